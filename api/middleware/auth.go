@@ -14,6 +14,8 @@ import (
 type Claims struct {
 	AdminID uint        `json:"admin_id"`
 	Role    models.Role `json:"role"`
+	// Permissions of the role at login; nil in tokens issued before permissions existed
+	Permissions []models.Permission `json:"permissions"`
 	jwt.RegisteredClaims
 }
 
@@ -50,29 +52,58 @@ func JWTAuth() echo.MiddlewareFunc {
 
 			c.Set(utils.CtxAdminIDKey, claims.AdminID)
 			c.Set(utils.CtxRoleKey, claims.Role)
+			perms := claims.Permissions
+			if perms == nil {
+				perms = models.DefaultPermissions(claims.Role)
+			}
+			c.Set(utils.CtxPermissionsKey, perms)
 
 			return next(c)
 		}
 	}
 }
 
-func RequireRole(allowed ...models.Role) echo.MiddlewareFunc {
-	allowedSet := map[models.Role]bool{}
-	for _, r := range allowed {
-		allowedSet[r] = true
+// RequirePermission allows the request only when the token holds every one of perms.
+func RequirePermission(perms ...models.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := utils.GetRole(c); !ok {
+				return utils.UnauthorizedResponse(c, "unauthorized")
+			}
+			for _, p := range perms {
+				if !utils.HasPermission(c, p) {
+					logrus.WithField("permission", p).Warn("forbidden permission")
+					return utils.ForbiddenResponse(c, "forbidden: missing permission "+string(p))
+				}
+			}
+			return next(c)
+		}
 	}
+}
 
+// RequireAnyPermission allows the request when the token holds at least one of perms, for
+// routes shared by several areas (e.g. notes of registrations and of contacts).
+func RequireAnyPermission(perms ...models.Permission) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			role, ok := utils.GetRole(c)
-			if !ok {
+			if _, ok := utils.GetRole(c); !ok {
 				return utils.UnauthorizedResponse(c, "unauthorized")
 			}
-			if !allowedSet[role] {
-				logrus.WithField("role", role).Warn("forbidden role")
-				return utils.ForbiddenResponse(c, "forbidden")
+			for _, p := range perms {
+				if utils.HasPermission(c, p) {
+					return next(c)
+				}
 			}
-			return next(c)
+			logrus.WithField("permissions", perms).Warn("forbidden permission")
+			return utils.ForbiddenResponse(c, "forbidden: missing one of permissions "+joinPermissions(perms))
 		}
 	}
 }
+
+func joinPermissions(perms []models.Permission) string {
+	names := make([]string, len(perms))
+	for i, p := range perms {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
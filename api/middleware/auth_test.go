@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"darulabror/internal/models"
+	"darulabror/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequireAnyPermission(t *testing.T) {
+	tests := []struct {
+		name  string
+		perms []models.Permission
+		want  int
+	}{
+		{name: "first", perms: []models.Permission{models.PermRegistrationsEdit}, want: http.StatusNoContent},
+		{name: "second", perms: []models.Permission{models.PermContactsReply}, want: http.StatusNoContent},
+		{name: "none", perms: []models.Permission{models.PermArticlesView}, want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/admin/notes/1", nil), rec)
+			c.Set(utils.CtxRoleKey, models.Role("editor"))
+			c.Set(utils.CtxPermissionsKey, tt.perms)
+
+			h := RequireAnyPermission(models.PermRegistrationsEdit, models.PermContactsReply)(func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			})
+			if err := h(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
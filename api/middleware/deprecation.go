@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Deprecated marks responses of a deprecated route: Deprecation (RFC 9745) carries when it
+// was deprecated and Sunset (RFC 8594) when it stops working.
+func Deprecated(deprecatedAt, sunset time.Time) echo.MiddlewareFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetValue := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			h := c.Response().Header()
+			h.Set("Deprecation", deprecation)
+			h.Set("Sunset", sunsetValue)
+			return next(c)
+		}
+	}
+}
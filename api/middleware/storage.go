@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"darulabror/internal/repository"
+	"darulabror/internal/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// SignedURL protects files served from a private local store.
+// Expects ?expires=<unix>&signature=<hex> produced by GenerateSignedURL.
+func SignedURL(store repository.LocalStorageRepo) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			method := c.Request().Method
+			if method == http.MethodHead {
+				method = http.MethodGet
+			}
+
+			err := store.VerifySignature(method, c.Param("*"), c.QueryParam("expires"), c.QueryParam("signature"))
+			if err != nil {
+				logrus.WithField("object", c.Param("*")).Warn("invalid storage signature")
+				return utils.ForbiddenResponse(c, "invalid or expired signature")
+			}
+			return next(c)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"darulabror/api/middleware"
 	"darulabror/internal/handler"
 	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
@@ -13,54 +15,193 @@ type Handlers struct {
 	Registration *handler.RegistrationHandler
 	Contact      *handler.ContactHandler
 	Admin        *handler.AdminHandler
+	Upload       *handler.UploadHandler
+	Export       *handler.ExportHandler
+	Import       *handler.ImportHandler
+	Period       *handler.AdmissionPeriodHandler
+	Selection    *handler.SelectionHandler
+	Document     *handler.DocumentHandler
+	Payment      *handler.PaymentHandler
+	Note         *handler.NoteHandler
+	Dashboard    *handler.DashboardHandler
+	Role         *handler.RoleHandler
+
+	// FakePayments serves GET /payments/fake/:number (PAYMENT_PROVIDER=fake only).
+	FakePayments bool
 }
 
-func Register(e *echo.Echo, h Handlers) {
+// registerV1 adds the /v1 routes; the unversioned aliases are the same routes.
+func registerV1(g *echo.Group, h Handlers) {
 	// ======================
 	// Public routes
 	// ======================
-	e.GET("/articles", h.Article.ListPublished)
-	e.GET("/articles/:id", h.Article.GetPublishedByID)
+	g.GET("/articles", h.Article.ListPublished)
+	g.GET("/articles/popular", h.Article.Popular)
+	g.GET("/articles/slug/:slug", h.Article.GetPublishedBySlug)
+	g.GET("/articles/:id", h.Article.GetPublishedByID)
+
+	g.GET("/admission-periods/current", h.Period.Current)
+	g.POST("/registrations", h.Registration.Create)
+	g.GET("/registrations/track", h.Document.Track)
+	g.POST("/registrations/edit-link", h.Registration.RequestEditLink)
+	g.GET("/registrations/edit", h.Registration.GetForEdit)
+	g.PUT("/registrations/edit", h.Registration.UpdateByApplicant)
+	g.GET("/documents/:token", h.Document.Download)
+	g.POST("/registrations/payment", h.Payment.CreatePayment)
+	g.GET("/registrations/payment", h.Payment.GetPayment)
+	g.POST("/registrations/payment/proof", h.Payment.UploadProof)
+	g.GET("/registrations/payment/proof", h.Payment.GetProof)
 
-	e.POST("/registrations", h.Registration.Create)
-	e.POST("/contacts", h.Contact.Create)
+	// payment gateway callbacks (verified by signature, not JWT)
+	g.POST("/payments/webhook/:provider", h.Payment.Webhook)
+	if h.FakePayments {
+		g.GET("/payments/fake/:number", h.Payment.FakePay)
+	}
+	g.POST("/contacts", h.Contact.Create)
 
 	// Admin login (public)
-	e.POST("/admin/login", h.Admin.Login)
+	g.POST("/admin/login", h.Admin.Login)
+	// second step with the mfa_token of /admin/login
+	g.POST("/admin/login/mfa", h.Admin.LoginMFA)
+	g.POST("/admin/login/mfa/setup", h.Admin.LoginMFASetup)
+	g.POST("/admin/login/mfa/enroll", h.Admin.LoginMFAEnroll)
 
 	// ======================
-	// Admin routes (/admin)
+	// Admin routes (/v1/admin)
 	// ======================
-	admin := e.Group("/admin", middleware.JWTAuth(), middleware.RequireRole(models.Admins, models.Superadmin))
+	admin := g.Group("/admin", middleware.JWTAuth())
+	can := middleware.RequirePermission
+	canAny := middleware.RequireAnyPermission
+	// notes and assignees belong to registrations and contacts, so either area's editors may use them
+	noteEditor := canAny(models.PermRegistrationsEdit, models.PermContactsReply)
 
+	// any authenticated admin
 	admin.GET("/profile", h.Admin.Profile)
 	admin.PATCH("/profile/password", h.Admin.ChangePassword)
+	admin.GET("/profile/mfa", h.Admin.MFAStatus)
+	admin.POST("/profile/mfa/setup", h.Admin.MFASetup)
+	admin.POST("/profile/mfa/enable", h.Admin.MFAEnable)
+	admin.POST("/profile/mfa/recovery-codes", h.Admin.MFARecoveryCodes)
+	admin.POST("/profile/mfa/disable", h.Admin.MFADisable)
+	admin.GET("/dashboard/stats", h.Dashboard.Stats, can(models.PermDashboardView))
+
+	// manage articles (publishing is checked by the service: articles.publish)
+	admin.GET("/articles", h.Article.AdminListAll, can(models.PermArticlesView))
+	admin.POST("/articles", h.Article.AdminCreate, can(models.PermArticlesWrite))
+	admin.PUT("/articles/:id", h.Article.AdminUpdate, can(models.PermArticlesWrite))
+	admin.DELETE("/articles/:id", h.Article.AdminDelete, can(models.PermArticlesDelete))
+	admin.GET("/articles/:id/analytics", h.Article.AdminAnalytics, can(models.PermArticlesView))
+	admin.GET("/articles/:id/translations", h.Article.AdminTranslations, can(models.PermArticlesView))
+	admin.PUT("/articles/:id/translations/:locale", h.Article.AdminSaveTranslation, can(models.PermArticlesWrite))
+	admin.DELETE("/articles/:id/translations/:locale", h.Article.AdminDeleteTranslation, can(models.PermArticlesWrite))
 
-	// manage articles
-	admin.GET("/articles", h.Article.AdminListAll)
-	admin.POST("/articles", h.Article.AdminCreate)
-	admin.PUT("/articles/:id", h.Article.AdminUpdate)
-	admin.DELETE("/articles/:id", h.Article.AdminDelete)
+	// direct-to-bucket uploads (large media)
+	admin.POST("/uploads", h.Upload.Create, can(models.PermArticlesWrite))
+	admin.POST("/uploads/:id/complete", h.Upload.Complete, can(models.PermArticlesWrite))
 
 	// manage registrations
-	admin.GET("/registrations", h.Registration.AdminList)
-	admin.GET("/registrations/:id", h.Registration.AdminGetByID)
-	admin.PATCH("/registrations/:id/status", h.Registration.AdminUpdateStatus)
-	admin.DELETE("/registrations/:id", h.Registration.AdminDelete)
+	admin.GET("/registrations", h.Registration.AdminList, can(models.PermRegistrationsView))
+	admin.GET("/registrations/export", h.Export.Registrations, can(models.PermRegistrationsExport))
+	admin.POST("/registrations/import", h.Import.Registrations, can(models.PermRegistrationsImport))
+	admin.GET("/registrations/:id", h.Registration.AdminGetByID, can(models.PermRegistrationsView))
+	admin.GET("/registrations/:id/history", h.Registration.AdminStatusHistory, can(models.PermRegistrationsView))
+	admin.GET("/registrations/:id/changes", h.Registration.AdminChanges, can(models.PermRegistrationsView))
+	admin.PUT("/registrations/:id", h.Registration.AdminUpdate, can(models.PermRegistrationsEdit))
+	admin.PATCH("/registrations/:id", h.Registration.AdminPatch, can(models.PermRegistrationsEdit))
+	admin.PATCH("/registrations/:id/status", h.Registration.AdminUpdateStatus, can(models.PermRegistrationsEdit))
+	admin.DELETE("/registrations/:id", h.Registration.AdminDelete, can(models.PermRegistrationsDelete))
+	admin.GET("/exports/:id", h.Export.GetJob, can(models.PermRegistrationsExport))
+
+	// registration cards and decision letters
+	admin.GET("/registrations/:id/documents", h.Document.RegistrationDocuments, can(models.PermRegistrationsView))
+	admin.POST("/admission-periods/:id/documents", h.Document.BulkDocuments, can(models.PermRegistrationsExport))
+	admin.GET("/registrations/:id/invoices", h.Payment.AdminInvoices, can(models.PermRegistrationsView))
+
+	// bank transfer verification
+	admin.GET("/payments/pending", h.Payment.PendingProofs, can(models.PermPaymentsVerify))
+	admin.POST("/payments/:id/approve", h.Payment.ApproveProof, can(models.PermPaymentsVerify))
+	admin.POST("/payments/:id/reject", h.Payment.RejectProof, can(models.PermPaymentsVerify))
+
+	// selection tests
+	admin.GET("/selection-sessions", h.Selection.ListSessions, can(models.PermSelectionManage))
+	admin.POST("/selection-sessions", h.Selection.CreateSession, can(models.PermSelectionManage))
+	admin.GET("/selection-sessions/:id", h.Selection.GetSession, can(models.PermSelectionManage))
+	admin.PUT("/selection-sessions/:id", h.Selection.UpdateSession, can(models.PermSelectionManage))
+	admin.DELETE("/selection-sessions/:id", h.Selection.DeleteSession, can(models.PermSelectionManage))
+	admin.GET("/selection-sessions/:id/candidates", h.Selection.SessionCandidates, can(models.PermSelectionManage))
+	admin.POST("/selection-sessions/:id/assignments", h.Selection.Assign, can(models.PermSelectionManage))
+	admin.DELETE("/selection-sessions/:id/assignments/:registration_id", h.Selection.Unassign, can(models.PermSelectionManage))
+	admin.GET("/registrations/:id/scores", h.Selection.Scores, can(models.PermRegistrationsView))
+	admin.PUT("/registrations/:id/scores", h.Selection.SaveScores, can(models.PermRegistrationsEdit))
+	admin.GET("/admission-periods/:id/ranking", h.Selection.Ranking, can(models.PermSelectionManage))
+	admin.POST("/admission-periods/:id/ranking/apply", h.Selection.ApplyRanking, can(models.PermSelectionManage))
 
 	// manage contacts
-	admin.GET("/contacts", h.Contact.AdminList)
-	admin.GET("/contacts/:id", h.Contact.AdminGetByID)
-	admin.PUT("/contacts/:id", h.Contact.AdminUpdate)
-	admin.PATCH("/contacts/:id/status", h.Contact.AdminUpdateStatus)
-	admin.DELETE("/contacts/:id", h.Contact.AdminDelete)
+	admin.GET("/contacts", h.Contact.AdminList, can(models.PermContactsView))
+	admin.GET("/contacts/:id", h.Contact.AdminGetByID, can(models.PermContactsView))
+	admin.PUT("/contacts/:id", h.Contact.AdminUpdate, can(models.PermContactsReply))
+	admin.PATCH("/contacts/:id/status", h.Contact.AdminUpdateStatus, can(models.PermContactsReply))
+	admin.DELETE("/contacts/:id", h.Contact.AdminDelete, can(models.PermContactsDelete))
 
-	// ======================
-	// Superadmin-only routes
-	// ======================
-	super := admin.Group("", middleware.RequireRole(models.Superadmin))
-	super.POST("/admins", h.Admin.Create)
-	super.GET("/admins", h.Admin.List)
-	super.PUT("/admins/:id", h.Admin.Update)
-	super.DELETE("/admins/:id", h.Admin.Delete)
+	// internal notes and assignments (deleting others' notes is checked by the service: notes.delete)
+	admin.GET("/registrations/:id/notes", h.Note.RegistrationNotes, can(models.PermRegistrationsView))
+	admin.POST("/registrations/:id/notes", h.Note.AddRegistrationNote, can(models.PermRegistrationsEdit))
+	admin.PUT("/registrations/:id/assignee", h.Note.AssignRegistration, can(models.PermRegistrationsEdit))
+	admin.GET("/contacts/:id/notes", h.Note.ContactNotes, can(models.PermContactsView))
+	admin.POST("/contacts/:id/notes", h.Note.AddContactNote, can(models.PermContactsReply))
+	admin.PUT("/contacts/:id/assignee", h.Note.AssignContact, can(models.PermContactsReply))
+	admin.GET("/assignees", h.Note.Assignees, noteEditor)
+	admin.DELETE("/notes/:id", h.Note.DeleteNote, noteEditor)
+
+	// admins and roles
+	admin.POST("/admins", h.Admin.Create, can(models.PermAdminsManage))
+	admin.GET("/admins", h.Admin.List, can(models.PermAdminsManage))
+	admin.PUT("/admins/:id", h.Admin.Update, can(models.PermAdminsManage))
+	admin.DELETE("/admins/:id", h.Admin.Delete, can(models.PermAdminsManage))
+	admin.DELETE("/admins/:id/mfa", h.Admin.ResetMFA, can(models.PermAdminsManage))
+
+	admin.GET("/permissions", h.Role.Permissions, can(models.PermRolesManage))
+	admin.GET("/roles", h.Role.List, can(models.PermRolesManage))
+	admin.POST("/roles", h.Role.Create, can(models.PermRolesManage))
+	admin.GET("/roles/:name", h.Role.Get, can(models.PermRolesManage))
+	admin.PUT("/roles/:name", h.Role.Update, can(models.PermRolesManage))
+	admin.DELETE("/roles/:name", h.Role.Delete, can(models.PermRolesManage))
+
+	// admission setup
+	admin.GET("/admission-periods", h.Period.List, can(models.PermAdmissionPeriodsManage))
+	admin.POST("/admission-periods", h.Period.Create, can(models.PermAdmissionPeriodsManage))
+	admin.GET("/admission-periods/:id", h.Period.GetByID, can(models.PermAdmissionPeriodsManage))
+	admin.PUT("/admission-periods/:id", h.Period.Update, can(models.PermAdmissionPeriodsManage))
+	admin.DELETE("/admission-periods/:id", h.Period.Delete, can(models.PermAdmissionPeriodsManage))
+	admin.GET("/admission-periods/:id/quotas", h.Period.Quotas, can(models.PermAdmissionPeriodsManage))
+	admin.PUT("/admission-periods/:id/quotas", h.Period.SetQuotas, can(models.PermAdmissionPeriodsManage))
+	admin.GET("/admission-periods/:id/selection-components", h.Selection.Components, can(models.PermAdmissionPeriodsManage))
+	admin.PUT("/admission-periods/:id/selection-components", h.Selection.SetComponents, can(models.PermAdmissionPeriodsManage))
+	admin.GET("/letter-templates", h.Document.LetterTemplates, can(models.PermLetterTemplatesManage))
+	admin.PUT("/letter-templates/:kind", h.Document.UpdateLetterTemplate, can(models.PermLetterTemplatesManage))
+}
+
+// directUploadPaths holds the local PUT routes that skip the global body limit.
+var directUploadPaths = map[string]bool{}
+
+// RegisterLocalStorage serves files of a local object store (STORAGE_DRIVER=local).
+// Private stores only serve requests carrying a valid signed URL; uploads always need one.
+func RegisterLocalStorage(e *echo.Echo, store repository.LocalStorageRepo) {
+	signed := middleware.SignedURL(store)
+
+	g := e.Group(store.RoutePrefix())
+	if !store.IsPublic() {
+		g.Use(signed)
+	}
+	g.Static("/", store.BaseDir())
+
+	uploadPath := store.RoutePrefix() + "/*"
+	e.PUT(uploadPath, handler.NewLocalStorageHandler(store).Put, signed)
+	directUploadPaths[uploadPath] = true
+}
+
+// IsDirectUpload reports whether the request is a signed PUT to a local store,
+// which (like a real bucket) is not subject to the API body limit.
+func IsDirectUpload(c echo.Context) bool {
+	return c.Request().Method == http.MethodPut && directUploadPaths[c.Path()]
 }
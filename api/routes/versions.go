@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"darulabror/api/middleware"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Version is one API version mounted under its own prefix.
+type Version struct {
+	// Name is also the Swagger instance of the version (docs/<name>).
+	Name     string
+	Prefix   string
+	Register func(g *echo.Group, h Handlers)
+}
+
+// Versions are served side by side. A breaking change goes into a new version whose Register
+// calls registerV1 first and then re-registers only the changed routes; Echo keeps the last
+// handler added for a method and path, so the rest of v1 carries over unchanged.
+var Versions = []Version{
+	{Name: "v1", Prefix: "/v1", Register: registerV1},
+}
+
+// LegacyDeprecatedAt is when the unversioned routes were superseded by /v1.
+var LegacyDeprecatedAt = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+// Legacy controls the unversioned aliases of v1 kept for clients that predate /v1.
+type Legacy struct {
+	Enabled bool
+	Sunset  time.Time
+}
+
+// Register mounts every API version and, when enabled, the unversioned aliases of v1.
+func Register(e *echo.Echo, h Handlers, legacy Legacy) {
+	for _, v := range Versions {
+		v.Register(e.Group(v.Prefix), h)
+	}
+
+	if legacy.Enabled {
+		e.Pre(legacyAlias(apiRoots(e, "/v1"), middleware.Deprecated(LegacyDeprecatedAt, legacy.Sunset)))
+	}
+}
+
+// apiRoots returns the first path segments of the routes under prefix, e.g. "articles".
+func apiRoots(e *echo.Echo, prefix string) map[string]bool {
+	roots := map[string]bool{}
+	for _, r := range e.Routes() {
+		rest, ok := strings.CutPrefix(r.Path, prefix+"/")
+		if !ok {
+			continue
+		}
+		root, _, _ := strings.Cut(rest, "/")
+		if root != "" && root != "*" {
+			roots[root] = true
+		}
+	}
+	return roots
+}
+
+// legacyAlias serves an unversioned API path by its /v1 route (the path is rewritten before
+// routing, so both share one route table) and marks the response as deprecated.
+func legacyAlias(roots map[string]bool, deprecated echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		aliased := deprecated(next)
+		return func(c echo.Context) error {
+			req := c.Request()
+			root, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+			if !roots[root] {
+				return next(c)
+			}
+
+			req.URL.Path = "/v1" + req.URL.Path
+			if req.URL.RawPath != "" {
+				req.URL.RawPath = "/v1" + req.URL.RawPath
+			}
+			c.Response().Header().Add("Link", `<`+req.URL.Path+`>; rel="successor-version"`)
+			return aliased(c)
+		}
+	}
+}
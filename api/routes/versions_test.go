@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"darulabror/api/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestLegacyAlias(t *testing.T) {
+	e := echo.New()
+	ok := func(c echo.Context) error { return c.String(http.StatusOK, c.Path()) }
+	v1 := e.Group("/v1")
+	v1.GET("/articles/:id", ok)
+	v1.POST("/admin/login", ok)
+	e.GET("/healthz", ok)
+
+	sunset := time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC)
+	e.Pre(legacyAlias(apiRoots(e, "/v1"), middleware.Deprecated(LegacyDeprecatedAt, sunset)))
+
+	cases := []struct {
+		method, path string
+		route        string
+		deprecated   bool
+	}{
+		{http.MethodGet, "/articles/7", "/v1/articles/:id", true},
+		{http.MethodPost, "/admin/login", "/v1/admin/login", true},
+		{http.MethodGet, "/v1/articles/7", "/v1/articles/:id", false},
+		{http.MethodGet, "/healthz", "/healthz", false},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+
+		if rec.Code != http.StatusOK || rec.Body.String() != tc.route {
+			t.Errorf("%s %s: got %d %q, want route %q", tc.method, tc.path, rec.Code, rec.Body.String(), tc.route)
+		}
+		if got := rec.Header().Get("Deprecation") != ""; got != tc.deprecated {
+			t.Errorf("%s %s: deprecated = %v, want %v", tc.method, tc.path, got, tc.deprecated)
+		}
+		if tc.deprecated {
+			if got := rec.Header().Get("Sunset"); got != "Sun, 18 Apr 2027 00:00:00 GMT" {
+				t.Errorf("%s %s: Sunset = %q", tc.method, tc.path, got)
+			}
+			if got, want := rec.Header().Get("Link"), `<`+"/v1"+tc.path+`>; rel="successor-version"`; got != want {
+				t.Errorf("%s %s: Link = %q, want %q", tc.method, tc.path, got, want)
+			}
+		}
+	}
+}
@@ -3,7 +3,7 @@ package main
 // @title Darul Abror API
 // @version 1.0
 // @description Darul Abror backend API (public + admin).
-// @BasePath /
+// @BasePath /v1
 // @schemes https http
 //
 // @securityDefinitions.apikey BearerAuth
@@ -15,17 +15,24 @@ import (
 	"context"
 	"darulabror/api/routes"
 	"darulabror/config"
-	_ "darulabror/docs"
+	_ "darulabror/docs/v1"
 	"darulabror/internal/handler"
 	"darulabror/internal/repository"
 	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"reflect"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
-	"cloud.google.com/go/storage"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	echomw "github.com/labstack/echo/v4/middleware"
@@ -54,6 +61,30 @@ func parseCORSOrigins(corsOrigins string) []string {
 	return allowOrigins
 }
 
+// newIPExtractor decides where c.RealIP() comes from. Without trusted proxies it is the TCP peer,
+// so a client cannot pick its own address with X-Forwarded-For. With trustedProxies (comma-separated
+// CIDRs) it is the right-most X-Forwarded-For entry that is not one of those proxies.
+func newIPExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	var ranges []echo.TrustOption
+	for _, c := range strings.Split(trustedProxies, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", c, err)
+		}
+		ranges = append(ranges, echo.TrustIPRange(ipNet))
+	}
+	if len(ranges) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	// echo trusts loopback and private ranges by default; only the listed proxies are trusted here
+	opts := append([]echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}, ranges...)
+	return echo.ExtractIPFromXFFHeader(opts...), nil
+}
+
 // appendUniqueOrigins appends new origins to the existing list, avoiding duplicates.
 func appendUniqueOrigins(existing, newOrigins []string) []string {
 	// Create a map of existing origins for quick lookup
@@ -84,14 +115,25 @@ func main() {
 	// Core (Echo + middleware)
 	// ======================
 	e := echo.New()
+	// every error returned by handlers and middleware ends in the shared error envelope
+	e.HTTPErrorHandler = utils.HTTPErrorHandler
 	e.HideBanner = true
 	e.Logger.SetOutput(os.Stdout)
+	ipExtractor, err := newIPExtractor(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
+	e.IPExtractor = ipExtractor
 
 	e.Use(echomw.RequestID())
 	e.Use(echomw.Recover())
 
-	// Limit request body (protect from huge uploads)
-	e.Use(echomw.BodyLimit("20M"))
+	// Limit request body (protect from huge uploads).
+	// Large media goes directly to the bucket via POST /admin/uploads instead.
+	e.Use(echomw.BodyLimitWithConfig(echomw.BodyLimitConfig{
+		Limit:   "20M",
+		Skipper: routes.IsDirectUpload,
+	}))
 
 	// CORS (frontend origins) - REQUIRED for production
 	corsOrigins := strings.TrimSpace(os.Getenv("CORS_ORIGINS"))
@@ -118,6 +160,7 @@ func main() {
 			echo.GET,
 			echo.POST,
 			echo.PUT,
+			echo.PATCH,
 			echo.DELETE,
 			echo.OPTIONS,
 		},
@@ -126,6 +169,17 @@ func main() {
 			echo.HeaderContentType,
 			echo.HeaderAccept,
 			echo.HeaderAuthorization,
+			echo.HeaderIfModifiedSince,
+			"If-None-Match",
+		},
+		// readable by the frontend for its own conditional requests and to notice
+		// it still calls the deprecated unversioned routes
+		ExposeHeaders: []string{
+			"ETag",
+			echo.HeaderLastModified,
+			"Deprecation",
+			"Sunset",
+			"Link",
 		},
 		AllowCredentials: false,
 	}))
@@ -145,6 +199,9 @@ func main() {
 		}
 		return name
 	})
+	if err := utils.RegisterValidationTranslations(v); err != nil {
+		log.Fatalf("failed to register validation translations: %v", err)
+	}
 	e.Validator = &CustomValidator{v: v}
 
 	// ======================
@@ -158,21 +215,15 @@ func main() {
 	}
 
 	// ======================
-	// GCS (bucket)
+	// Object storage (STORAGE_DRIVER=gcs|local|s3)
 	// ======================
-	publicBucket := os.Getenv("PUBLIC_BUCKET")
-
-	var gcsClient *storage.Client
-	if publicBucket != "" {
-		var err error
-		gcsClient, err = storage.NewClient(ctx)
-		if err != nil {
-			log.Fatalf("failed to init gcs client: %v", err)
-		}
-	}
-
 	// Always inject (repo will return ErrStorageNotConfigured if not configured)
-	publicStore := repository.NewGCPStorageRepo(gcsClient, publicBucket, true)
+	publicStore := config.ConnectionStorage(ctx, os.Getenv("PUBLIC_BUCKET"), true)
+	// Private bucket (exports, personal documents) — only reachable via signed URLs
+	privateStore := config.ConnectionStorage(ctx, os.Getenv("PRIVATE_BUCKET"), false)
+
+	// Email notifications to applicants (SMTP_HOST; logged only when empty)
+	mailer := config.ConnectionMailer()
 
 	// ======================
 	// Repositories
@@ -181,29 +232,78 @@ func main() {
 	regRepo := repository.NewRegistrationRepo(db)
 	contactRepo := repository.NewContactRepository(db)
 	adminRepo := repository.NewAdminRepository(db)
+	uploadRepo := repository.NewMediaUploadRepo(db)
+	exportJobRepo := repository.NewExportJobRepo(db)
+	periodRepo := repository.NewAdmissionPeriodRepo(db)
+	quotaRepo := repository.NewAdmissionQuotaRepo(db)
+	selectionRepo := repository.NewSelectionRepo(db)
+	letterRepo := repository.NewLetterTemplateRepo(db)
+	paymentRepo := repository.NewPaymentRepo(db)
+	noteRepo := repository.NewAdminNoteRepo(db)
+	articleViewRepo := repository.NewArticleViewRepo(db)
+	translationRepo := repository.NewArticleTranslationRepo(db)
+	roleRepo := repository.NewRoleRepo(db)
+	adminMFARepo := repository.NewAdminMFARepo(db)
+
+	// Cached reads (CACHE_DRIVER=memory|redis|none); writes through the decorators invalidate by tag
+	readCache := config.ConnectionCache(ctx)
+	if readCache != nil {
+		cacheTTL := config.CacheTTL()
+		articleRepo = repository.NewCachedArticleRepo(articleRepo, readCache, cacheTTL)
+		adminRepo = repository.NewCachedAdminRepository(adminRepo, readCache, cacheTTL)
+	}
 
 	// ======================
 	// Services
 	// ======================
-	articleSvc := service.NewArticleService(articleRepo, publicStore)
-	regSvc := service.NewRegistrationService(regRepo)
+	articleSvc := service.NewArticleService(articleRepo, translationRepo, publicStore)
+	// buffered in memory and written in batches; flushed on shutdown
+	articleViewSvc := service.NewArticleViewService(articleViewRepo, articleRepo, config.LoadArticleViewConfig())
+	docSvc := service.NewDocumentService(config.LoadDocumentConfig(jwtSecret), regRepo, periodRepo, letterRepo, exportJobRepo, privateStore)
+	regSvc := service.NewRegistrationService(regRepo, periodRepo, quotaRepo, docSvc, config.LoadRegistrationEditConfig(jwtSecret), mailer)
 	contactSvc := service.NewContactService(contactRepo)
-	adminSvc := service.NewAdminService(adminRepo, jwtSecret)
+	adminSvc := service.NewAdminService(adminRepo, roleRepo, adminMFARepo, jwtSecret, config.LoadMFAConfig(jwtSecret))
+	uploadSvc := service.NewUploadService(uploadRepo, publicStore)
+	exportSvc := service.NewExportService(regRepo, exportJobRepo, privateStore)
+	importSvc := service.NewImportService(regRepo, periodRepo, quotaRepo, v)
+	periodSvc := service.NewAdmissionPeriodService(periodRepo, quotaRepo)
+	selectionSvc := service.NewSelectionService(selectionRepo, regRepo, periodRepo, quotaRepo)
+	paymentSvc := service.NewPaymentService(config.LoadPaymentConfig(), paymentRepo, regRepo, periodRepo, privateStore, mailer)
+	noteSvc := service.NewNoteService(noteRepo, adminRepo)
+	dashboardSvc := service.NewDashboardService(regRepo, contactRepo, articleRepo, readCache)
+	roleSvc := service.NewRoleService(roleRepo)
 
 	// ======================
 	// Handlers
 	// ======================
 	h := routes.Handlers{
-		Article:      handler.NewArticleHandler(articleSvc),
+		Article:      handler.NewArticleHandler(articleSvc, articleViewSvc),
 		Registration: handler.NewRegistrationHandler(regSvc),
 		Contact:      handler.NewContactHandler(contactSvc),
 		Admin:        handler.NewAdminHandler(adminSvc),
+		Upload:       handler.NewUploadHandler(uploadSvc),
+		Export:       handler.NewExportHandler(exportSvc),
+		Import:       handler.NewImportHandler(importSvc),
+		Period:       handler.NewAdmissionPeriodHandler(periodSvc),
+		Selection:    handler.NewSelectionHandler(selectionSvc),
+		Document:     handler.NewDocumentHandler(docSvc),
+		Payment:      handler.NewPaymentHandler(paymentSvc),
+		Note:         handler.NewNoteHandler(noteSvc),
+		Dashboard:    handler.NewDashboardHandler(dashboardSvc),
+		Role:         handler.NewRoleHandler(roleSvc),
+		FakePayments: config.PaymentProvider() == config.PaymentProviderFake,
 	}
 
 	// ======================
 	// Routes
 	// ======================
-	routes.Register(e, h)
+	routes.Register(e, h, config.LoadLegacyRoutes())
+
+	for _, store := range []repository.ObjectStore{publicStore, privateStore} {
+		if local, ok := store.(repository.LocalStorageRepo); ok {
+			routes.RegisterLocalStorage(e, local)
+		}
+	}
 
 	// ======================
 	// Health check (Cloud Run)
@@ -220,8 +320,11 @@ func main() {
 		return c.String(200, "Darul Abror API")
 	})
 
-	// Swagger UI
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	// Swagger UI, one document per API version (/swagger/ keeps showing v1)
+	for _, v := range routes.Versions {
+		e.GET("/swagger/"+v.Name+"/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName(v.Name)))
+	}
+	e.GET("/swagger/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("v1")))
 
 	// ======================
 	// Start
@@ -234,8 +337,37 @@ func main() {
 	log.Printf("starting server on :%s", port)
 	log.Printf("swagger UI: /swagger/index.html")
 
-	if err := e.Start(":" + port); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	go func() {
+		if err := e.Start(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start server: %v", err)
+		}
+	}()
+
+	// ======================
+	// Graceful shutdown (Cloud Run sends SIGTERM, then kills after 10s)
+	// ======================
+	sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-sigCtx.Done()
+
+	log.Printf("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to stop server: %v", err)
+	}
+	// after the server, so views of the last requests are written too
+	if err := articleViewSvc.Close(shutdownCtx); err != nil {
+		log.Printf("failed to write article views: %v", err)
+	}
+	if err := exportSvc.Close(shutdownCtx); err != nil {
+		log.Printf("failed to finish export jobs: %v", err)
+	}
+	if err := docSvc.Close(shutdownCtx); err != nil {
+		log.Printf("failed to finish document jobs: %v", err)
+	}
+	if err := paymentSvc.Close(shutdownCtx); err != nil {
+		log.Printf("failed to send payment emails: %v", err)
 	}
-	_ = ctx
 }
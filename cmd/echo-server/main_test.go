@@ -1,8 +1,16 @@
 package main
 
 import (
+	"context"
+	"darulabror/internal/repository"
+	"darulabror/internal/service"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
+
+	"github.com/labstack/echo/v4"
 )
 
 func TestParseCORSOrigins(t *testing.T) {
@@ -107,3 +115,84 @@ func TestAppendUniqueOrigins(t *testing.T) {
 		})
 	}
 }
+
+type fakeArticleViewRepo struct {
+	repository.ArticleViewRepo
+	mu    sync.Mutex
+	views int64
+}
+
+func (r *fakeArticleViewRepo) AddViews(counts []repository.ArticleViewCount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range counts {
+		r.views += c.Views
+	}
+	return nil
+}
+
+func TestSpoofedForwardedForIsSameVisitor(t *testing.T) {
+	const ua = "Mozilla/5.0 (Linux; Android 14) Chrome/124.0 Mobile Safari"
+	tests := []struct {
+		name           string
+		trustedProxies string
+		remoteAddr     string
+		xff            []string
+	}{
+		{
+			name:       "no trusted proxy",
+			remoteAddr: "203.0.113.7:51000",
+			xff:        []string{"", "198.51.100.1", "198.51.100.2, 198.51.100.3"},
+		},
+		{
+			name:           "behind a trusted proxy",
+			trustedProxies: "35.191.0.0/16",
+			remoteAddr:     "35.191.4.2:443",
+			// the proxy appends the real client; anything before it was sent by the client
+			xff: []string{"203.0.113.7", "198.51.100.1, 203.0.113.7", "10.0.0.1, 198.51.100.2, 203.0.113.7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := newIPExtractor(tt.trustedProxies)
+			if err != nil {
+				t.Fatalf("newIPExtractor() error = %v", err)
+			}
+			repo := &fakeArticleViewRepo{}
+			views := service.NewArticleViewService(repo, nil, service.ArticleViewConfig{})
+
+			e := echo.New()
+			e.IPExtractor = extractor
+			e.GET("/articles/:slug", func(c echo.Context) error {
+				if got := c.RealIP(); got != "203.0.113.7" {
+					t.Errorf("RealIP() = %q, want 203.0.113.7", got)
+				}
+				views.Record(1, c.RealIP(), c.Request().UserAgent())
+				return c.NoContent(http.StatusOK)
+			})
+			for _, xff := range tt.xff {
+				req := httptest.NewRequest(http.MethodGet, "/articles/a", nil)
+				req.RemoteAddr = tt.remoteAddr
+				req.Header.Set("User-Agent", ua)
+				if xff != "" {
+					req.Header.Set(echo.HeaderXForwardedFor, xff)
+				}
+				e.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			if err := views.Close(context.Background()); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if repo.views != 1 {
+				t.Errorf("views = %d, want 1 (a spoofed X-Forwarded-For is not a new visitor)", repo.views)
+			}
+		})
+	}
+}
+
+func TestNewIPExtractorInvalidCIDR(t *testing.T) {
+	if _, err := newIPExtractor("35.191.0.0/16, not-a-cidr"); err == nil {
+		t.Error("newIPExtractor() error = nil, want an invalid CIDR error")
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"darulabror/api/routes"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultLegacySunset gives clients six months after /v1 to move off the unversioned routes.
+var defaultLegacySunset = routes.LegacyDeprecatedAt.AddDate(0, 6, 0)
+
+// LoadLegacyRoutes reads whether the unversioned route aliases are served (LEGACY_ROUTES=off
+// drops them) and the Sunset date they announce (LEGACY_ROUTES_SUNSET, YYYY-MM-DD).
+func LoadLegacyRoutes() routes.Legacy {
+	legacy := routes.Legacy{
+		Enabled: !strings.EqualFold(strings.TrimSpace(os.Getenv("LEGACY_ROUTES")), "off"),
+		Sunset:  defaultLegacySunset,
+	}
+	if v := strings.TrimSpace(os.Getenv("LEGACY_ROUTES_SUNSET")); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			log.Fatalf("invalid LEGACY_ROUTES_SUNSET %q (expected YYYY-MM-DD)", v)
+		}
+		legacy.Sunset = t
+	}
+	return legacy
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"darulabror/internal/service"
+	"log"
+	"os"
+	"time"
+)
+
+// LoadArticleViewConfig reads how often article views are written and how long repeat views are ignored.
+func LoadArticleViewConfig() service.ArticleViewConfig {
+	var cfg service.ArticleViewConfig
+	if v := os.Getenv("ARTICLE_VIEW_FLUSH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid ARTICLE_VIEW_FLUSH_INTERVAL %q (expected a duration like 30s)", v)
+		}
+		cfg.FlushInterval = d
+	}
+	if v := os.Getenv("ARTICLE_VIEW_DEDUP_WINDOW"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid ARTICLE_VIEW_DEDUP_WINDOW %q (expected a duration like 30m)", v)
+		}
+		cfg.DedupWindow = d
+	}
+	return cfg
+}
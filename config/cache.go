@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"darulabror/internal/cache"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	CacheDriverMemory = "memory"
+	CacheDriverRedis  = "redis"
+	CacheDriverNone   = "none"
+)
+
+// ConnectionCache builds the cache of hot reads selected by CACHE_DRIVER (memory|redis|none, default memory).
+// It returns nil for none. The memory cache is per instance, so with several instances
+// a write is only seen by the others after CACHE_TTL; use redis to share invalidations.
+func ConnectionCache(ctx context.Context) cache.Cache {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("CACHE_DRIVER")))
+	switch driver {
+	case "", CacheDriverMemory:
+		maxEntries := 10000
+		if v := os.Getenv("CACHE_MAX_ENTRIES"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				log.Fatalf("invalid CACHE_MAX_ENTRIES %q", v)
+			}
+			maxEntries = n
+		}
+		return cache.NewMemory(maxEntries)
+
+	case CacheDriverRedis:
+		url := os.Getenv("REDIS_URL")
+		if url == "" {
+			log.Fatal("REDIS_URL is required when CACHE_DRIVER=redis, e.g. redis://:password@10.0.0.3:6379/0")
+		}
+		opts, err := redis.ParseURL(url)
+		if err != nil {
+			log.Fatalf("invalid REDIS_URL: %v", err)
+		}
+		client := redis.NewClient(opts)
+
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := client.Ping(pingCtx).Err(); err != nil {
+			// not fatal: the cache is best effort and the API works without it
+			log.Printf("redis cache unreachable at startup: %v", err)
+		}
+		log.Printf("redis cache enabled: %s", opts.Addr)
+		return cache.NewRedis(client, envOr("CACHE_KEY_PREFIX", "darulabror:"))
+
+	case CacheDriverNone:
+		log.Printf("CACHE_DRIVER=none: reads are not cached")
+		return nil
+
+	default:
+		log.Fatalf("invalid CACHE_DRIVER %q (expected memory, redis or none)", driver)
+		return nil
+	}
+}
+
+// CacheTTL is how long cached reads are kept (CACHE_TTL, default 1m).
+func CacheTTL() time.Duration {
+	v := os.Getenv("CACHE_TTL")
+	if v == "" {
+		return time.Minute
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl <= 0 {
+		log.Fatalf("invalid CACHE_TTL %q (expected a duration like 1m)", v)
+	}
+	return ttl
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"darulabror/internal/service"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// LoadDocumentConfig reads the letterhead and download-link settings of generated PDFs.
+// Without DOCUMENT_SIGNING_KEY the links are signed with fallbackKey (JWT_SECRET).
+func LoadDocumentConfig(fallbackKey string) service.DocumentConfig {
+	cfg := service.DocumentConfig{
+		Letterhead: service.Letterhead{
+			SchoolName:     envOr("SCHOOL_NAME", "Pondok Pesantren Darul Abror"),
+			Address:        os.Getenv("SCHOOL_ADDRESS"),
+			Contact:        os.Getenv("SCHOOL_CONTACT"),
+			City:           envOr("SCHOOL_CITY", "Kediri"),
+			SignatoryName:  envOr("LETTER_SIGNATORY_NAME", "Panitia PSB"),
+			SignatoryTitle: envOr("LETTER_SIGNATORY_TITLE", "Ketua Panitia Penerimaan Santri Baru"),
+			LogoPath:       os.Getenv("LETTERHEAD_LOGO"),
+		},
+		SigningKey: []byte(envOr("DOCUMENT_SIGNING_KEY", fallbackKey)),
+		BaseURL:    APIBaseURL(),
+	}
+
+	if v := os.Getenv("DOCUMENT_LINK_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("invalid DOCUMENT_LINK_TTL %q (expected a duration like 720h)", v)
+		}
+		cfg.LinkTTL = ttl
+	}
+	if cfg.Letterhead.LogoPath != "" {
+		if _, err := os.Stat(cfg.Letterhead.LogoPath); err != nil {
+			log.Fatalf("LETTERHEAD_LOGO: %v", err)
+		}
+	}
+	return cfg
+}
+
+// APIBaseURL is the public URL of this API (API_BASE_URL), used in links sent to applicants.
+func APIBaseURL() string {
+	return envOr("API_BASE_URL", "http://localhost:"+envOr("PORT", "8080"))
+}
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
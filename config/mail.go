@@ -0,0 +1,30 @@
+package config
+
+import (
+	"darulabror/internal/repository"
+	"log"
+	"os"
+	"strconv"
+)
+
+// ConnectionMailer returns an SMTP mailer, or a mailer that only logs when SMTP_HOST is empty.
+func ConnectionMailer() repository.Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Printf("SMTP_HOST is empty: emails are only logged")
+		return repository.NewLogMailer()
+	}
+
+	port := 587
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		var err error
+		if port, err = strconv.Atoi(v); err != nil {
+			log.Fatalf("invalid SMTP_PORT %q", v)
+		}
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		log.Fatal("SMTP_FROM is required when SMTP_HOST is set, e.g. PPDB Darul Abror <ppdb@darulabror.com>")
+	}
+	return repository.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"darulabror/internal/service"
+	"log"
+	"os"
+	"time"
+)
+
+// LoadMFAConfig reads the settings of admin two-factor authentication.
+// TOTP secrets are encrypted with MFA_ENCRYPTION_KEY, or fallbackKey (JWT_SECRET) without it;
+// changing the key makes existing authenticators unusable (reset them via the admin API).
+func LoadMFAConfig(fallbackKey string) service.MFAConfig {
+	cfg := service.MFAConfig{
+		Issuer:        envOr("MFA_ISSUER", "Darul Abror"),
+		EncryptionKey: []byte(envOr("MFA_ENCRYPTION_KEY", fallbackKey)),
+	}
+
+	if v := os.Getenv("MFA_CHALLENGE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("invalid MFA_CHALLENGE_TTL %q (expected a duration like 5m)", v)
+		}
+		cfg.ChallengeTTL = ttl
+	}
+	return cfg
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"crypto/rand"
+	"darulabror/internal/repository"
+	"darulabror/internal/service"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PaymentProviderMidtrans = "midtrans"
+	PaymentProviderXendit   = "xendit"
+	PaymentProviderFake     = "fake"
+)
+
+// PaymentProvider is PAYMENT_PROVIDER, lower-cased; empty when online payment is disabled.
+func PaymentProvider() string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv("PAYMENT_PROVIDER")))
+}
+
+// LoadPaymentConfig selects the gateway of PAYMENT_PROVIDER (midtrans|xendit|fake).
+// Without a provider online payment is disabled.
+func LoadPaymentConfig() service.PaymentConfig {
+	var cfg service.PaymentConfig
+	if v := os.Getenv("PAYMENT_INVOICE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("invalid PAYMENT_INVOICE_TTL %q (expected a duration like 24h)", v)
+		}
+		cfg.InvoiceTTL = ttl
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	provider := PaymentProvider()
+	switch provider {
+	case "":
+		log.Printf("PAYMENT_PROVIDER is empty: online payment disabled")
+
+	case PaymentProviderMidtrans:
+		serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
+		if serverKey == "" {
+			log.Fatal("MIDTRANS_SERVER_KEY is required when PAYMENT_PROVIDER=midtrans")
+		}
+		production, _ := strconv.ParseBool(os.Getenv("MIDTRANS_PRODUCTION"))
+		cfg.Gateway = repository.NewMidtransGateway(client, serverKey, production)
+
+	case PaymentProviderXendit:
+		secretKey, callbackToken := os.Getenv("XENDIT_SECRET_KEY"), os.Getenv("XENDIT_CALLBACK_TOKEN")
+		if secretKey == "" || callbackToken == "" {
+			log.Fatal("XENDIT_SECRET_KEY and XENDIT_CALLBACK_TOKEN are required when PAYMENT_PROVIDER=xendit")
+		}
+		cfg.Gateway = repository.NewXenditGateway(client, secretKey, callbackToken)
+
+	case PaymentProviderFake:
+		// the fake provider only signs webhooks to itself, so a per-process key is enough
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("failed to generate fake payment key: %v", err)
+		}
+		log.Printf("fake payment provider enabled: pay invoices via GET /payments/fake/:number")
+		cfg.Gateway = repository.NewFakeGateway(APIBaseURL(), key)
+
+	default:
+		log.Fatalf("unknown PAYMENT_PROVIDER %q (expected midtrans, xendit or fake)", provider)
+	}
+	return cfg
+}
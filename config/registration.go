@@ -0,0 +1,26 @@
+package config
+
+import (
+	"darulabror/internal/service"
+	"log"
+	"os"
+	"time"
+)
+
+// LoadRegistrationEditConfig reads the settings of the applicant edit links.
+// Links are signed with DOCUMENT_SIGNING_KEY, or fallbackKey (JWT_SECRET) without it.
+func LoadRegistrationEditConfig(fallbackKey string) service.RegistrationEditConfig {
+	cfg := service.RegistrationEditConfig{
+		SigningKey: []byte(envOr("DOCUMENT_SIGNING_KEY", fallbackKey)),
+		URL:        envOr("REGISTRATION_EDIT_URL", APIBaseURL()+"/v1/registrations/edit"),
+	}
+
+	if v := os.Getenv("REGISTRATION_EDIT_LINK_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("invalid REGISTRATION_EDIT_LINK_TTL %q (expected a duration like 1h)", v)
+		}
+		cfg.LinkTTL = ttl
+	}
+	return cfg
+}
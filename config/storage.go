@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"darulabror/internal/repository"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	StorageDriverGCS   = "gcs"
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// ConnectionStorage builds the object store selected by STORAGE_DRIVER (gcs|local|s3, default gcs).
+// An empty bucket keeps the old behavior for gcs/s3: the store returns ErrStorageNotConfigured.
+func ConnectionStorage(ctx context.Context, bucketName string, isPublic bool) repository.ObjectStore {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_DRIVER")))
+	if driver == "" {
+		driver = StorageDriverGCS
+	}
+
+	switch driver {
+	case StorageDriverGCS:
+		var client *storage.Client
+		if bucketName != "" {
+			var err error
+			client, err = storage.NewClient(ctx)
+			if err != nil {
+				log.Fatalf("failed to init gcs client: %v", err)
+			}
+		}
+		return repository.NewGCPStorageRepo(client, bucketName, isPublic)
+
+	case StorageDriverLocal:
+		if bucketName == "" {
+			bucketName = "public"
+			if !isPublic {
+				bucketName = "private"
+			}
+		}
+
+		baseDir := os.Getenv("LOCAL_STORAGE_DIR")
+		if baseDir == "" {
+			baseDir = "./storage"
+		}
+		baseURL := strings.TrimRight(os.Getenv("LOCAL_STORAGE_BASE_URL"), "/")
+		if baseURL == "" {
+			port := os.Getenv("PORT")
+			if port == "" {
+				port = "8080"
+			}
+			baseURL = "http://localhost:" + port + "/media"
+		}
+
+		log.Printf("local storage enabled: bucket=%s dir=%s", bucketName, baseDir)
+		return repository.NewLocalStorageRepo(
+			filepath.Join(baseDir, bucketName),
+			baseURL+"/"+bucketName,
+			storageSigningKey(),
+			isPublic,
+		)
+
+	case StorageDriverS3:
+		if bucketName == "" {
+			return repository.NewS3StorageRepo(nil, "", "", isPublic)
+		}
+
+		endpoint := os.Getenv("S3_ENDPOINT")
+		if endpoint == "" {
+			log.Fatal("S3_ENDPOINT is required when STORAGE_DRIVER=s3")
+		}
+		useSSL := true
+		if v := os.Getenv("S3_USE_SSL"); v != "" {
+			useSSL, _ = strconv.ParseBool(v)
+		}
+
+		client, err := minio.New(endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY"), ""),
+			Secure: useSSL,
+			Region: os.Getenv("S3_REGION"),
+		})
+		if err != nil {
+			log.Fatalf("failed to init s3 client: %v", err)
+		}
+		return repository.NewS3StorageRepo(client, bucketName, os.Getenv("S3_PUBLIC_BASE_URL"), isPublic)
+
+	default:
+		log.Fatalf("unknown STORAGE_DRIVER %q (expected gcs, local or s3)", driver)
+		return nil
+	}
+}
+
+// storageSigningKey is used for local signed URLs. Without STORAGE_SIGNING_KEY a random key is
+// generated, so signed URLs stop working after a restart (fine for development).
+func storageSigningKey() []byte {
+	if key := strings.TrimSpace(os.Getenv("STORAGE_SIGNING_KEY")); key != "" {
+		return []byte(key)
+	}
+
+	log.Printf("STORAGE_SIGNING_KEY is empty: using a random key (signed URLs reset on restart)")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("failed to generate storage signing key: %v", err)
+	}
+	return key
+}
@@ -0,0 +1,22 @@
+// Package cache stores serialized values of hot reads with a TTL and tags.
+// Tags group keys so a write can drop everything derived from it (e.g. "article:12").
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get when the key is absent or expired.
+var ErrMiss = errors.New("cache miss")
+
+type Cache interface {
+	// Get returns the value of key, or ErrMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl (0 = no expiry) and adds key to each tag.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	Delete(ctx context.Context, keys ...string) error
+	// InvalidateTags deletes every key stored with one of the tags.
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testCache runs the Cache contract; advance moves the clock of the backend forward.
+func testCache(t *testing.T, c Cache, advance func(time.Duration)) {
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Get(missing) error = %v, want ErrMiss", err)
+	}
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(c.Set(ctx, "article:1", []byte("one"), time.Minute, "article:1", "articles"))
+	must(c.Set(ctx, "article:2", []byte("two"), time.Minute, "article:2", "articles"))
+	must(c.Set(ctx, "list:1", []byte("[1,2]"), time.Minute, "article_lists"))
+	must(c.Set(ctx, "short", []byte("x"), time.Second))
+
+	if v, err := c.Get(ctx, "article:1"); err != nil || string(v) != "one" {
+		t.Errorf("Get(article:1) = %q, %v", v, err)
+	}
+
+	advance(2 * time.Second)
+	if _, err := c.Get(ctx, "short"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(short) after TTL error = %v, want ErrMiss", err)
+	}
+
+	must(c.InvalidateTags(ctx, "article:1"))
+	if _, err := c.Get(ctx, "article:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("article:1 survived its tag")
+	}
+	if _, err := c.Get(ctx, "article:2"); err != nil {
+		t.Errorf("article:2 should be kept, got %v", err)
+	}
+
+	must(c.InvalidateTags(ctx, "articles", "unknown"))
+	if _, err := c.Get(ctx, "article:2"); !errors.Is(err, ErrMiss) {
+		t.Errorf("article:2 survived the articles tag")
+	}
+
+	must(c.Delete(ctx, "list:1"))
+	if _, err := c.Get(ctx, "list:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("list:1 survived Delete")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewMemory(10).(*memoryCache)
+	c.now = func() time.Time { return now }
+	testCache(t, c, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(2)
+	c.Set(ctx, "a", []byte("a"), 0, "t")
+	c.Set(ctx, "b", []byte("b"), 0, "t")
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("c"), 0)
+
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Error("b should have been evicted")
+	}
+	if tagged := c.(*memoryCache).tags["t"]; len(tagged) != 1 {
+		t.Errorf("tag t = %v, want only a", tagged)
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	c := NewRedis(client, "test:")
+	testCache(t, c, srv.FastForward)
+
+	// tag sets expire with their longest-lived key
+	ctx := context.Background()
+	c.Set(ctx, "long", []byte("x"), time.Hour, "group")
+	c.Set(ctx, "brief", []byte("y"), time.Minute, "group")
+	if ttl := srv.TTL("test:tag:group"); ttl != time.Hour {
+		t.Errorf("tag TTL = %v, want 1h", ttl)
+	}
+	if !srv.Exists("test:long") {
+		t.Error("keys are not prefixed")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	c := NewMemory(10)
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return 42, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if v, err := Load(c, "answer", time.Minute, Tagged[int]("answers"), load); err != nil || v != 42 {
+			t.Fatalf("Load() = %d, %v", v, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1 (second call served from cache)", loads)
+	}
+
+	Invalidate(c, "answers")
+	Load(c, "answer", time.Minute, Tagged[int]("answers"), load)
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2 after Invalidate", loads)
+	}
+
+	boom := errors.New("boom")
+	if _, err := Load(c, "failing", time.Minute, Tagged[int](), func() (int, error) { return 0, boom }); !errors.Is(err, boom) {
+		t.Errorf("Load() error = %v, want %v", err, boom)
+	}
+	if _, err := c.Get(context.Background(), "failing"); !errors.Is(err, ErrMiss) {
+		t.Error("a failed load must not be cached")
+	}
+}
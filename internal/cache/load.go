@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// callTimeout bounds each cache call; a slow cache falls back to the source.
+const callTimeout = 500 * time.Millisecond
+
+// Load returns the value stored under key, or loads it and stores it with tagsOf(value).
+// The cache is best effort: its errors are logged and the value is read from load.
+func Load[T any](c Cache, key string, ttl time.Duration, tagsOf func(T) []string, load func() (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	if b, err := c.Get(ctx, key); err == nil {
+		var v T
+		if err := json.Unmarshal(b, &v); err == nil {
+			return v, nil
+		}
+		logrus.WithField("key", key).Warn("undecodable cache entry, reloading")
+	} else if !errors.Is(err, ErrMiss) {
+		logrus.WithError(err).WithField("key", key).Warn("cache get failed")
+	}
+
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+
+	// a fresh timeout: load may have used up the first one
+	setCtx, cancelSet := context.WithTimeout(context.Background(), callTimeout)
+	defer cancelSet()
+	b, err := json.Marshal(v)
+	if err == nil {
+		err = c.Set(setCtx, key, b, ttl, tagsOf(v)...)
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("key", key).Warn("cache set failed")
+	}
+	return v, nil
+}
+
+// Tagged returns a tagsOf for Load that ignores the value.
+func Tagged[T any](tags ...string) func(T) []string {
+	return func(T) []string { return tags }
+}
+
+// Invalidate drops the tagged entries after a successful write.
+// A failure leaves stale entries until their TTL, so it is logged loudly.
+func Invalidate(c Cache, tags ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	if err := c.InvalidateTags(ctx, tags...); err != nil {
+		logrus.WithError(err).WithField("tags", tags).Error("cache invalidation failed")
+	}
+}
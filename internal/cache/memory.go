@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryCache keeps values in process memory and evicts the least recently used entry when full.
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	tags       map[string]map[string]struct{}
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero = no expiry
+	tags      []string
+}
+
+// NewMemory returns a cache of at most maxEntries values, local to this process.
+func NewMemory(maxEntries int) Cache {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &memoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		now:        time.Now,
+	}
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	e := el.Value.(*memoryEntry)
+	if !e.expiresAt.IsZero() && !m.now().Before(e.expiresAt) {
+		m.remove(el)
+		return nil, ErrMiss
+	}
+	m.ll.MoveToFront(el)
+	return append([]byte(nil), e.value...), nil
+}
+
+func (m *memoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.remove(el)
+	}
+	e := &memoryEntry{key: key, value: append([]byte(nil), value...), tags: tags}
+	if ttl > 0 {
+		e.expiresAt = m.now().Add(ttl)
+	}
+	m.items[key] = m.ll.PushFront(e)
+	for _, t := range tags {
+		if m.tags[t] == nil {
+			m.tags[t] = make(map[string]struct{})
+		}
+		m.tags[t][key] = struct{}{}
+	}
+
+	for m.ll.Len() > m.maxEntries {
+		m.remove(m.ll.Back())
+	}
+	return nil
+}
+
+func (m *memoryCache) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, k := range keys {
+		if el, ok := m.items[k]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+func (m *memoryCache) InvalidateTags(_ context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, t := range tags {
+		for k := range m.tags[t] {
+			if el, ok := m.items[k]; ok {
+				m.remove(el)
+			}
+		}
+		delete(m.tags, t)
+	}
+	return nil
+}
+
+// remove unlinks el from the list, the index and its tags; callers hold mu.
+func (m *memoryCache) remove(el *list.Element) {
+	e := el.Value.(*memoryEntry)
+	m.ll.Remove(el)
+	delete(m.items, e.key)
+	for _, t := range e.tags {
+		delete(m.tags[t], e.key)
+		if len(m.tags[t]) == 0 {
+			delete(m.tags, t)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCache stores values in Redis (or any server speaking its protocol), shared by all instances.
+// Each tag is a set of the keys stored with it; the set lives as long as its longest-lived key.
+type redisCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis namespaces all keys with prefix (e.g. "darulabror:").
+func NewRedis(client redis.UniversalClient, prefix string) Cache {
+	return &redisCache{client: client, prefix: prefix}
+}
+
+func (r *redisCache) tagKey(tag string) string {
+	return r.prefix + "tag:" + tag
+}
+
+func (r *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return b, err
+}
+
+func (r *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	key = r.prefix + key
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, value, ttl)
+		for _, t := range tags {
+			tk := r.tagKey(t)
+			pipe.SAdd(ctx, tk, key)
+			if ttl > 0 {
+				// NX sets the TTL of a new set, GT only ever extends it
+				pipe.ExpireNX(ctx, tk, ttl)
+				pipe.ExpireGT(ctx, tk, ttl)
+			} else {
+				pipe.Persist(ctx, tk)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	full := make([]string, len(keys))
+	for i, k := range keys {
+		full[i] = r.prefix + k
+	}
+	return r.client.Del(ctx, full...).Err()
+}
+
+func (r *redisCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, t := range tags {
+		tk := r.tagKey(t)
+		keys, err := r.client.SMembers(ctx, tk).Result()
+		if err != nil {
+			return err
+		}
+		if err := r.client.Del(ctx, append(keys, tk)...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,11 +7,17 @@ type AdminDTO struct {
 	Username string      `json:"username" validate:"required,min=3,max=50"`
 	Email    string      `json:"email" validate:"required,email"`
 	Password string      `json:"password" validate:"omitempty,min=6,max=50"`
-	Role     models.Role `json:"role" validate:"required,oneof=admin superadmin"`
+	Role     models.Role `json:"role" validate:"required,min=2,max=50"`
 
-	IsActive  *bool `json:"is_active" validate:"omitempty"`
-	CreatedAt int64 `json:"created_at,omitempty"`
-	UpdatedAt int64 `json:"updated_at,omitempty"`
+	IsActive *bool `json:"is_active" validate:"omitempty"`
+	// MFARequired makes the admin enroll a TOTP authenticator at the next login; tokens issued
+	// before stay valid until they expire. Admins cannot change it on their own account.
+	MFARequired *bool `json:"mfa_required" validate:"omitempty"`
+	CreatedAt   int64 `json:"created_at,omitempty"`
+	UpdatedAt   int64 `json:"updated_at,omitempty"`
+
+	// Permissions of the role, returned on login and profile only
+	Permissions []models.Permission `json:"permissions,omitempty"`
 }
 
 func AdminDTOToModel(dto AdminDTO) (models.Admin, error) {
@@ -19,27 +25,31 @@ func AdminDTOToModel(dto AdminDTO) (models.Admin, error) {
 	if dto.IsActive != nil {
 		isActive = *dto.IsActive
 	}
+	mfaRequired := dto.MFARequired != nil && *dto.MFARequired
 
 	return models.Admin{
-		ID:       dto.ID,
-		Username: dto.Username,
-		Email:    dto.Email,
-		Password: dto.Password,
-		Role:     dto.Role,
-		IsActive: isActive,
+		ID:          dto.ID,
+		Username:    dto.Username,
+		Email:       dto.Email,
+		Password:    dto.Password,
+		Role:        dto.Role,
+		IsActive:    isActive,
+		MFARequired: mfaRequired,
 	}, nil
 }
 
 func AdminModelToDTO(admin models.Admin) AdminDTO {
 	isActive := admin.IsActive
+	mfaRequired := admin.MFARequired
 	return AdminDTO{
-		ID:        admin.ID,
-		Username:  admin.Username,
-		Email:     admin.Email,
-		Password:  admin.Password,
-		Role:      admin.Role,
-		IsActive:  &isActive,
-		CreatedAt: admin.CreatedAt,
-		UpdatedAt: admin.UpdatedAt,
+		ID:          admin.ID,
+		Username:    admin.Username,
+		Email:       admin.Email,
+		Password:    admin.Password,
+		Role:        admin.Role,
+		IsActive:    &isActive,
+		MFARequired: &mfaRequired,
+		CreatedAt:   admin.CreatedAt,
+		UpdatedAt:   admin.UpdatedAt,
 	}
 }
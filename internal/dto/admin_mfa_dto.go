@@ -0,0 +1,39 @@
+package dto
+
+// AdminLoginDTO is the result of a login step: the JWT and admin, or a two-factor challenge
+// to complete with MFAToken.
+type AdminLoginDTO struct {
+	Token string    `json:"token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Admin *AdminDTO `json:"admin,omitempty"`
+
+	// MFARequired: send a TOTP or recovery code with mfa_token to POST /admin/login/mfa
+	MFARequired bool `json:"mfa_required,omitempty"`
+	// MFAEnrollmentRequired: the account must set up an authenticator first
+	// (POST /admin/login/mfa/setup, then POST /admin/login/mfa/enroll)
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	MFATokenExpiresAt     int64  `json:"mfa_token_expires_at,omitempty" example:"1734567890"`
+
+	// RecoveryCodes are returned once, when the authenticator is enrolled during login
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// MFASetupDTO is a new, not yet confirmed authenticator. The secret is shown once; apps scan
+// the otpauth URL (qr_code is the same URL as a PNG data URL).
+type MFASetupDTO struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OTPAuthURL string `json:"otpauth_url" example:"otpauth://totp/Darul%20Abror:admin@darulabror.com?issuer=Darul+Abror&secret=JBSWY3DPEHPK3PXP"`
+	QRCode     string `json:"qr_code" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+type MFAStatusDTO struct {
+	Enabled           bool  `json:"enabled"`
+	EnabledAt         int64 `json:"enabled_at,omitempty" example:"1734567890"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left" example:"10"`
+}
+
+// MFARecoveryCodesDTO: each code works once in place of a TOTP code; they are shown only now.
+type MFARecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "darulabror/internal/models"
+
+type AdminNoteDTO struct {
+	ID          uint               `json:"id" example:"1"`
+	SubjectType models.NoteSubject `json:"subject_type" example:"registration"`
+	SubjectID   uint               `json:"subject_id" example:"42"`
+	AuthorID    uint               `json:"author_id" example:"2"`
+	AuthorName  string             `json:"author_name" example:"ustadzah_aminah"`
+	Body        string             `json:"body" example:"Sudah ditelepon, **berkas KK** menyusul minggu depan."`
+	CreatedAt   int64              `json:"created_at" example:"1734567890"`
+}
+
+// AdminNoteCreateDTO: body is markdown, rendered by the admin frontend.
+type AdminNoteCreateDTO struct {
+	Body string `json:"body" validate:"required,max=5000" example:"Sudah ditelepon, **berkas KK** menyusul minggu depan."`
+}
+
+// AssigneeDTO: admin_id null unassigns.
+type AssigneeDTO struct {
+	AdminID *uint `json:"admin_id" example:"2"`
+}
+
+// AssigneeOptionDTO is an admin that items can be assigned to.
+type AssigneeOptionDTO struct {
+	ID       uint   `json:"id" example:"2"`
+	Username string `json:"username" example:"ustadzah_aminah"`
+}
+
+func AdminNoteModelToDTO(n models.AdminNote, authorName string) AdminNoteDTO {
+	return AdminNoteDTO{
+		ID:          n.ID,
+		SubjectType: n.SubjectType,
+		SubjectID:   n.SubjectID,
+		AuthorID:    n.AuthorID,
+		AuthorName:  authorName,
+		Body:        n.Body,
+		CreatedAt:   n.CreatedAt,
+	}
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"darulabror/internal/models"
+	"strconv"
+	"time"
+)
+
+type AdmissionPeriodDTO struct {
+	ID              uint      `json:"id" validate:"omitempty"`
+	AcademicYear    string    `json:"academic_year" validate:"required,len=9" example:"2025/2026"`
+	Wave            int       `json:"wave" validate:"required,min=1" example:"1"`
+	Name            string    `json:"name" validate:"omitempty,max=100" example:"Gelombang 1"`
+	OpensAt         time.Time `json:"opens_at" validate:"required" example:"2025-01-01T00:00:00+07:00"`
+	ClosesAt        time.Time `json:"closes_at" validate:"required,gtfield=OpensAt" example:"2025-03-31T23:59:59+07:00"`
+	Quota           int       `json:"quota" validate:"min=0" example:"200"`               // 0 = unlimited
+	RegistrationFee int64     `json:"registration_fee" validate:"min=0" example:"250000"` // rupiah, 0 = no fee
+
+	// read-only
+	IsOpen     bool  `json:"is_open"`
+	Registered int64 `json:"registered"`
+	CreatedAt  int64 `json:"created_at,omitempty"`
+	UpdatedAt  int64 `json:"updated_at,omitempty"`
+}
+
+func AdmissionPeriodDTOToModel(d AdmissionPeriodDTO) models.AdmissionPeriod {
+	name := d.Name
+	if name == "" {
+		name = "Gelombang " + strconv.Itoa(d.Wave) + " " + d.AcademicYear
+	}
+	return models.AdmissionPeriod{
+		ID:              d.ID,
+		AcademicYear:    d.AcademicYear,
+		Wave:            d.Wave,
+		Name:            name,
+		OpensAt:         d.OpensAt,
+		ClosesAt:        d.ClosesAt,
+		Quota:           d.Quota,
+		RegistrationFee: d.RegistrationFee,
+	}
+}
+
+func AdmissionPeriodModelToDTO(m models.AdmissionPeriod) AdmissionPeriodDTO {
+	return AdmissionPeriodDTO{
+		ID:              m.ID,
+		AcademicYear:    m.AcademicYear,
+		Wave:            m.Wave,
+		Name:            m.Name,
+		OpensAt:         m.OpensAt,
+		ClosesAt:        m.ClosesAt,
+		Quota:           m.Quota,
+		RegistrationFee: m.RegistrationFee,
+		IsOpen:          m.IsOpen(time.Now()),
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
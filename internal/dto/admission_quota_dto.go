@@ -0,0 +1,34 @@
+package dto
+
+import "darulabror/internal/models"
+
+type AdmissionQuotaDTO struct {
+	Gender  models.Gender  `json:"gender" validate:"required,oneof=male female" example:"female"`
+	Program models.Program `json:"program" validate:"required,oneof=boarding non_boarding" example:"boarding"`
+	Seats   int            `json:"seats" validate:"min=0" example:"60"`
+
+	// read-only
+	Taken      int64 `json:"taken"`
+	Waitlisted int64 `json:"waitlisted"`
+}
+
+type AdmissionQuotasDTO struct {
+	Quotas []AdmissionQuotaDTO `json:"quotas" validate:"dive"`
+}
+
+func AdmissionQuotaDTOToModel(periodID uint, d AdmissionQuotaDTO) models.AdmissionQuota {
+	return models.AdmissionQuota{
+		AdmissionPeriodID: periodID,
+		Gender:            d.Gender,
+		Program:           d.Program,
+		Seats:             d.Seats,
+	}
+}
+
+func AdmissionQuotaModelToDTO(m models.AdmissionQuota) AdmissionQuotaDTO {
+	return AdmissionQuotaDTO{
+		Gender:  m.Gender,
+		Program: m.Program,
+		Seats:   m.Seats,
+	}
+}
@@ -9,11 +9,19 @@ import (
 type ArticleDTO struct {
 	ID          uint           `json:"id" validate:"omitempty"`
 	Title       string         `json:"title" validate:"required,min=3,max=100"`
+	Slug        string         `json:"slug" validate:"omitempty,max=200"` // generated from the title when empty
 	PhotoHeader string         `json:"photo_header" validate:"required,max=2000"`
 	Content     datatypes.JSON `json:"content" validate:"required"`
 	Author      string         `json:"author" validate:"required,min=3,max=50"`
 	Status      string         `json:"status" validate:"omitempty,oneof=draft published"`
 
+	// Public: language of title/slug/content (the default when the requested one is not translated)
+	Locale           string   `json:"locale,omitempty" example:"en"`
+	Dir              string   `json:"dir,omitempty" example:"ltr"` // text direction: ltr or rtl
+	AvailableLocales []string `json:"available_locales,omitempty"`
+	// Admin: translation status per locale (missing, outdated, up_to_date)
+	Translations map[string]string `json:"translations,omitempty"`
+
 	CreatedAt int64 `json:"created_at,omitempty"`
 	UpdatedAt int64 `json:"updated_at,omitempty"`
 }
@@ -21,6 +29,7 @@ type ArticleDTO struct {
 func ArticleDTOToModel(dto ArticleDTO) (models.Article, error) {
 	return models.Article{
 		Title:       dto.Title,
+		Slug:        dto.Slug,
 		PhotoHeader: dto.PhotoHeader,
 		Content:     dto.Content,
 		Author:      dto.Author,
@@ -32,6 +41,7 @@ func ArticleModelToDTO(article models.Article) ArticleDTO {
 	return ArticleDTO{
 		ID:          article.ID,
 		Title:       article.Title,
+		Slug:        article.Slug,
 		PhotoHeader: article.PhotoHeader,
 		Content:     article.Content,
 		Author:      article.Author,
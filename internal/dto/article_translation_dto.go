@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"darulabror/internal/models"
+
+	"gorm.io/datatypes"
+)
+
+// ArticleTranslationDTO: status is missing (no title/content yet), outdated or up_to_date.
+type ArticleTranslationDTO struct {
+	Locale    string         `json:"locale" example:"ar"`
+	Dir       string         `json:"dir" example:"rtl"`
+	Status    string         `json:"status" example:"up_to_date"`
+	Title     string         `json:"title,omitempty"`
+	Slug      string         `json:"slug,omitempty"`
+	Content   datatypes.JSON `json:"content,omitempty"`
+	UpdatedAt int64          `json:"updated_at,omitempty" example:"1734567890"`
+}
+
+type ArticleTranslationInputDTO struct {
+	Title   string         `json:"title" validate:"required,min=3,max=100"`
+	Slug    string         `json:"slug" validate:"omitempty,max=200"` // generated from the title when empty
+	Content datatypes.JSON `json:"content" validate:"required"`
+}
+
+func ArticleTranslationModelToDTO(t models.ArticleTranslation, status string) ArticleTranslationDTO {
+	return ArticleTranslationDTO{
+		Locale:    string(t.Locale),
+		Dir:       t.Locale.Dir(),
+		Status:    status,
+		Title:     t.Title,
+		Slug:      t.Slug,
+		Content:   t.Content,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
@@ -0,0 +1,23 @@
+package dto
+
+type PopularArticleDTO struct {
+	ID          uint   `json:"id" example:"12"`
+	Title       string `json:"title" example:"Penerimaan Santri Baru 2025/2026"`
+	Author      string `json:"author" example:"Admin"`
+	PhotoHeader string `json:"photo_header"`
+	CreatedAt   int64  `json:"created_at" example:"1734567890"`
+	Views       int64  `json:"views" example:"431"`
+}
+
+// ArticleAnalyticsDTO: views are written in batches, so the last few seconds may be missing.
+type ArticleAnalyticsDTO struct {
+	ArticleID  uint   `json:"article_id" example:"12"`
+	Title      string `json:"title" example:"Penerimaan Santri Baru 2025/2026"`
+	Status     string `json:"status" example:"published"`
+	TotalViews int64  `json:"total_views" example:"1520"`
+	From       string `json:"from" example:"2025-01-01"`
+	To         string `json:"to" example:"2025-01-30"`
+	RangeViews int64  `json:"range_views" example:"431"`
+	// Daily has one entry per day of the range (WIB), zero-filled.
+	Daily []DailyCountDTO `json:"daily"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+type KeyCountDTO struct {
+	Key   string `json:"key" example:"SDN 1 Kediri"`
+	Count int64  `json:"count" example:"12"`
+}
+
+type DailyCountDTO struct {
+	Day   string `json:"day" example:"2025-01-15"`
+	Count int64  `json:"count" example:"4"`
+}
+
+type RegistrationStatsDTO struct {
+	Total            int64            `json:"total" example:"240"`
+	ByStatus         map[string]int64 `json:"by_status"`
+	ByGender         map[string]int64 `json:"by_gender"`
+	ByStudentType    map[string]int64 `json:"by_student_type"`
+	TopOriginSchools []KeyCountDTO    `json:"top_origin_schools"`
+	// Daily has one entry per day of the range (WIB), zero-filled.
+	Daily []DailyCountDTO `json:"daily"`
+}
+
+// ContactBacklogDTO: age buckets are lt_1d, 1d_3d, 3d_7d and gt_7d (since created_at).
+type ContactBacklogDTO struct {
+	Status string           `json:"status" example:"new"`
+	Total  int64            `json:"total" example:"7"`
+	ByAge  map[string]int64 `json:"by_age"`
+}
+
+type ContactStatsDTO struct {
+	Open    int64               `json:"open" example:"9"` // new + in_progress
+	Backlog []ContactBacklogDTO `json:"backlog"`
+}
+
+type ArticleSummaryDTO struct {
+	ID          uint   `json:"id" example:"12"`
+	Title       string `json:"title" example:"Penerimaan Santri Baru 2025/2026"`
+	Author      string `json:"author" example:"Admin"`
+	PhotoHeader string `json:"photo_header"`
+	CreatedAt   int64  `json:"created_at" example:"1734567890"`
+}
+
+type ArticleStatsDTO struct {
+	Total           int64               `json:"total" example:"30"`
+	ByStatus        map[string]int64    `json:"by_status"`
+	LatestPublished []ArticleSummaryDTO `json:"latest_published"`
+}
+
+// DashboardStatsDTO is the admin overview. It is cached for a short time (see generated_at).
+type DashboardStatsDTO struct {
+	From              string               `json:"from" example:"2025-01-01"`
+	To                string               `json:"to" example:"2025-01-30"`
+	AdmissionPeriodID uint                 `json:"admission_period_id,omitempty" example:"3"`
+	Registrations     RegistrationStatsDTO `json:"registrations"`
+	Contacts          ContactStatsDTO      `json:"contacts"`
+	Articles          ArticleStatsDTO      `json:"articles"`
+	GeneratedAt       int64                `json:"generated_at" example:"1734567890"`
+}
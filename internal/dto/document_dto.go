@@ -0,0 +1,43 @@
+package dto
+
+import "darulabror/internal/models"
+
+// DocumentLinksDTO holds tokenized download links; LetterURL is empty until a decision is made.
+type DocumentLinksDTO struct {
+	TrackingCode string            `json:"tracking_code" example:"DA-7K3M-Q9TX"`
+	CardURL      string            `json:"card_url" example:"https://api.darulabror.id/documents/NDIuY2FyZC4xNzM3MTU5ODkw.kX3c"`
+	LetterURL    string            `json:"letter_url,omitempty"`
+	LetterKind   models.LetterKind `json:"letter_kind,omitempty" example:"acceptance"`
+	ExpiresAt    int64             `json:"expires_at" example:"1737159890"`
+}
+
+// RegistrationTrackDTO is what an applicant sees when checking the status with tracking code and NISN.
+type RegistrationTrackDTO struct {
+	TrackingCode    string                    `json:"tracking_code" example:"DA-7K3M-Q9TX"`
+	FullName        string                    `json:"full_name" example:"Ahmad Fauzi"`
+	Status          models.RegistrationStatus `json:"status" example:"done"`
+	AdmissionPeriod string                    `json:"admission_period,omitempty" example:"Gelombang 1 2025/2026"`
+	CreatedAt       string                    `json:"created_at" example:"2025-01-02T08:00:00Z"`
+	Documents       DocumentLinksDTO          `json:"documents"`
+}
+
+// LetterTemplateDTO: subject and body are Go text/template, e.g. "Ananda {{.FullName}}".
+// Fields: FullName, NISN, Email, TrackingCode, Gender, Program, OriginSchool,
+// PeriodName, AcademicYear, SchoolName, Date.
+type LetterTemplateDTO struct {
+	Kind      models.LetterKind `json:"kind" example:"acceptance"`
+	Subject   string            `json:"subject" validate:"required,max=200" example:"Pengumuman Hasil Seleksi"`
+	Body      string            `json:"body" validate:"required,max=10000"`
+	Custom    bool              `json:"custom" example:"true"` // false: built-in default
+	UpdatedAt int64             `json:"updated_at,omitempty" example:"1734567890"`
+}
+
+func LetterTemplateModelToDTO(m models.LetterTemplate, custom bool) LetterTemplateDTO {
+	return LetterTemplateDTO{
+		Kind:      m.Kind,
+		Subject:   m.Subject,
+		Body:      m.Body,
+		Custom:    custom,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "darulabror/internal/models"
+
+type ExportJobDTO struct {
+	ID          uint                   `json:"id" example:"1"`
+	Kind        string                 `json:"kind" example:"registrations"`
+	Format      string                 `json:"format" example:"xlsx"`
+	Status      models.ExportJobStatus `json:"status" example:"done"`
+	RowCount    int64                  `json:"row_count" example:"1250"`
+	Error       string                 `json:"error,omitempty"`
+	DownloadURL string                 `json:"download_url,omitempty"`
+	CreatedAt   int64                  `json:"created_at" example:"1734567890"`
+	CompletedAt int64                  `json:"completed_at,omitempty"`
+}
+
+func ExportJobModelToDTO(m models.ExportJob) ExportJobDTO {
+	return ExportJobDTO{
+		ID:          m.ID,
+		Kind:        m.Kind,
+		Format:      m.Format,
+		Status:      m.Status,
+		RowCount:    m.RowCount,
+		Error:       m.Error,
+		CreatedAt:   m.CreatedAt,
+		CompletedAt: m.CompletedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "darulabror/internal/models"
+
+const (
+	ImportRowValid    = "valid"
+	ImportRowInvalid  = "invalid"
+	ImportRowImported = "imported"
+)
+
+// ImportRowDTO is the per-row result of a registration import.
+// Row is the spreadsheet row number (the header is row 1).
+type ImportRowDTO struct {
+	Row      int      `json:"row" example:"2"`
+	Status   string   `json:"status" example:"invalid"`
+	FullName string   `json:"full_name,omitempty" example:"Ahmad Fauzi"`
+	Email    string   `json:"email,omitempty" example:"ahmad@example.com"`
+	NISN     string   `json:"nisn,omitempty" example:"0012345678"`
+	Errors   []string `json:"errors,omitempty" example:"nisn: already registered"`
+	// RegistrationStatus is set for imported rows: new, or waitlisted when the quota was full.
+	RegistrationStatus models.RegistrationStatus `json:"registration_status,omitempty" example:"new"`
+	TrackingCode       string                    `json:"tracking_code,omitempty" example:"DA-7K3M-Q9TX"`
+}
+
+type ImportReportDTO struct {
+	AdmissionPeriodID uint           `json:"admission_period_id" example:"3"`
+	DryRun            bool           `json:"dry_run" example:"true"`
+	Total             int            `json:"total" example:"120"`
+	Valid             int            `json:"valid" example:"117"`
+	Invalid           int            `json:"invalid" example:"3"`
+	Imported          int            `json:"imported" example:"0"`
+	Waitlisted        int            `json:"waitlisted" example:"0"`
+	Rows              []ImportRowDTO `json:"rows"`
+}
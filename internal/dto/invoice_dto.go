@@ -0,0 +1,77 @@
+package dto
+
+import "darulabror/internal/models"
+
+// PaymentRequestDTO identifies the applicant (same as GET /registrations/track).
+type PaymentRequestDTO struct {
+	TrackingCode string `json:"tracking_code" validate:"required" example:"DA-7K3M-Q9TX"`
+	NISN         string `json:"nisn" validate:"required,len=10" example:"0012345678"`
+}
+
+type InvoiceDTO struct {
+	ID             uint                 `json:"id" example:"1"`
+	RegistrationID uint                 `json:"registration_id" example:"42"`
+	Number         string               `json:"number" example:"PSB-000042-01"`
+	Amount         int64                `json:"amount" example:"250000"`
+	Status         models.InvoiceStatus `json:"status" example:"pending"`
+	Provider       string               `json:"provider" example:"midtrans"`
+	PaymentURL     string               `json:"payment_url,omitempty" example:"https://app.sandbox.midtrans.com/snap/v4/redirection/66e4fa55"`
+	ExpiresAt      int64                `json:"expires_at" example:"1734654290"`
+	PaidAt         *int64               `json:"paid_at,omitempty"`
+	CreatedAt      int64                `json:"created_at" example:"1734567890"`
+}
+
+func InvoiceModelToDTO(m models.Invoice) InvoiceDTO {
+	out := InvoiceDTO{
+		ID:             m.ID,
+		RegistrationID: m.RegistrationID,
+		Number:         m.Number,
+		Amount:         m.Amount,
+		Status:         m.Status,
+		Provider:       m.Provider,
+		ExpiresAt:      m.ExpiresAt,
+		PaidAt:         m.PaidAt,
+		CreatedAt:      m.CreatedAt,
+	}
+	if m.Status == models.InvoicePending {
+		out.PaymentURL = m.PaymentURL
+	}
+	return out
+}
+
+// PaymentProofDTO: file_url (admin only) is a short-lived signed link to the receipt.
+type PaymentProofDTO struct {
+	ID             uint                      `json:"id" example:"1"`
+	RegistrationID uint                      `json:"registration_id" example:"42"`
+	Amount         int64                     `json:"amount" example:"250000"`
+	Status         models.PaymentProofStatus `json:"status" example:"pending"`
+	RejectReason   string                    `json:"reject_reason,omitempty" example:"Nominal transfer tidak sesuai"`
+	InvoiceID      *uint                     `json:"invoice_id,omitempty"`
+	ReviewedBy     *uint                     `json:"reviewed_by,omitempty"`
+	ReviewedAt     *int64                    `json:"reviewed_at,omitempty"`
+	CreatedAt      int64                     `json:"created_at" example:"1734567890"`
+
+	// admin queue
+	FullName     string `json:"full_name,omitempty" example:"Ahmad Fauzi"`
+	TrackingCode string `json:"tracking_code,omitempty" example:"DA-7K3M-Q9TX"`
+	ContentType  string `json:"content_type,omitempty" example:"image/jpeg"`
+	FileURL      string `json:"file_url,omitempty"`
+}
+
+type PaymentProofRejectDTO struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500" example:"Nominal transfer tidak sesuai"`
+}
+
+func PaymentProofModelToDTO(m models.PaymentProof) PaymentProofDTO {
+	return PaymentProofDTO{
+		ID:             m.ID,
+		RegistrationID: m.RegistrationID,
+		Amount:         m.Amount,
+		Status:         m.Status,
+		RejectReason:   m.RejectReason,
+		InvoiceID:      m.InvoiceID,
+		ReviewedBy:     m.ReviewedBy,
+		ReviewedAt:     m.ReviewedAt,
+		CreatedAt:      m.CreatedAt,
+	}
+}
@@ -14,9 +14,14 @@ type RegistrationDTO struct {
 	Phone       string                    `json:"phone" validate:"required,min=10,max=13"`
 	Status      models.RegistrationStatus `json:"status,omitempty"`
 
-	Gender       models.Gender `json:"gender" validate:"required,oneof=male female"`
-	PlaceOfBirth string        `json:"place_of_birth" validate:"required,min=3,max=100"`
-	DateOfBirth  string        `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
+	// AdmissionPeriodID and TrackingCode are assigned by the server.
+	AdmissionPeriodID *uint  `json:"admission_period_id,omitempty"`
+	TrackingCode      string `json:"tracking_code,omitempty" example:"DA-7K3M-Q9TX"`
+
+	Gender       models.Gender  `json:"gender" validate:"required,oneof=male female"`
+	Program      models.Program `json:"program" validate:"omitempty,oneof=boarding non_boarding"` // default boarding
+	PlaceOfBirth string         `json:"place_of_birth" validate:"required,min=3,max=100"`
+	DateOfBirth  string         `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
 
 	Address      string `json:"address" validate:"required,min=3,max=255"`
 	OriginSchool string `json:"origin_school" validate:"required,min=3,max=100"`
@@ -32,6 +37,12 @@ type RegistrationDTO struct {
 	PhoneMother       string `json:"phone_mother" validate:"required,min=10,max=13"`
 	DateOfBirthMother string `json:"date_of_birth_mother" validate:"required,datetime=2006-01-02"`
 
+	// AssignedTo is set with PUT /admin/registrations/:id/assignee.
+	AssignedTo *uint `json:"assigned_to,omitempty"`
+
+	// Version must be sent back when editing; a stale version is rejected with 409.
+	Version int `json:"version,omitempty" example:"1"`
+
 	CreatedAt string `json:"created_at,omitempty"`
 }
 
@@ -51,11 +62,19 @@ func RegistrationDTOToModel(d RegistrationDTO) (models.Registration, error) {
 		return models.Registration{}, err
 	}
 
+	program := d.Program
+	if program == "" {
+		program = models.ProgramBoarding
+	}
+
 	return models.Registration{
 		ID:                d.ID,
+		AdmissionPeriodID: d.AdmissionPeriodID,
 		StudentType:       d.StudentType,
 		Gender:            d.Gender,
+		Program:           program,
 		Status:            d.Status,
+		Version:           d.Version,
 		Email:             d.Email,
 		FullName:          d.FullName,
 		Phone:             d.Phone,
@@ -78,11 +97,14 @@ func RegistrationDTOToModel(d RegistrationDTO) (models.Registration, error) {
 func RegistrationModelToDTO(m models.Registration) RegistrationDTO {
 	return RegistrationDTO{
 		ID:                m.ID,
+		AdmissionPeriodID: m.AdmissionPeriodID,
+		TrackingCode:      m.TrackingCode,
 		StudentType:       m.StudentType,
 		FullName:          m.FullName,
 		Email:             m.Email,
 		Phone:             m.Phone,
 		Gender:            m.Gender,
+		Program:           m.Program,
 		PlaceOfBirth:      m.PlaceOfBirth,
 		DateOfBirth:       m.DateOfBirth.Format(dateLayout),
 		Address:           m.Address,
@@ -97,6 +119,39 @@ func RegistrationModelToDTO(m models.Registration) RegistrationDTO {
 		PhoneMother:       m.PhoneMother,
 		DateOfBirthMother: m.DateOfBirthMother.Format(dateLayout),
 		Status:            m.Status,
+		AssignedTo:        m.AssignedTo,
+		Version:           m.Version,
 		CreatedAt:         m.CreatedAt.Format(time.RFC3339),
 	}
 }
+
+// RegistrationReceiptDTO is returned to the applicant after registering;
+// status is "waitlisted" when the group's quota was already full.
+// The tracking code (with the NISN) is needed to check the status later.
+type RegistrationReceiptDTO struct {
+	ID                uint                      `json:"id" example:"42"`
+	Status            models.RegistrationStatus `json:"status" example:"new"`
+	AdmissionPeriodID *uint                     `json:"admission_period_id" example:"3"`
+	TrackingCode      string                    `json:"tracking_code" example:"DA-7K3M-Q9TX"`
+	CardURL           string                    `json:"card_url" example:"https://api.darulabror.id/documents/NDIuY2FyZC4xNzM3MTU5ODkw.kX3c"`
+}
+
+type RegistrationStatusHistoryDTO struct {
+	ID         uint                      `json:"id" example:"1"`
+	FromStatus models.RegistrationStatus `json:"from_status" example:"waitlisted"`
+	ToStatus   models.RegistrationStatus `json:"to_status" example:"new"`
+	Reason     string                    `json:"reason" example:"promoted"`
+	ChangedBy  *uint                     `json:"changed_by"`
+	CreatedAt  int64                     `json:"created_at" example:"1734567890"`
+}
+
+func RegistrationStatusHistoryModelToDTO(m models.RegistrationStatusHistory) RegistrationStatusHistoryDTO {
+	return RegistrationStatusHistoryDTO{
+		ID:         m.ID,
+		FromStatus: m.FromStatus,
+		ToStatus:   m.ToStatus,
+		Reason:     m.Reason,
+		ChangedBy:  m.ChangedBy,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "darulabror/internal/models"
+
+// EditLinkRequestDTO identifies the registration an applicant wants to edit.
+type EditLinkRequestDTO struct {
+	TrackingCode string `json:"tracking_code" validate:"required" example:"DA-7K3M-Q9TX"`
+	NISN         string `json:"nisn" validate:"required" example:"1234567890"`
+}
+
+// EditLinkSentDTO tells where the edit link went; the address is masked.
+type EditLinkSentDTO struct {
+	Email     string `json:"email" example:"a***@gmail.com"`
+	ExpiresAt int64  `json:"expires_at" example:"1737159890"`
+}
+
+type RegistrationChangeDTO struct {
+	ID        uint   `json:"id" example:"1"`
+	Field     string `json:"field" example:"phone"`
+	OldValue  string `json:"old_value" example:"081234567890"`
+	NewValue  string `json:"new_value" example:"081234567809"`
+	Source    string `json:"source" example:"applicant"`
+	ChangedBy *uint  `json:"changed_by"`
+	CreatedAt int64  `json:"created_at" example:"1734567890"`
+}
+
+func RegistrationChangeModelToDTO(m models.RegistrationChange) RegistrationChangeDTO {
+	return RegistrationChangeDTO{
+		ID:        m.ID,
+		Field:     m.Field,
+		OldValue:  m.OldValue,
+		NewValue:  m.NewValue,
+		Source:    m.Source,
+		ChangedBy: m.ChangedBy,
+		CreatedAt: m.CreatedAt,
+	}
+}
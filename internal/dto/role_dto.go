@@ -0,0 +1,34 @@
+package dto
+
+import "darulabror/internal/models"
+
+type RoleDTO struct {
+	Name        models.Role         `json:"name" example:"editor"`
+	Description string              `json:"description" example:"Writes and publishes articles"`
+	System      bool                `json:"system"`
+	Permissions []models.Permission `json:"permissions"`
+	CreatedAt   int64               `json:"created_at,omitempty"`
+	UpdatedAt   int64               `json:"updated_at,omitempty"`
+}
+
+// RoleInputDTO creates a role (name required) or updates one (name taken from the path).
+type RoleInputDTO struct {
+	Name        models.Role         `json:"name" validate:"omitempty,min=2,max=50" example:"editor"`
+	Description string              `json:"description" validate:"max=200" example:"Writes and publishes articles"`
+	Permissions []models.Permission `json:"permissions" validate:"required,dive,required"`
+}
+
+func RoleModelToDTO(role models.AdminRole) RoleDTO {
+	perms := make([]models.Permission, len(role.Permissions))
+	for i, p := range role.Permissions {
+		perms[i] = p.Permission
+	}
+	return RoleDTO{
+		Name:        role.Name,
+		Description: role.Description,
+		System:      role.System,
+		Permissions: perms,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package dto
+
+import (
+	"darulabror/internal/models"
+	"time"
+)
+
+type SelectionComponentDTO struct {
+	ID       uint    `json:"id" validate:"omitempty"`
+	Key      string  `json:"key" validate:"required,max=50" example:"quran"`
+	Name     string  `json:"name" validate:"required,max=100" example:"Tes Baca Al-Qur'an"`
+	Weight   float64 `json:"weight" validate:"gt=0,lte=100" example:"40"` // percent; all weights add up to 100
+	MaxScore float64 `json:"max_score" validate:"gt=0" example:"100"`
+}
+
+type SelectionComponentsDTO struct {
+	Components []SelectionComponentDTO `json:"components" validate:"required,min=1,dive"`
+}
+
+func SelectionComponentDTOToModel(periodID uint, d SelectionComponentDTO) models.SelectionComponent {
+	return models.SelectionComponent{
+		AdmissionPeriodID: periodID,
+		Key:               d.Key,
+		Name:              d.Name,
+		Weight:            d.Weight,
+		MaxScore:          d.MaxScore,
+	}
+}
+
+func SelectionComponentModelToDTO(m models.SelectionComponent) SelectionComponentDTO {
+	return SelectionComponentDTO{
+		ID:       m.ID,
+		Key:      m.Key,
+		Name:     m.Name,
+		Weight:   m.Weight,
+		MaxScore: m.MaxScore,
+	}
+}
+
+type SelectionSessionDTO struct {
+	ID                uint      `json:"id" validate:"omitempty"`
+	AdmissionPeriodID uint      `json:"admission_period_id" validate:"required" example:"3"`
+	Name              string    `json:"name" validate:"required,max=100" example:"Sesi 1"`
+	StartsAt          time.Time `json:"starts_at" validate:"required" example:"2025-04-12T08:00:00+07:00"`
+	Location          string    `json:"location" validate:"required,max=255" example:"Aula Utama"`
+	Capacity          int       `json:"capacity" validate:"required,min=1" example:"40"`
+
+	// read-only
+	Assigned  int64 `json:"assigned"`
+	CreatedAt int64 `json:"created_at,omitempty"`
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+}
+
+func SelectionSessionDTOToModel(d SelectionSessionDTO) models.SelectionSession {
+	return models.SelectionSession{
+		ID:                d.ID,
+		AdmissionPeriodID: d.AdmissionPeriodID,
+		Name:              d.Name,
+		StartsAt:          d.StartsAt,
+		Location:          d.Location,
+		Capacity:          d.Capacity,
+	}
+}
+
+func SelectionSessionModelToDTO(m models.SelectionSession) SelectionSessionDTO {
+	return SelectionSessionDTO{
+		ID:                m.ID,
+		AdmissionPeriodID: m.AdmissionPeriodID,
+		Name:              m.Name,
+		StartsAt:          m.StartsAt,
+		Location:          m.Location,
+		Capacity:          m.Capacity,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+type SelectionAssignDTO struct {
+	RegistrationIDs []uint `json:"registration_ids" validate:"required,min=1,max=500" example:"12,15,18"`
+}
+
+type SelectionCandidateDTO struct {
+	RegistrationID uint                      `json:"registration_id" example:"12"`
+	FullName       string                    `json:"full_name" example:"Ahmad Fauzi"`
+	NISN           string                    `json:"nisn" example:"0012345678"`
+	Gender         models.Gender             `json:"gender" example:"male"`
+	Program        models.Program            `json:"program" example:"boarding"`
+	Status         models.RegistrationStatus `json:"status" example:"validate"`
+	SessionID      *uint                     `json:"session_id,omitempty" example:"2"`
+}
+
+type SelectionScoreInputDTO struct {
+	ComponentID uint    `json:"component_id" validate:"required" example:"1"`
+	Score       float64 `json:"score" validate:"min=0" example:"87.5"`
+}
+
+type SelectionScoresDTO struct {
+	Scores []SelectionScoreInputDTO `json:"scores" validate:"required,min=1,dive"`
+}
+
+// SelectionScoreDTO is one component of a registration's result; Score is nil until entered.
+type SelectionScoreDTO struct {
+	ComponentID uint     `json:"component_id" example:"1"`
+	Key         string   `json:"key" example:"quran"`
+	Name        string   `json:"name" example:"Tes Baca Al-Qur'an"`
+	Weight      float64  `json:"weight" example:"40"`
+	MaxScore    float64  `json:"max_score" example:"100"`
+	Score       *float64 `json:"score" example:"87.5"`
+}
+
+// SelectionResultDTO: FinalScore is the weighted score (0-100) of the components entered so far.
+type SelectionResultDTO struct {
+	RegistrationID uint                `json:"registration_id" example:"12"`
+	Scores         []SelectionScoreDTO `json:"scores"`
+	FinalScore     float64             `json:"final_score" example:"84.25"`
+	Complete       bool                `json:"complete" example:"true"`
+}
+
+// RankingEntryDTO: Rank is 0 for applicants whose scores are incomplete (listed last).
+type RankingEntryDTO struct {
+	Rank int `json:"rank" example:"1"`
+	SelectionCandidateDTO
+	FinalScore float64 `json:"final_score" example:"84.25"`
+	Complete   bool    `json:"complete" example:"true"`
+}
+
+// RankingApplyDTO moves the top ranked applicants (complete scores, final_score >= min_score) to status.
+type RankingApplyDTO struct {
+	Status   models.RegistrationStatus `json:"status" validate:"required,oneof=process done" example:"done"`
+	Top      int                       `json:"top" validate:"required,min=1" example:"60"`
+	MinScore float64                   `json:"min_score" validate:"min=0,max=100" example:"60"`
+	Gender   models.Gender             `json:"gender" validate:"omitempty,oneof=male female" example:"male"`
+	Program  models.Program            `json:"program" validate:"omitempty,oneof=boarding non_boarding" example:"boarding"`
+}
+
+type RankingApplyResultDTO struct {
+	Status          models.RegistrationStatus `json:"status" example:"done"`
+	Updated         int                       `json:"updated" example:"58"`
+	RegistrationIDs []uint                    `json:"registration_ids"`
+}
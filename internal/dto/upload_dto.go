@@ -0,0 +1,49 @@
+package dto
+
+import "darulabror/internal/models"
+
+type UploadRequestDTO struct {
+	FileName    string `json:"file_name" validate:"required,min=1,max=255" example:"kegiatan.mp4"`
+	ContentType string `json:"content_type" validate:"required,max=100" example:"video/mp4"`
+	Size        int64  `json:"size" validate:"required,gt=0" example:"104857600"`
+	Purpose     string `json:"purpose" validate:"required,oneof=article_header article_content" example:"article_content"`
+	Resumable   bool   `json:"resumable" example:"false"`
+}
+
+// UploadTicketDTO tells the client where to send the bytes. Headers must be sent as-is.
+type UploadTicketDTO struct {
+	ID         uint              `json:"id" example:"1"`
+	ObjectName string            `json:"object_name" example:"articles/content/1734567890_kegiatan.mp4"`
+	UploadURL  string            `json:"upload_url" example:"https://storage.googleapis.com/bucket/articles/content/...?X-Goog-Signature=..."`
+	Method     string            `json:"method" example:"PUT"`
+	Headers    map[string]string `json:"headers"`
+	ExpiresAt  int64             `json:"expires_at" example:"1734569690"`
+}
+
+type MediaUploadDTO struct {
+	ID          uint                     `json:"id"`
+	ObjectName  string                   `json:"object_name"`
+	FileName    string                   `json:"file_name"`
+	ContentType string                   `json:"content_type"`
+	Size        int64                    `json:"size"`
+	Purpose     string                   `json:"purpose"`
+	Status      models.MediaUploadStatus `json:"status"`
+	URL         string                   `json:"url,omitempty"`
+	CreatedAt   int64                    `json:"created_at"`
+	CompletedAt int64                    `json:"completed_at,omitempty"`
+}
+
+func MediaUploadModelToDTO(m models.MediaUpload) MediaUploadDTO {
+	return MediaUploadDTO{
+		ID:          m.ID,
+		ObjectName:  m.ObjectName,
+		FileName:    m.FileName,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		Purpose:     m.Purpose,
+		Status:      m.Status,
+		URL:         m.URL,
+		CreatedAt:   m.CreatedAt,
+		CompletedAt: m.CompletedAt,
+	}
+}
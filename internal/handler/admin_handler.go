@@ -2,6 +2,7 @@ package handler
 
 import (
 	"darulabror/internal/dto"
+	"darulabror/internal/repository"
 	"darulabror/internal/service"
 	"darulabror/internal/utils"
 	"net/http"
@@ -19,8 +20,8 @@ func NewAdminHandler(svc service.AdminService) *AdminHandler {
 }
 
 // Create godoc
-// @Summary Superadmin create admin
-// @Tags Admins (Superadmin)
+// @Summary Create admin (admins.manage)
+// @Tags Admins (Admin)
 // @Security BearerAuth
 // @Accept json
 // @Produce json
@@ -29,6 +30,7 @@ func NewAdminHandler(svc service.AdminService) *AdminHandler {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/admins [post]
@@ -38,49 +40,53 @@ func (h *AdminHandler) Create(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 	if body.Password == "" {
 		return utils.UnprocessableEntityResponse(c, "password is required")
 	}
 
-	role, _ := utils.GetRole(c)
-	if err := h.svc.CreateAdmin(role, body); err != nil {
-		if err.Error() == "forbidden" {
-			return utils.ForbiddenResponse(c, "forbidden")
-		}
-		return utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.svc.CreateAdmin(utils.GetPermissions(c), body); err != nil {
+		return writeError(c, err, "failed to create admin")
 	}
 
 	return c.NoContent(http.StatusCreated)
 }
 
 // List godoc
-// @Summary Superadmin list admins
-// @Tags Admins (Superadmin)
+// @Summary List admin (admins.manage)s
+// @Tags Admins (Admin)
 // @Security BearerAuth
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(10)
+// @Param cursor query string false "Keyset pagination: empty for the first page, then meta.next_cursor (replaces page)"
+// @Param with_total query bool false "Also count total in cursor mode"
+// @Param q query string false "Search username or email"
+// @Param from query string false "Created on/after (YYYY-MM-DD)"
+// @Param to query string false "Created on/before (YYYY-MM-DD)"
+// @Param sort query string false "Sort field" Enums(id, created_at, username, email) default(id)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
 // @Success 200 {object} AdminListResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/admins [get]
 func (h *AdminHandler) List(c echo.Context) error {
-	page, limit := utils.ParsePagination(c)
-	items, total, err := h.svc.GetAllAdmins(page, limit)
+	q, err := utils.ParseListQuery(c, repository.AdminListSpec.SortFields(), nil)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, page, err := h.svc.GetAllAdmins(q)
 	if err != nil {
 		return utils.InternalServerErrorResponse(c, "failed to fetch admins")
 	}
 
 	return utils.SuccessResponse(c, "admins fetched", map[string]interface{}{
 		"items": items,
-		"meta": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"meta":  utils.ListMeta(q, page),
 	})
 }
 
@@ -106,8 +112,9 @@ func (h *AdminHandler) Profile(c echo.Context) error {
 }
 
 // Update godoc
-// @Summary Superadmin update admin
-// @Tags Admins (Superadmin)
+// @Summary Update admin (admins.manage)
+// @Description mfa_required cannot be changed on your own account. It is checked at login, so tokens issued before it was set stay valid until they expire (24h).
+// @Tags Admins (Admin)
 // @Security BearerAuth
 // @Accept json
 // @Produce json
@@ -131,23 +138,23 @@ func (h *AdminHandler) Update(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 	body.ID = uint(id64)
+	callerID, ok := utils.GetAdminID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthorized")
+	}
 
-	role, _ := utils.GetRole(c)
-	if err := h.svc.UpdateAdmin(role, body); err != nil {
-		if err.Error() == "forbidden" {
-			return utils.ForbiddenResponse(c, "forbidden")
-		}
-		return utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.svc.UpdateAdmin(utils.GetPermissions(c), callerID, body); err != nil {
+		return writeError(c, err, "failed to update admin")
 	}
 	return c.NoContent(http.StatusOK)
 }
 
 // Delete godoc
-// @Summary Superadmin delete admin
-// @Tags Admins (Superadmin)
+// @Summary Delete admin (admins.manage)
+// @Tags Admins (Admin)
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "Admin ID" minimum(1)
@@ -163,19 +170,15 @@ func (h *AdminHandler) Delete(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid id")
 	}
 
-	role, _ := utils.GetRole(c)
-	if err := h.svc.DeleteAdmin(role, uint(id64)); err != nil {
-		if err.Error() == "forbidden" {
-			return utils.ForbiddenResponse(c, "forbidden")
-		}
-		return utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.svc.DeleteAdmin(utils.GetPermissions(c), uint(id64)); err != nil {
+		return writeError(c, err, "failed to delete admin")
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
 // Login godoc
 // @Summary Admin login
-// @Description Returns JWT token for accessing /admin endpoints.
+// @Description Returns JWT token for accessing /admin endpoints. With two-factor authentication it returns mfa_required (or mfa_enrollment_required) and an mfa_token instead; finish with POST /admin/login/mfa (or /admin/login/mfa/setup and /enroll).
 // @Tags Auth (Admin)
 // @Accept json
 // @Produce json
@@ -194,10 +197,10 @@ func (h *AdminHandler) Login(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
-	token, admin, err := h.svc.AuthenticateAdmin(body.Email, body.Password)
+	out, err := h.svc.AuthenticateAdmin(body.Email, body.Password)
 	if err != nil {
 		switch err {
 		case service.ErrInvalidCredentials:
@@ -209,10 +212,10 @@ func (h *AdminHandler) Login(c echo.Context) error {
 		}
 	}
 
-	return utils.SuccessResponse(c, "login success", map[string]interface{}{
-		"token": token,
-		"admin": admin,
-	})
+	if out.Token == "" {
+		return utils.SuccessResponse(c, "two-factor authentication required", out)
+	}
+	return utils.SuccessResponse(c, "login success", out)
 }
 
 // (optional) helper supaya compile kalau dipakai di routes
@@ -255,7 +258,7 @@ func (h *AdminHandler) ChangePassword(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
 	if err := h.svc.ChangePassword(adminID, body.CurrentPassword, body.NewPassword); err != nil {
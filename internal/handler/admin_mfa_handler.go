@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PUBLIC: POST /admin/login/mfa
+// LoginMFA godoc
+// @Summary Admin login, second step
+// @Description Exchanges the mfa_token of POST /admin/login and a TOTP code (or a recovery code, which then stops working) for the JWT. After 5 wrong codes, codes are refused for 15 minutes.
+// @Tags Auth (Admin)
+// @Accept json
+// @Produce json
+// @Param request body AdminMFALoginRequest true "Challenge token and code"
+// @Success 200 {object} AdminLoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/login/mfa [post]
+func (h *AdminHandler) LoginMFA(c echo.Context) error {
+	var body AdminMFALoginRequest
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	out, err := h.svc.VerifyLoginMFA(body.MFAToken, body.Code)
+	if err != nil {
+		return writeError(c, err, "failed to login")
+	}
+	return utils.SuccessResponse(c, "login success", out)
+}
+
+// PUBLIC: POST /admin/login/mfa/setup
+// LoginMFASetup godoc
+// @Summary Admin login, set up the required authenticator
+// @Description For accounts that must enroll (mfa_enrollment_required). Returns the secret and otpauth URL (also as a QR code) to add to an authenticator app.
+// @Tags Auth (Admin)
+// @Accept json
+// @Produce json
+// @Param request body AdminMFATokenRequest true "Challenge token"
+// @Success 200 {object} MFASetupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/login/mfa/setup [post]
+func (h *AdminHandler) LoginMFASetup(c echo.Context) error {
+	var body AdminMFATokenRequest
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	out, err := h.svc.SetupLoginMFA(body.MFAToken)
+	if err != nil {
+		return writeError(c, err, "failed to set up two-factor authentication")
+	}
+	return utils.SuccessResponse(c, "authenticator created", out)
+}
+
+// PUBLIC: POST /admin/login/mfa/enroll
+// LoginMFAEnroll godoc
+// @Summary Admin login, confirm the required authenticator
+// @Description Confirms the authenticator with its first code and returns the JWT together with the recovery codes (shown only once).
+// @Tags Auth (Admin)
+// @Accept json
+// @Produce json
+// @Param request body AdminMFALoginRequest true "Challenge token and TOTP code"
+// @Success 200 {object} AdminLoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/login/mfa/enroll [post]
+func (h *AdminHandler) LoginMFAEnroll(c echo.Context) error {
+	var body AdminMFALoginRequest
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	out, err := h.svc.EnrollLoginMFA(body.MFAToken, body.Code)
+	if err != nil {
+		return writeError(c, err, "failed to enable two-factor authentication")
+	}
+	return utils.SuccessResponse(c, "login success", out)
+}
+
+// ADMIN: GET /admin/profile/mfa
+// MFAStatus godoc
+// @Summary Admin get own two-factor authentication status
+// @Tags Admins (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} MFAStatusResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/profile/mfa [get]
+func (h *AdminHandler) MFAStatus(c echo.Context) error {
+	adminID, ok := utils.GetAdminID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthorized")
+	}
+	out, err := h.svc.GetMFAStatus(adminID)
+	if err != nil {
+		return writeError(c, err, "failed to fetch two-factor status")
+	}
+	return utils.SuccessResponse(c, "two-factor status fetched", out)
+}
+
+// ADMIN: POST /admin/profile/mfa/setup
+// MFASetup godoc
+// @Summary Admin set up an authenticator
+// @Description Returns a new secret and otpauth URL (also as a QR code). Two-factor authentication is on only after POST /admin/profile/mfa/enable.
+// @Tags Admins (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} MFASetupResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/profile/mfa/setup [post]
+func (h *AdminHandler) MFASetup(c echo.Context) error {
+	adminID, ok := utils.GetAdminID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthorized")
+	}
+	out, err := h.svc.SetupMFA(adminID)
+	if err != nil {
+		return writeError(c, err, "failed to set up two-factor authentication")
+	}
+	return utils.SuccessResponse(c, "authenticator created", out)
+}
+
+// ADMIN: POST /admin/profile/mfa/enable
+// MFAEnable godoc
+// @Summary Admin confirm the authenticator and turn on two-factor authentication
+// @Description Returns the recovery codes, shown only once.
+// @Tags Admins (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body AdminMFACodeRequest true "TOTP code"
+// @Success 200 {object} MFARecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/profile/mfa/enable [post]
+func (h *AdminHandler) MFAEnable(c echo.Context) error {
+	adminID, ok := utils.GetAdminID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthorized")
+	}
+	var body AdminMFACodeRequest
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	codes, err := h.svc.EnableMFA(adminID, body.Code)
+	if err != nil {
+		return writeError(c, err, "failed to enable two-factor authentication")
+	}
+	return utils.SuccessResponse(c, "two-factor authentication enabled", dto.MFARecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// ADMIN: POST /admin/profile/mfa/recovery-codes
+// MFARecoveryCodes godoc
+// @Summary Admin replace own recovery codes
+// @Description The old codes stop working; the new ones are shown only once.
+// @Tags Admins (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body AdminMFACodeRequest true "TOTP code"
+// @Success 200 {object} MFARecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/profile/mfa/recovery-codes [post]
+func (h *AdminHandler) MFARecoveryCodes(c echo.Context) error {
+	adminID, ok := utils.GetAdminID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthorized")
+	}
+	var body AdminMFACodeRequest
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	codes, err := h.svc.RegenerateRecoveryCodes(adminID, body.Code)
+	if err != nil {
+		return writeError(c, err, "failed to replace recovery codes")
+	}
+	return utils.SuccessResponse(c, "recovery codes replaced", dto.MFARecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// ADMIN: POST /admin/profile/mfa/disable
+// MFADisable godoc
+// @Summary Admin turn off own two-factor authentication
+// @Description Needs the password and a TOTP or recovery code; not allowed when two-factor authentication is required for the account.
+// @Tags Admins (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body AdminMFADisableRequest true "Password and code"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/profile/mfa/disable [post]
+func (h *AdminHandler) MFADisable(c echo.Context) error {
+	adminID, ok := utils.GetAdminID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthorized")
+	}
+	var body AdminMFADisableRequest
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	if err := h.svc.DisableMFA(adminID, body.Password, body.Code); err != nil {
+		return writeError(c, err, "failed to disable two-factor authentication")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ADMIN: DELETE /admin/admins/:id/mfa
+// ResetMFA godoc
+// @Summary Reset an admin's two-factor authentication (admins.manage)
+// @Description Removes the authenticator and recovery codes of an admin who lost them. If the admin is required to use two-factor authentication, they enroll again at the next login.
+// @Tags Admins (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admin ID" minimum(1)
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admins/{id}/mfa [delete]
+func (h *AdminHandler) ResetMFA(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	if err := h.svc.ResetMFA(utils.GetPermissions(c), uint(id64)); err != nil {
+		return writeError(c, err, "failed to reset two-factor authentication")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type AdmissionPeriodHandler struct {
+	svc service.AdmissionPeriodService
+}
+
+func NewAdmissionPeriodHandler(svc service.AdmissionPeriodService) *AdmissionPeriodHandler {
+	return &AdmissionPeriodHandler{svc: svc}
+}
+
+// PUBLIC: GET /admission-periods/current
+// Current godoc
+// @Summary Get the open admission period
+// @Description Returns 404 when registration is closed.
+// @Tags Admission Periods (Public)
+// @Produce json
+// @Success 200 {object} AdmissionPeriodResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admission-periods/current [get]
+func (h *AdmissionPeriodHandler) Current(c echo.Context) error {
+	item, err := h.svc.GetCurrent()
+	if err != nil {
+		if errors.Is(err, service.ErrRegistrationClosed) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		logrus.WithError(err).Error("failed get current admission period")
+		return utils.InternalServerErrorResponse(c, "failed to fetch admission period")
+	}
+	return utils.SuccessResponse(c, "admission period fetched", item)
+}
+
+// ADMIN: GET /admin/admission-periods
+// List godoc
+// @Summary Admin list admission periods
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} AdmissionPeriodListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods [get]
+func (h *AdmissionPeriodHandler) List(c echo.Context) error {
+	items, err := h.svc.GetAll()
+	if err != nil {
+		logrus.WithError(err).Error("failed list admission periods")
+		return utils.InternalServerErrorResponse(c, "failed to fetch admission periods")
+	}
+	return utils.SuccessResponse(c, "admission periods fetched", items)
+}
+
+// ADMIN: GET /admin/admission-periods/:id
+// GetByID godoc
+// @Summary Admin get admission period
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Success 200 {object} AdmissionPeriodResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id} [get]
+func (h *AdmissionPeriodHandler) GetByID(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	item, err := h.svc.GetByID(uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to fetch admission period")
+	}
+	return utils.SuccessResponse(c, "admission period fetched", item)
+}
+
+// ADMIN: POST /admin/admission-periods
+// Create godoc
+// @Summary Admin create admission period
+// @Description Periods may not overlap; registrations are accepted while opens_at <= now < closes_at. quota 0 = unlimited.
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.AdmissionPeriodDTO true "Admission period payload"
+// @Success 201 {object} AdmissionPeriodResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods [post]
+func (h *AdmissionPeriodHandler) Create(c echo.Context) error {
+	var body dto.AdmissionPeriodDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.Create(body)
+	if err != nil {
+		return writeError(c, err, "failed to create admission period")
+	}
+	return utils.CreatedResponse(c, "admission period created", item)
+}
+
+// ADMIN: PUT /admin/admission-periods/:id
+// Update godoc
+// @Summary Admin update admission period
+// @Description Raising quota promotes waitlisted registrations of the period (oldest first).
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Param request body dto.AdmissionPeriodDTO true "Admission period payload"
+// @Success 200 {object} AdmissionPeriodResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id} [put]
+func (h *AdmissionPeriodHandler) Update(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	var body dto.AdmissionPeriodDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.Update(uint(id64), body)
+	if err != nil {
+		return writeError(c, err, "failed to update admission period")
+	}
+	return utils.SuccessResponse(c, "admission period updated", item)
+}
+
+// ADMIN: DELETE /admin/admission-periods/:id
+// Delete godoc
+// @Summary Admin delete admission period
+// @Description Only periods without registrations can be deleted.
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id} [delete]
+func (h *AdmissionPeriodHandler) Delete(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	if err := h.svc.Delete(uint(id64)); err != nil {
+		return writeError(c, err, "failed to delete admission period")
+	}
+	return utils.NoContentResponse(c)
+}
+
+// ADMIN: GET /admin/admission-periods/:id/quotas
+// Quotas godoc
+// @Summary Admin get admission quotas
+// @Description Seats per gender/program with taken (new..done) and waitlisted counts.
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Success 200 {object} AdmissionQuotaListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/quotas [get]
+func (h *AdmissionPeriodHandler) Quotas(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	items, err := h.svc.GetQuotas(uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to fetch admission quotas")
+	}
+	return utils.SuccessResponse(c, "admission quotas fetched", items)
+}
+
+// ADMIN: PUT /admin/admission-periods/:id/quotas
+// SetQuotas godoc
+// @Summary Admin replace admission quotas
+// @Description Replaces all quotas of the period. Groups without a quota are only limited by the period quota.
+// @Description Raising a quota promotes waitlisted registrations (oldest first); lowering it never removes seats already taken.
+// @Tags Admission Periods (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Param request body dto.AdmissionQuotasDTO true "Quotas payload"
+// @Success 200 {object} AdmissionQuotaListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/quotas [put]
+func (h *AdmissionPeriodHandler) SetQuotas(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	var body dto.AdmissionQuotasDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	items, err := h.svc.SetQuotas(uint(id64), body.Quotas)
+	if err != nil {
+		return writeError(c, err, "failed to update admission quotas")
+	}
+	return utils.SuccessResponse(c, "admission quotas updated", items)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"darulabror/internal/dto"
+	"darulabror/internal/models"
 	"darulabror/internal/repository"
 	"darulabror/internal/service"
 	"darulabror/internal/utils"
@@ -20,50 +21,111 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// publicArticleCacheControl lets browsers and CDNs reuse public article responses briefly;
+// afterwards they revalidate with If-None-Match / If-Modified-Since.
+const publicArticleCacheControl = "public, max-age=60"
+
 type ArticleHandler struct {
-	svc service.ArticleService
+	svc   service.ArticleService
+	views service.ArticleViewService
 }
 
-func NewArticleHandler(svc service.ArticleService) *ArticleHandler {
-	return &ArticleHandler{svc: svc}
+func NewArticleHandler(svc service.ArticleService, views service.ArticleViewService) *ArticleHandler {
+	return &ArticleHandler{svc: svc, views: views}
 }
 
 // PUBLIC: GET /articles
 // ListPublished godoc
 // @Summary List published articles
 // @Description Returns only articles with status "published".
+// @Description Sends ETag, Last-Modified and Cache-Control; answers 304 to a matching If-None-Match or If-Modified-Since.
+// @Description Title, slug and content are in the requested language, or Indonesian when not translated (see locale).
 // @Tags Articles (Public)
 // @Produce json
+// @Param lang query string false "Content language (wins over Accept-Language)" Enums(id, en, ar)
+// @Param Accept-Language header string false "Preferred languages, e.g. ar,en;q=0.8"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of the cached copy"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(10)
+// @Param cursor query string false "Keyset pagination: empty for the first page, then meta.next_cursor (replaces page)"
+// @Param with_total query bool false "Also count total in cursor mode"
 // @Success 200 {object} ArticleListResponse
+// @Success 304 "Not modified"
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /articles [get]
 func (h *ArticleHandler) ListPublished(c echo.Context) error {
-	page, limit := utils.ParsePagination(c)
-	items, total, err := h.svc.GetPublishedArticles(page, limit)
+	q, err := utils.ParsePageQuery(c)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	locale, err := utils.ResolveLocale(c)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	res, err := h.svc.GetPublishedArticles(q, locale)
 	if err != nil {
 		logrus.WithError(err).Error("failed list published articles")
 		return utils.InternalServerErrorResponse(c, "failed to fetch articles")
 	}
 
+	setLocaleHeaders(c, locale)
+	if utils.NotModified(c, res.ETag, res.LastModified, publicArticleCacheControl) {
+		return c.NoContent(http.StatusNotModified)
+	}
 	return utils.SuccessResponse(c, "articles fetched", map[string]interface{}{
-		"items": items,
-		"meta": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"items": res.Items,
+		"meta":  utils.ListMeta(q, res.Page),
 	})
 }
 
+// PUBLIC: GET /articles/popular
+// Popular godoc
+// @Summary Most viewed published articles
+// @Description Ranks published articles by views in the last window days (today included, WIB).
+// @Description Views are written in batches, so the newest views may take up to a minute to count.
+// @Tags Articles (Public)
+// @Produce json
+// @Param window query string false "Window in days, e.g. 7d (max 90d)" default(7d)
+// @Param limit query int false "Number of articles (max 50)" default(10)
+// @Success 200 {object} PopularArticleListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /articles/popular [get]
+func (h *ArticleHandler) Popular(c echo.Context) error {
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return utils.BadRequestResponse(c, "invalid limit")
+		}
+		limit = n
+	}
+
+	items, err := h.views.Popular(c.QueryParam("window"), limit)
+	if err != nil {
+		return writeError(c, err, "failed to fetch popular articles")
+	}
+	return utils.SuccessResponse(c, "popular articles fetched", items)
+}
+
 // PUBLIC: GET /articles/:id
 // GetPublishedByID godoc
 // @Summary Get published article by ID
+// @Description Counts a view in the background (bots, prefetches and repeat views of a visitor are ignored).
+// @Description Sends ETag, Last-Modified and Cache-Control; answers 304 to a matching If-None-Match or If-Modified-Since.
+// @Description Title, slug and content are in the requested language, or Indonesian when not translated (see locale).
 // @Tags Articles (Public)
 // @Produce json
 // @Param id path int true "Article ID" minimum(1)
+// @Param lang query string false "Content language (wins over Accept-Language)" Enums(id, en, ar)
+// @Param Accept-Language header string false "Preferred languages, e.g. ar,en;q=0.8"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of the cached copy"
 // @Success 200 {object} SuccessResponse[dto.ArticleDTO]
+// @Success 304 "Not modified"
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /articles/{id} [get]
@@ -73,31 +135,107 @@ func (h *ArticleHandler) GetPublishedByID(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid id")
 	}
 
-	item, err := h.svc.GetPublishedArticleByID(uint(id64))
+	locale, err := utils.ResolveLocale(c)
 	if err != nil {
-		return utils.NotFoundResponse(c, err.Error())
+		return utils.BadRequestResponse(c, err.Error())
 	}
 
-	return utils.SuccessResponse(c, "article fetched", item)
+	res, err := h.svc.GetPublishedArticleByID(uint(id64), locale)
+	if err != nil {
+		return writeError(c, err, "failed to fetch article")
+	}
+	return h.publishedArticle(c, res)
+}
+
+// PUBLIC: GET /articles/slug/:slug
+// GetPublishedBySlug godoc
+// @Summary Get published article by slug
+// @Description The slug of any language finds the article. Without ?lang= the article is returned in the language
+// @Description of the slug (Accept-Language is ignored, so shared links keep their language). Views and caching as by ID.
+// @Tags Articles (Public)
+// @Produce json
+// @Param slug path string true "Article slug"
+// @Param lang query string false "Content language" Enums(id, en, ar)
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of the cached copy"
+// @Success 200 {object} SuccessResponse[dto.ArticleDTO]
+// @Success 304 "Not modified"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /articles/slug/{slug} [get]
+func (h *ArticleHandler) GetPublishedBySlug(c echo.Context) error {
+	locale, err := utils.ParseLang(c)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	res, err := h.svc.GetPublishedArticleBySlug(c.Param("slug"), locale)
+	if err != nil {
+		return writeError(c, err, "failed to fetch article")
+	}
+	return h.publishedArticle(c, res)
+}
+
+// publishedArticle records the view and writes the article, or 304 for a current cached copy.
+func (h *ArticleHandler) publishedArticle(c echo.Context, res service.PublishedArticle) error {
+	// a revalidated copy is still read, so 304s count as views too
+	if !isPrefetch(c) {
+		h.views.Record(res.Article.ID, c.RealIP(), c.Request().UserAgent())
+	}
+	setLocaleHeaders(c, models.Locale(res.Article.Locale))
+	if utils.NotModified(c, res.ETag, res.LastModified, publicArticleCacheControl) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return utils.SuccessResponse(c, "article fetched", res.Article)
+}
+
+// setLocaleHeaders marks the language of a public response; shared caches keep one copy per Accept-Language.
+func setLocaleHeaders(c echo.Context, locale models.Locale) {
+	h := c.Response().Header()
+	h.Set("Content-Language", string(locale))
+	h.Add(echo.HeaderVary, "Accept-Language")
+}
+
+// isPrefetch reports speculative loads by the browser, which are not views.
+func isPrefetch(c echo.Context) bool {
+	for _, name := range []string{"Sec-Purpose", "Purpose", "X-Moz"} {
+		if strings.Contains(strings.ToLower(c.Request().Header.Get(name)), "prefetch") {
+			return true
+		}
+	}
+	return false
 }
 
 // ADMIN: GET /admin/articles
 // AdminListAll godoc
 // @Summary Admin list all articles
-// @Description Returns draft + published.
+// @Description Returns draft + published, in Indonesian, with the translation status per locale (translations).
 // @Tags Articles (Admin)
 // @Security BearerAuth
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(10)
+// @Param cursor query string false "Keyset pagination: empty for the first page, then meta.next_cursor (replaces page)"
+// @Param with_total query bool false "Also count total in cursor mode"
+// @Param q query string false "Search title or author"
+// @Param status query string false "Filter by status, comma-separated (draft, published)"
+// @Param from query string false "Created on/after (YYYY-MM-DD)"
+// @Param to query string false "Created on/before (YYYY-MM-DD)"
+// @Param sort query string false "Sort field" Enums(id, created_at, updated_at, title, status) default(id)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
 // @Success 200 {object} ArticleListResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/articles [get]
 func (h *ArticleHandler) AdminListAll(c echo.Context) error {
-	page, limit := utils.ParsePagination(c)
-	items, total, err := h.svc.GetAllArticles(page, limit)
+	q, err := utils.ParseListQuery(c, repository.ArticleListSpec.SortFields(), []string{"draft", "published"})
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, page, err := h.svc.GetAllArticles(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed admin list all articles")
 		return utils.InternalServerErrorResponse(c, "failed to fetch articles")
@@ -105,11 +243,7 @@ func (h *ArticleHandler) AdminListAll(c echo.Context) error {
 
 	return utils.SuccessResponse(c, "articles fetched", map[string]interface{}{
 		"items": items,
-		"meta": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"meta":  utils.ListMeta(q, page),
 	})
 }
 
@@ -121,8 +255,9 @@ func (h *ArticleHandler) AdminListAll(c echo.Context) error {
 // @Accept multipart/form-data
 // @Produce json
 // @Param title formData string true "Title"
+// @Param slug formData string false "URL slug (generated from the title when empty; kept on update when empty)"
 // @Param author formData string true "Author"
-// @Param status formData string false "draft|published" Enums(draft,published)
+// @Param status formData string false "draft|published (publishing or unpublishing needs articles.publish)" Enums(draft,published)
 // @Param content formData string true "JSON string (flexible)"
 // @Param photo_header formData string false "Optional header URL (ignored if photo_header_file is provided)"
 // @Param photo_header_file formData file false "Optional header image file (uploaded and set to photo_header)"
@@ -131,6 +266,7 @@ func (h *ArticleHandler) AdminListAll(c echo.Context) error {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/articles [post]
@@ -157,7 +293,7 @@ func (h *ArticleHandler) AdminCreate(c echo.Context) error {
 	urlByKey, err := h.parseAndUploadContentFiles(c)
 	if err != nil {
 		if errors.Is(err, repository.ErrStorageNotConfigured) {
-			return utils.BadRequestResponse(c, "storage not configured: set PUBLIC_BUCKET to enable uploads")
+			return utils.BadRequestResponse(c, "storage not configured: set STORAGE_DRIVER/PUBLIC_BUCKET to enable uploads")
 		}
 		logrus.WithError(err).Error("failed upload content files")
 		return utils.InternalServerErrorResponse(c, "failed to upload content files")
@@ -174,6 +310,7 @@ func (h *ArticleHandler) AdminCreate(c echo.Context) error {
 
 	body := dto.ArticleDTO{
 		Title:       title,
+		Slug:        c.FormValue("slug"),
 		Author:      author,
 		Status:      status,
 		PhotoHeader: c.FormValue("photo_header"),
@@ -194,7 +331,7 @@ func (h *ArticleHandler) AdminCreate(c echo.Context) error {
 		urlOrObject, err := h.svc.UploadArticleMedia(c.Request().Context(), src, objectName)
 		if err != nil {
 			if errors.Is(err, repository.ErrStorageNotConfigured) {
-				return utils.BadRequestResponse(c, "storage not configured: set PUBLIC_BUCKET to enable uploads")
+				return utils.BadRequestResponse(c, "storage not configured: set STORAGE_DRIVER/PUBLIC_BUCKET to enable uploads")
 			}
 			logrus.WithError(err).Error("failed upload photo_header_file")
 			return utils.InternalServerErrorResponse(c, "failed to upload header")
@@ -208,11 +345,11 @@ func (h *ArticleHandler) AdminCreate(c echo.Context) error {
 	}
 
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
-	if err := h.svc.CreateArticle(body); err != nil {
-		return utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.svc.CreateArticle(body, utils.HasPermission(c, models.PermArticlesPublish)); err != nil {
+		return writeError(c, err, "article request failed")
 	}
 	return c.NoContent(http.StatusCreated)
 }
@@ -226,8 +363,9 @@ func (h *ArticleHandler) AdminCreate(c echo.Context) error {
 // @Produce json
 // @Param id path int true "Article ID" minimum(1)
 // @Param title formData string true "Title"
+// @Param slug formData string false "URL slug (generated from the title when empty; kept on update when empty)"
 // @Param author formData string true "Author"
-// @Param status formData string false "draft|published" Enums(draft,published)
+// @Param status formData string false "draft|published (publishing or unpublishing needs articles.publish)" Enums(draft,published)
 // @Param content formData string true "JSON string (flexible)"
 // @Param photo_header formData string false "Optional header URL (ignored if photo_header_file is provided)"
 // @Param photo_header_file formData file false "Optional header image file (uploaded and set to photo_header)"
@@ -236,6 +374,8 @@ func (h *ArticleHandler) AdminCreate(c echo.Context) error {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/articles/{id} [put]
@@ -266,7 +406,7 @@ func (h *ArticleHandler) AdminUpdate(c echo.Context) error {
 	urlByKey, err := h.parseAndUploadContentFiles(c)
 	if err != nil {
 		if errors.Is(err, repository.ErrStorageNotConfigured) {
-			return utils.BadRequestResponse(c, "storage not configured: set PUBLIC_BUCKET to enable uploads")
+			return utils.BadRequestResponse(c, "storage not configured: set STORAGE_DRIVER/PUBLIC_BUCKET to enable uploads")
 		}
 		logrus.WithError(err).Error("failed upload content files")
 		return utils.InternalServerErrorResponse(c, "failed to upload content files")
@@ -281,6 +421,7 @@ func (h *ArticleHandler) AdminUpdate(c echo.Context) error {
 
 	body := dto.ArticleDTO{
 		Title:       title,
+		Slug:        c.FormValue("slug"),
 		Author:      author,
 		Status:      status,
 		PhotoHeader: c.FormValue("photo_header"),
@@ -301,7 +442,7 @@ func (h *ArticleHandler) AdminUpdate(c echo.Context) error {
 		urlOrObject, err := h.svc.UploadArticleMedia(c.Request().Context(), src, objectName)
 		if err != nil {
 			if errors.Is(err, repository.ErrStorageNotConfigured) {
-				return utils.BadRequestResponse(c, "storage not configured: set PUBLIC_BUCKET to enable header upload")
+				return utils.BadRequestResponse(c, "storage not configured: set STORAGE_DRIVER/PUBLIC_BUCKET to enable header upload")
 			}
 			logrus.WithError(err).Error("failed upload photo_header_file")
 			return utils.InternalServerErrorResponse(c, "failed to upload header")
@@ -315,11 +456,11 @@ func (h *ArticleHandler) AdminUpdate(c echo.Context) error {
 	}
 
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
-	if err := h.svc.UpdateArticle(uint(id64), body); err != nil {
-		return utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.svc.UpdateArticle(uint(id64), body, utils.HasPermission(c, models.PermArticlesPublish)); err != nil {
+		return writeError(c, err, "article request failed")
 	}
 	return c.NoContent(http.StatusOK)
 }
@@ -344,8 +485,7 @@ func (h *ArticleHandler) AdminDelete(c echo.Context) error {
 	}
 
 	if err := h.svc.DeleteArticle(uint(id64)); err != nil {
-		logrus.WithError(err).WithField("id", id64).Error("failed delete article")
-		return utils.InternalServerErrorResponse(c, err.Error())
+		return writeError(c, err, "failed to delete article")
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -354,6 +494,134 @@ func (h *ArticleHandler) AdminDelete(c echo.Context) error {
 // extractUploadKey supports field naming:
 // - content_files[img1]
 // - content_file_img1 (fallback)
+// ADMIN: GET /admin/articles/:id/analytics
+// AdminAnalytics godoc
+// @Summary Admin article view analytics
+// @Description Total views and views per day (WIB, zero-filled) of one article.
+// @Tags Articles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Article ID" minimum(1)
+// @Param from query string false "Range start (YYYY-MM-DD); default 29 days before to"
+// @Param to query string false "Range end, inclusive (YYYY-MM-DD); default today"
+// @Success 200 {object} ArticleAnalyticsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/articles/{id}/analytics [get]
+func (h *ArticleHandler) AdminAnalytics(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	out, err := h.views.Analytics(uint(id64), c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return writeError(c, err, "failed to fetch article analytics")
+	}
+	return utils.SuccessResponse(c, "article analytics fetched", out)
+}
+
+// ADMIN: GET /admin/articles/:id/translations
+// AdminTranslations godoc
+// @Summary Admin list article translations
+// @Description One entry per non-default locale (en, ar) with status missing, outdated (the Indonesian title or
+// @Description content changed after the translation was saved) or up_to_date.
+// @Tags Articles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Article ID" minimum(1)
+// @Success 200 {object} ArticleTranslationListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/articles/{id}/translations [get]
+func (h *ArticleHandler) AdminTranslations(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	items, err := h.svc.GetTranslations(id)
+	if err != nil {
+		return writeError(c, err, "article request failed")
+	}
+	return utils.SuccessResponse(c, "article translations fetched", items)
+}
+
+// ADMIN: PUT /admin/articles/:id/translations/:locale
+// AdminSaveTranslation godoc
+// @Summary Admin create or replace an article translation
+// @Description Content has the same JSON shape as the article content; media URLs can be reused.
+// @Tags Articles (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Article ID" minimum(1)
+// @Param locale path string true "Translation locale" Enums(en, ar)
+// @Param body body dto.ArticleTranslationInputDTO true "Translation"
+// @Success 200 {object} ArticleTranslationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/articles/{id}/translations/{locale} [put]
+func (h *ArticleHandler) AdminSaveTranslation(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	var body dto.ArticleTranslationInputDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid request body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	out, err := h.svc.SaveTranslation(id, c.Param("locale"), body)
+	if err != nil {
+		return writeError(c, err, "article request failed")
+	}
+	return utils.SuccessResponse(c, "article translation saved", out)
+}
+
+// ADMIN: DELETE /admin/articles/:id/translations/:locale
+// AdminDeleteTranslation godoc
+// @Summary Admin delete an article translation
+// @Tags Articles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Article ID" minimum(1)
+// @Param locale path string true "Translation locale" Enums(en, ar)
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/articles/{id}/translations/{locale} [delete]
+func (h *ArticleHandler) AdminDeleteTranslation(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	if err := h.svc.DeleteTranslation(id, c.Param("locale")); err != nil {
+		return writeError(c, err, "article request failed")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 func extractUploadKey(field string) (string, bool) {
 	if strings.HasPrefix(field, "content_files[") && strings.HasSuffix(field, "]") {
 		key := strings.TrimSuffix(strings.TrimPrefix(field, "content_files["), "]")
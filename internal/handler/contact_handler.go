@@ -2,6 +2,7 @@ package handler
 
 import (
 	"darulabror/internal/models"
+	"darulabror/internal/repository"
 	"darulabror/internal/service"
 	"darulabror/internal/utils"
 	"net/http"
@@ -11,6 +12,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var contactStatuses = []string{
+	string(models.ContactStatusNew),
+	string(models.ContactStatusInProgress),
+	string(models.ContactStatusDone),
+}
+
 type ContactHandler struct {
 	svc service.ContactService
 }
@@ -37,7 +44,7 @@ func (h *ContactHandler) Create(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
 	if err := h.svc.CreateContact(body.Email, body.Subject, body.Message); err != nil {
@@ -56,17 +63,31 @@ func (h *ContactHandler) Create(c echo.Context) error {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(10)
-// @Param status query string false "Filter by status" Enums(new, in_progress, done)
+// @Param cursor query string false "Keyset pagination: empty for the first page, then meta.next_cursor (replaces page)"
+// @Param with_total query bool false "Also count total in cursor mode"
+// @Param q query string false "Search email or subject"
+// @Param status query string false "Filter by status, comma-separated (new, in_progress, done)"
+// @Param assignee query string false "Assigned admin: me (my queue), none or an admin ID"
+// @Param from query string false "Created on/after (YYYY-MM-DD)"
+// @Param to query string false "Created on/before (YYYY-MM-DD)"
+// @Param sort query string false "Sort field" Enums(id, created_at, email, status) default(id)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
 // @Success 200 {object} ContactListResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/contacts [get]
 func (h *ContactHandler) AdminList(c echo.Context) error {
-	page, limit := utils.ParsePagination(c)
-	status := c.QueryParam("status")
-	
-	items, total, err := h.svc.GetAllContacts(page, limit, status)
+	q, err := utils.ParseListQuery(c, repository.ContactListSpec.SortFields(), contactStatuses)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := utils.ParseAssignee(c, &q); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, page, err := h.svc.GetAllContacts(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed list contacts")
 		return utils.InternalServerErrorResponse(c, "failed to fetch contacts")
@@ -74,11 +95,7 @@ func (h *ContactHandler) AdminList(c echo.Context) error {
 
 	return utils.SuccessResponse(c, "contacts fetched", map[string]interface{}{
 		"items": items,
-		"meta": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"meta":  utils.ListMeta(q, page),
 	})
 }
 
@@ -103,7 +120,7 @@ func (h *ContactHandler) AdminGetByID(c echo.Context) error {
 
 	item, err := h.svc.GetContactByID(uint(id64))
 	if err != nil {
-		return utils.NotFoundResponse(c, err.Error())
+		return writeError(c, err, "failed to fetch contact")
 	}
 	return utils.SuccessResponse(c, "contact fetched", item)
 }
@@ -141,11 +158,11 @@ func (h *ContactHandler) AdminUpdate(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
 	if err := h.svc.UpdateContact(uint(id64), body.Email, body.Subject, body.Message); err != nil {
-		return utils.InternalServerErrorResponse(c, err.Error())
+		return writeError(c, err, "failed to update contact")
 	}
 	return c.NoContent(http.StatusOK)
 }
@@ -170,7 +187,7 @@ func (h *ContactHandler) AdminDelete(c echo.Context) error {
 	}
 
 	if err := h.svc.DeleteContact(uint(id64)); err != nil {
-		return utils.InternalServerErrorResponse(c, err.Error())
+		return writeError(c, err, "failed to delete contact")
 	}
 	return c.NoContent(http.StatusNoContent)
 }
@@ -203,14 +220,11 @@ func (h *ContactHandler) AdminUpdateStatus(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
 	if err := h.svc.UpdateContactStatus(uint(id64), models.ContactStatus(body.Status)); err != nil {
-		if err.Error() == "contact not found" {
-			return utils.NotFoundResponse(c, err.Error())
-		}
-		return utils.InternalServerErrorResponse(c, err.Error())
+		return writeError(c, err, "failed to update contact status")
 	}
 	return c.NoContent(http.StatusNoContent)
 }
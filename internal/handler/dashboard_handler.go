@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DashboardHandler struct {
+	svc service.DashboardService
+}
+
+func NewDashboardHandler(svc service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{svc: svc}
+}
+
+// ADMIN: GET /admin/dashboard/stats
+// Stats godoc
+// @Summary Admin dashboard statistics
+// @Description Registration counts (status, gender, student type, top origin schools, daily trend), contact backlog
+// @Description by status and age, and article counts with the latest published. Cached for one minute.
+// @Tags Dashboard (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Trend start (YYYY-MM-DD, WIB); default 29 days before to"
+// @Param to query string false "Trend end, inclusive (YYYY-MM-DD, WIB); default today"
+// @Param admission_period_id query int false "Only registrations of this admission period"
+// @Success 200 {object} DashboardStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dashboard/stats [get]
+func (h *DashboardHandler) Stats(c echo.Context) error {
+	q := service.DashboardQuery{From: c.QueryParam("from"), To: c.QueryParam("to")}
+	if v := c.QueryParam("admission_period_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return utils.BadRequestResponse(c, "invalid admission_period_id")
+		}
+		q.AdmissionPeriodID = uint(id)
+	}
+
+	stats, err := h.svc.GetStats(q)
+	if err != nil {
+		return writeError(c, err, "failed to fetch dashboard stats")
+	}
+	return utils.SuccessResponse(c, "dashboard stats fetched", stats)
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DocumentHandler struct {
+	svc service.DocumentService
+}
+
+func NewDocumentHandler(svc service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{svc: svc}
+}
+
+// PUBLIC: GET /registrations/track
+// Track godoc
+// @Summary Check registration status
+// @Description Looks up a registration by tracking code and NISN (both must match) and returns fresh document links.
+// @Tags Registrations
+// @Produce json
+// @Param code query string true "Tracking code" example(DA-7K3M-Q9TX)
+// @Param nisn query string true "NISN"
+// @Success 200 {object} RegistrationTrackResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/track [get]
+func (h *DocumentHandler) Track(c echo.Context) error {
+	code, nisn := c.QueryParam("code"), c.QueryParam("nisn")
+	if code == "" || nisn == "" {
+		return utils.BadRequestResponse(c, "code and nisn are required")
+	}
+
+	out, err := h.svc.Track(code, nisn)
+	if err != nil {
+		return writeError(c, err, "failed to fetch registration")
+	}
+	return utils.SuccessResponse(c, "registration fetched", out)
+}
+
+// PUBLIC: GET /documents/:token
+// Download godoc
+// @Summary Download a registration document
+// @Description Returns the registration card or decision letter (PDF) of a tokenized link.
+// @Tags Registrations
+// @Produce application/pdf
+// @Param token path string true "Document token"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /documents/{token} [get]
+func (h *DocumentHandler) Download(c echo.Context) error {
+	name, pdf, err := h.svc.RenderByToken(c.Param("token"))
+	if err != nil {
+		return writeError(c, err, "failed to generate document")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="`+name+`"`)
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+// ADMIN: GET /admin/registrations/:id/documents
+// RegistrationDocuments godoc
+// @Summary Admin get document links of a registration
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Success 200 {object} DocumentLinksResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/documents [get]
+func (h *DocumentHandler) RegistrationDocuments(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	out, err := h.svc.GetRegistrationDocuments(id)
+	if err != nil {
+		return writeError(c, err, "failed to fetch document links")
+	}
+	return utils.SuccessResponse(c, "document links fetched", out)
+}
+
+// ADMIN: POST /admin/admission-periods/:id/documents
+// BulkDocuments godoc
+// @Summary Admin generate documents of an admission period
+// @Description Starts a background job that zips the registration cards (kind=card) or decision letters
+// @Description (kind=letter, only registrations with status done or rejected). Poll GET /admin/exports/{id} for the download link.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Param kind query string true "Document kind" Enums(card, letter)
+// @Success 202 {object} ExportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/documents [post]
+func (h *DocumentHandler) BulkDocuments(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	job, err := h.svc.StartBulkDocuments(adminID, id, c.QueryParam("kind"))
+	if err != nil {
+		return writeError(c, err, "failed to start document job")
+	}
+	return utils.AcceptedResponse(c, "document job started", job)
+}
+
+// ADMIN: GET /admin/letter-templates
+// LetterTemplates godoc
+// @Summary Admin list letter templates
+// @Description Returns the acceptance and rejection templates; custom=false means the built-in default is used.
+// @Tags Letters (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} LetterTemplateListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/letter-templates [get]
+func (h *DocumentHandler) LetterTemplates(c echo.Context) error {
+	items, err := h.svc.GetLetterTemplates()
+	if err != nil {
+		return writeError(c, err, "failed to fetch letter templates")
+	}
+	return utils.SuccessResponse(c, "letter templates fetched", items)
+}
+
+// ADMIN: PUT /admin/letter-templates/:kind
+// UpdateLetterTemplate godoc
+// @Summary Admin update a letter template
+// @Description Subject and body are Go text/template. Available fields: FullName, NISN, Email, TrackingCode,
+// @Description Gender, Program, OriginSchool, PeriodName, AcademicYear, SchoolName, Date.
+// @Tags Letters (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param kind path string true "Letter kind" Enums(acceptance, rejection)
+// @Param request body dto.LetterTemplateDTO true "Template payload"
+// @Success 200 {object} LetterTemplateResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/letter-templates/{kind} [put]
+func (h *DocumentHandler) UpdateLetterTemplate(c echo.Context) error {
+	var body dto.LetterTemplateDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	kind := models.LetterKind(strings.ToLower(c.Param("kind")))
+	out, err := h.svc.UpdateLetterTemplate(kind, body, adminID)
+	if err != nil {
+		return writeError(c, err, "failed to update letter template")
+	}
+	return utils.SuccessResponse(c, "letter template updated", out)
+}
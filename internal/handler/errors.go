@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"darulabror/internal/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// writeError reports a service error: domain errors map to their code's status and message,
+// anything else is logged and answered with a 500 carrying fallback.
+func writeError(c echo.Context, err error, fallback string) error {
+	return utils.WriteError(c, err, fallback)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportHandler struct {
+	svc service.ExportService
+}
+
+func NewExportHandler(svc service.ExportService) *ExportHandler {
+	return &ExportHandler{svc: svc}
+}
+
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:  "text/csv; charset=utf-8",
+	service.ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// parseRegistrationFilter reads admission_period_id, status, student_type, gender, program, from, to (YYYY-MM-DD, inclusive).
+func parseRegistrationFilter(c echo.Context) (repository.RegistrationFilter, error) {
+	f := repository.RegistrationFilter{
+		Status:      c.QueryParam("status"),
+		StudentType: c.QueryParam("student_type"),
+		Gender:      c.QueryParam("gender"),
+		Program:     c.QueryParam("program"),
+	}
+
+	if v := c.QueryParam("admission_period_id"); v != "" {
+		id64, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return f, errors.New("invalid admission_period_id")
+		}
+		f.AdmissionPeriodID = uint(id64)
+	}
+
+	switch models.RegistrationStatus(f.Status) {
+	case "", models.RegistrationStatusNew, models.RegistrationStatusValidate, models.RegistrationStatusProcess, models.RegistrationStatusDone,
+		models.RegistrationStatusWaitlisted, models.RegistrationStatusWithdrawn, models.RegistrationStatusRejected:
+	default:
+		return f, errors.New("invalid status")
+	}
+	switch models.StudentType(f.StudentType) {
+	case "", models.StudentNew, models.StudentTransfer:
+	default:
+		return f, errors.New("invalid student_type")
+	}
+	switch models.Gender(f.Gender) {
+	case "", models.Male, models.Female:
+	default:
+		return f, errors.New("invalid gender")
+	}
+	switch models.Program(f.Program) {
+	case "", models.ProgramBoarding, models.ProgramNonBoarding:
+	default:
+		return f, errors.New("invalid program")
+	}
+
+	if v := c.QueryParam("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return f, errors.New("invalid from (expected YYYY-MM-DD)")
+		}
+		f.From = &t
+	}
+	if v := c.QueryParam("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return f, errors.New("invalid to (expected YYYY-MM-DD)")
+		}
+		t = t.AddDate(0, 0, 1) // inclusive end date
+		f.To = &t
+	}
+	return f, nil
+}
+
+func splitCSVParam(v string) []string {
+	out := []string{}
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ADMIN: GET /admin/registrations/export
+// Registrations godoc
+// @Summary Admin export registrations (CSV/XLSX)
+// @Description Streams all matching registrations with Indonesian headers.
+// @Description Exports above 5000 rows (or async=true) run as a background job: 202 + job, poll GET /admin/exports/{id} for the download link.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Param format query string false "File format" Enums(csv, xlsx) default(csv)
+// @Param admission_period_id query int false "Filter by admission period"
+// @Param status query string false "Filter by status" Enums(new, validate, process, done, waitlisted, withdrawn, rejected)
+// @Param student_type query string false "Filter by student type" Enums(new, transfer)
+// @Param gender query string false "Filter by gender" Enums(male, female)
+// @Param program query string false "Filter by program" Enums(boarding, non_boarding)
+// @Param from query string false "Registered on/after (YYYY-MM-DD)"
+// @Param to query string false "Registered on/before (YYYY-MM-DD)"
+// @Param columns query string false "Comma-separated column keys, e.g. full_name,nisn,date_of_birth (default: all)"
+// @Param async query bool false "Force background job"
+// @Success 200 {file} file
+// @Success 202 {object} ExportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/export [get]
+func (h *ExportHandler) Registrations(c echo.Context) error {
+	filter, err := parseRegistrationFilter(c)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	opts := service.RegistrationExportOptions{
+		Format:  strings.ToLower(c.QueryParam("format")),
+		Columns: splitCSVParam(c.QueryParam("columns")),
+		Filter:  filter,
+	}
+	if opts.Format == "" {
+		opts.Format = service.ExportFormatCSV
+	}
+	if err := service.ValidateRegistrationExportOptions(opts); err != nil {
+		return utils.UnprocessableEntityResponse(c, err.Error())
+	}
+
+	async, _ := strconv.ParseBool(c.QueryParam("async"))
+	if !async {
+		count, err := h.svc.CountRegistrations(filter)
+		if err != nil {
+			return utils.InternalServerErrorResponse(c, "failed to export registrations")
+		}
+		async = count > service.ExportSyncMaxRows
+	}
+
+	if async {
+		adminID, _ := utils.GetAdminID(c)
+		job, err := h.svc.StartRegistrationExport(adminID, opts)
+		if err != nil {
+			return utils.InternalServerErrorResponse(c, "failed to start export job")
+		}
+		return utils.AcceptedResponse(c, "export job started", job)
+	}
+
+	fileName := "pendaftar_" + time.Now().Format("20060102_150405") + "." + opts.Format
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, exportContentTypes[opts.Format])
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+fileName+`"`)
+	res.WriteHeader(http.StatusOK)
+
+	rows, err := h.svc.ExportRegistrations(res, opts)
+	if err != nil {
+		// headers are already sent; the client gets a truncated file
+		logrus.WithError(err).Error("registration export aborted")
+		return nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"rows":   rows,
+		"format": opts.Format,
+	}).Info("registrations exported")
+	return nil
+}
+
+// ADMIN: GET /admin/exports/:id
+// GetJob godoc
+// @Summary Admin get export job
+// @Description When status is "done", download_url is a short-lived signed link to the file.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Export job ID" minimum(1)
+// @Success 200 {object} ExportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/exports/{id} [get]
+func (h *ExportHandler) GetJob(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	job, err := h.svc.GetExportJob(c.Request().Context(), uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to fetch export job")
+	}
+	return utils.SuccessResponse(c, "export job fetched", job)
+}
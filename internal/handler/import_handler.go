@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ImportHandler struct {
+	svc service.ImportService
+}
+
+func NewImportHandler(svc service.ImportService) *ImportHandler {
+	return &ImportHandler{svc: svc}
+}
+
+// ADMIN: POST /admin/registrations/import
+// Registrations godoc
+// @Summary Admin import registrations (CSV/XLSX)
+// @Description Columns may use the export headers ("Nama Lengkap") or keys ("full_name"); dates as dd/mm/yyyy or yyyy-mm-dd.
+// @Description Every row is validated like POST /registrations and checked for duplicate email/NISN (database and file).
+// @Description Valid rows are inserted in one transaction, invalid rows are reported and skipped. Use dry_run=true to only validate.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file (max 2000 rows)"
+// @Param format formData string false "File format (default: from file extension)" Enums(csv, xlsx)
+// @Param dry_run formData bool false "Validate only, do not insert"
+// @Param admission_period_id formData int false "Target admission period (default: the open period)"
+// @Param Accept-Language header string false "Language of the row validation messages (id or en, default id)"
+// @Success 200 {object} ImportReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/import [post]
+func (h *ImportHandler) Registrations(c echo.Context) error {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return utils.BadRequestResponse(c, "file is required")
+	}
+
+	format := strings.ToLower(c.FormValue("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fh.Filename)), ".")
+	}
+	dryRun, _ := strconv.ParseBool(c.FormValue("dry_run"))
+
+	var periodID uint64
+	if v := c.FormValue("admission_period_id"); v != "" {
+		if periodID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return utils.BadRequestResponse(c, "invalid admission_period_id")
+		}
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return utils.BadRequestResponse(c, "failed to open file")
+	}
+	defer f.Close()
+
+	report, err := h.svc.ImportRegistrations(f, format, utils.ValidationLanguage(c.Request().Header.Get("Accept-Language")), dryRun, uint(periodID))
+	if err != nil {
+		if errors.Is(err, service.ErrRegistrationClosed) {
+			return utils.UnprocessableEntityResponse(c, "no open admission period: pass admission_period_id")
+		}
+		if _, ok := utils.AsDomainError(err); ok {
+			return writeError(c, err, "failed to import registrations")
+		}
+		// anything else comes from the spreadsheet parser
+		logrus.WithError(err).WithField("file", fh.Filename).Warn("failed read import file")
+		return utils.BadRequestResponse(c, "invalid "+format+" file")
+	}
+
+	message := "registrations imported"
+	if dryRun {
+		message = "import validated (dry run)"
+	}
+	return utils.SuccessResponse(c, message, report)
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NoteHandler struct {
+	svc service.NoteService
+}
+
+func NewNoteHandler(svc service.NoteService) *NoteHandler {
+	return &NoteHandler{svc: svc}
+}
+
+func (h *NoteHandler) list(c echo.Context, subject models.NoteSubject) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, err := h.svc.GetNotes(subject, id)
+	if err != nil {
+		return writeError(c, err, "failed to fetch notes")
+	}
+	return utils.SuccessResponse(c, "notes fetched", items)
+}
+
+func (h *NoteHandler) add(c echo.Context, subject models.NoteSubject) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.AdminNoteCreateDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	note, err := h.svc.AddNote(subject, id, adminID, body.Body)
+	if err != nil {
+		return writeError(c, err, "failed to create note")
+	}
+	return utils.CreatedResponse(c, "note created", note)
+}
+
+func (h *NoteHandler) assign(c echo.Context, subject models.NoteSubject) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.AssigneeDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	if err := h.svc.Assign(subject, id, body.AdminID, adminID); err != nil {
+		return writeError(c, err, "failed to update assignee")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ADMIN: GET /admin/registrations/:id/notes
+// RegistrationNotes godoc
+// @Summary Admin list internal notes of a registration
+// @Description Oldest first. Notes are markdown and only visible to admins.
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Success 200 {object} AdminNoteListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/notes [get]
+func (h *NoteHandler) RegistrationNotes(c echo.Context) error {
+	return h.list(c, models.NoteSubjectRegistration)
+}
+
+// ADMIN: POST /admin/registrations/:id/notes
+// AddRegistrationNote godoc
+// @Summary Admin add an internal note to a registration
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Param request body dto.AdminNoteCreateDTO true "Note (markdown)"
+// @Success 201 {object} AdminNoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/notes [post]
+func (h *NoteHandler) AddRegistrationNote(c echo.Context) error {
+	return h.add(c, models.NoteSubjectRegistration)
+}
+
+// ADMIN: PUT /admin/registrations/:id/assignee
+// AssignRegistration godoc
+// @Summary Admin assign a registration to an admin
+// @Description admin_id null unassigns. List with ?assignee=me to see your own queue.
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Param request body dto.AssigneeDTO true "Assignee"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/assignee [put]
+func (h *NoteHandler) AssignRegistration(c echo.Context) error {
+	return h.assign(c, models.NoteSubjectRegistration)
+}
+
+// ADMIN: GET /admin/contacts/:id/notes
+// ContactNotes godoc
+// @Summary Admin list internal notes of a contact message
+// @Description Oldest first. Notes are markdown and only visible to admins.
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Contact ID" minimum(1)
+// @Success 200 {object} AdminNoteListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/contacts/{id}/notes [get]
+func (h *NoteHandler) ContactNotes(c echo.Context) error {
+	return h.list(c, models.NoteSubjectContact)
+}
+
+// ADMIN: POST /admin/contacts/:id/notes
+// AddContactNote godoc
+// @Summary Admin add an internal note to a contact message
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID" minimum(1)
+// @Param request body dto.AdminNoteCreateDTO true "Note (markdown)"
+// @Success 201 {object} AdminNoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/contacts/{id}/notes [post]
+func (h *NoteHandler) AddContactNote(c echo.Context) error {
+	return h.add(c, models.NoteSubjectContact)
+}
+
+// ADMIN: PUT /admin/contacts/:id/assignee
+// AssignContact godoc
+// @Summary Admin assign a contact message to an admin
+// @Description admin_id null unassigns. List with ?assignee=me to see your own queue.
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact ID" minimum(1)
+// @Param request body dto.AssigneeDTO true "Assignee"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/contacts/{id}/assignee [put]
+func (h *NoteHandler) AssignContact(c echo.Context) error {
+	return h.assign(c, models.NoteSubjectContact)
+}
+
+// ADMIN: DELETE /admin/notes/:id
+// DeleteNote godoc
+// @Summary Admin delete a note
+// @Description Needs registrations.edit or contacts.reply. Only the author, or admins with notes.delete.
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Note ID" minimum(1)
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/notes/{id} [delete]
+func (h *NoteHandler) DeleteNote(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	canDeleteAny := utils.HasPermission(c, models.PermNotesDelete)
+	if err := h.svc.DeleteNote(id, adminID, canDeleteAny); err != nil {
+		return writeError(c, err, "failed to delete note")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ADMIN: GET /admin/assignees
+// Assignees godoc
+// @Summary Admin list admins that items can be assigned to
+// @Description Active admins by username. Needs registrations.edit or contacts.reply.
+// @Tags Notes (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} AssigneeListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/assignees [get]
+func (h *NoteHandler) Assignees(c echo.Context) error {
+	items, err := h.svc.GetAssignees()
+	if err != nil {
+		return writeError(c, err, "failed to fetch assignees")
+	}
+	return utils.SuccessResponse(c, "assignees fetched", items)
+}
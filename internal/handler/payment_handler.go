@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"io"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxWebhookBody is far above any gateway notification.
+const maxWebhookBody = 64 << 10
+
+type PaymentHandler struct {
+	svc service.PaymentService
+}
+
+func NewPaymentHandler(svc service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{svc: svc}
+}
+
+// writeError keeps the payment-specific wording for rejected proof files.
+func (h *PaymentHandler) writeError(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, service.ErrUploadTypeNotAllowed) {
+		return utils.UnprocessableEntityResponse(c, "file must be a JPEG, PNG, WebP or PDF")
+	}
+	return writeError(c, err, fallback)
+}
+
+// PUBLIC: POST /registrations/payment
+// CreatePayment godoc
+// @Summary Pay the registration fee
+// @Description Opens an invoice at the payment gateway (status must be validate) and returns its payment_url.
+// @Description An open or paid invoice is returned as is (200); a new one is 201.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param request body dto.PaymentRequestDTO true "Tracking code and NISN"
+// @Success 200 {object} InvoiceResponse
+// @Success 201 {object} InvoiceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /registrations/payment [post]
+func (h *PaymentHandler) CreatePayment(c echo.Context) error {
+	var body dto.PaymentRequestDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	inv, created, err := h.svc.GetOrCreateInvoice(c.Request().Context(), body.TrackingCode, body.NISN)
+	if err != nil {
+		return h.writeError(c, err, "failed to create invoice")
+	}
+	if created {
+		return utils.CreatedResponse(c, "invoice created", inv)
+	}
+	return utils.SuccessResponse(c, "invoice fetched", inv)
+}
+
+// PUBLIC: GET /registrations/payment
+// GetPayment godoc
+// @Summary Get the registration fee invoice
+// @Tags Payments
+// @Produce json
+// @Param code query string true "Tracking code"
+// @Param nisn query string true "NISN"
+// @Success 200 {object} InvoiceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/payment [get]
+func (h *PaymentHandler) GetPayment(c echo.Context) error {
+	code, nisn := c.QueryParam("code"), c.QueryParam("nisn")
+	if code == "" || nisn == "" {
+		return utils.BadRequestResponse(c, "code and nisn are required")
+	}
+
+	inv, err := h.svc.GetLatestInvoice(code, nisn)
+	if err != nil {
+		return h.writeError(c, err, "failed to fetch invoice")
+	}
+	return utils.SuccessResponse(c, "invoice fetched", inv)
+}
+
+// PUBLIC: POST /payments/webhook/:provider
+// Webhook godoc
+// @Summary Payment gateway notification
+// @Description Called by the gateway (midtrans, xendit or fake). The signature is verified; a notification
+// @Description delivered twice is applied once. A paid invoice moves the registration from validate to process.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider" Enums(midtrans, xendit, fake)
+// @Success 200 {string} string "OK"
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payments/webhook/{provider} [post]
+func (h *PaymentHandler) Webhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+
+	if err := h.svc.HandleWebhook(c.Param("provider"), c.Request().Header, body); err != nil {
+		return h.writeError(c, err, "failed to process payment notification")
+	}
+	return utils.SuccessResponse(c, "notification processed", nil)
+}
+
+// PUBLIC: GET /payments/fake/:number
+// FakePay godoc
+// @Summary Pay an invoice of the fake provider
+// @Description Only with PAYMENT_PROVIDER=fake (local development): marks the invoice paid through a signed webhook.
+// @Tags Payments
+// @Produce json
+// @Param number path string true "Invoice number"
+// @Success 200 {string} string "OK"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payments/fake/{number} [get]
+func (h *PaymentHandler) FakePay(c echo.Context) error {
+	if err := h.svc.SimulateFakePayment(c.Param("number")); err != nil {
+		return h.writeError(c, err, "failed to pay invoice")
+	}
+	return utils.SuccessResponse(c, "invoice paid", nil)
+}
+
+// ADMIN: GET /admin/registrations/:id/invoices
+// AdminInvoices godoc
+// @Summary Admin list invoices of a registration
+// @Tags Payments (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Success 200 {object} InvoiceListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/invoices [get]
+func (h *PaymentHandler) AdminInvoices(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, err := h.svc.GetInvoices(id)
+	if err != nil {
+		return h.writeError(c, err, "failed to fetch invoices")
+	}
+	return utils.SuccessResponse(c, "invoices fetched", items)
+}
+
+// PUBLIC: POST /registrations/payment/proof
+// UploadProof godoc
+// @Summary Upload a bank transfer receipt
+// @Description For families paying by bank transfer (status must be validate). The receipt is checked by an admin;
+// @Description the applicant is emailed the decision. Only one receipt can wait for verification at a time.
+// @Tags Payments
+// @Accept multipart/form-data
+// @Produce json
+// @Param tracking_code formData string true "Tracking code"
+// @Param nisn formData string true "NISN"
+// @Param file formData file true "Receipt (JPEG, PNG, WebP or PDF, max 5 MB)"
+// @Success 201 {object} PaymentProofResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/payment/proof [post]
+func (h *PaymentHandler) UploadProof(c echo.Context) error {
+	code, nisn := c.FormValue("tracking_code"), c.FormValue("nisn")
+	if code == "" || nisn == "" {
+		return utils.BadRequestResponse(c, "tracking_code and nisn are required")
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return utils.BadRequestResponse(c, "file is required")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return utils.BadRequestResponse(c, "failed to open file")
+	}
+	defer f.Close()
+
+	proof, err := h.svc.UploadProof(c.Request().Context(), code, nisn, f, fh.Size)
+	if err != nil {
+		return h.writeError(c, err, "failed to upload payment proof")
+	}
+	return utils.CreatedResponse(c, "payment proof uploaded", proof)
+}
+
+// PUBLIC: GET /registrations/payment/proof
+// GetProof godoc
+// @Summary Get the latest bank transfer receipt status
+// @Description status is pending, approved or rejected (with reject_reason).
+// @Tags Payments
+// @Produce json
+// @Param code query string true "Tracking code"
+// @Param nisn query string true "NISN"
+// @Success 200 {object} PaymentProofResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/payment/proof [get]
+func (h *PaymentHandler) GetProof(c echo.Context) error {
+	code, nisn := c.QueryParam("code"), c.QueryParam("nisn")
+	if code == "" || nisn == "" {
+		return utils.BadRequestResponse(c, "code and nisn are required")
+	}
+
+	proof, err := h.svc.GetLatestProof(code, nisn)
+	if err != nil {
+		return h.writeError(c, err, "failed to fetch payment proof")
+	}
+	return utils.SuccessResponse(c, "payment proof fetched", proof)
+}
+
+// ADMIN: GET /admin/payments/pending
+// PendingProofs godoc
+// @Summary Admin list transfer receipts waiting for verification
+// @Description Oldest first by default. file_url is a signed link to the receipt (valid 15 minutes).
+// @Tags Payments (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page (offset mode)" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "Keyset cursor; empty for the first page"
+// @Param order query string false "Order" Enums(asc, desc) default(asc)
+// @Success 200 {object} PaymentProofListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/payments/pending [get]
+func (h *PaymentHandler) PendingProofs(c echo.Context) error {
+	q, err := utils.ParseListQuery(c, repository.PaymentProofListSpec.SortFields(), nil)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	// a queue: oldest first unless asked otherwise
+	if c.QueryParam("order") == "" {
+		q.Desc = false
+	}
+	q.Statuses = []string{string(models.PaymentProofPending)}
+
+	items, page, err := h.svc.GetProofQueue(c.Request().Context(), q)
+	if err != nil {
+		return h.writeError(c, err, "failed to fetch payment proofs")
+	}
+	return utils.SuccessResponse(c, "payment proofs fetched", map[string]interface{}{
+		"items": items,
+		"meta":  utils.ListMeta(q, page),
+	})
+}
+
+// ADMIN: POST /admin/payments/:id/approve
+// ApproveProof godoc
+// @Summary Admin approve a transfer receipt
+// @Description Records the fee as paid (invoice provider "manual"), moves a registration in validate to process
+// @Description and emails the applicant.
+// @Tags Payments (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Payment proof ID" minimum(1)
+// @Success 200 {object} PaymentProofResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/payments/{id}/approve [post]
+func (h *PaymentHandler) ApproveProof(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	proof, err := h.svc.ApproveProof(id, adminID)
+	if err != nil {
+		return h.writeError(c, err, "failed to approve payment proof")
+	}
+	return utils.SuccessResponse(c, "payment proof approved", proof)
+}
+
+// ADMIN: POST /admin/payments/:id/reject
+// RejectProof godoc
+// @Summary Admin reject a transfer receipt
+// @Description The reason is emailed to the applicant, who can upload a new receipt.
+// @Tags Payments (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment proof ID" minimum(1)
+// @Param request body dto.PaymentProofRejectDTO true "Reject reason"
+// @Success 200 {object} PaymentProofResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/payments/{id}/reject [post]
+func (h *PaymentHandler) RejectProof(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.PaymentProofRejectDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	proof, err := h.svc.RejectProof(id, adminID, body.Reason)
+	if err != nil {
+		return h.writeError(c, err, "failed to reject payment proof")
+	}
+	return utils.SuccessResponse(c, "payment proof rejected", proof)
+}
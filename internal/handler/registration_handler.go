@@ -3,6 +3,7 @@ package handler
 import (
 	"darulabror/internal/dto"
 	"darulabror/internal/models"
+	"darulabror/internal/repository"
 	"darulabror/internal/service"
 	"darulabror/internal/utils"
 	"net/http"
@@ -12,6 +13,16 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var registrationStatuses = []string{
+	string(models.RegistrationStatusNew),
+	string(models.RegistrationStatusValidate),
+	string(models.RegistrationStatusProcess),
+	string(models.RegistrationStatusDone),
+	string(models.RegistrationStatusWaitlisted),
+	string(models.RegistrationStatusWithdrawn),
+	string(models.RegistrationStatusRejected),
+}
+
 type RegistrationHandler struct {
 	svc service.RegistrationService
 }
@@ -22,12 +33,16 @@ func NewRegistrationHandler(svc service.RegistrationService) *RegistrationHandle
 
 // Create godoc
 // @Summary Create registration
+// @Description Only accepted while an admission period is open; email and NISN are unique per period.
+// @Description When the gender/program quota is full the registration is accepted with status "waitlisted".
 // @Tags Registrations (Public)
 // @Accept json
 // @Produce json
 // @Param request body dto.RegistrationDTO true "Registration payload"
-// @Success 201 {string} string "Created"
+// @Success 201 {object} RegistrationReceiptResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /registrations [post]
@@ -37,15 +52,15 @@ func (h *RegistrationHandler) Create(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
-	if err := h.svc.CreateRegistration(body); err != nil {
-		logrus.WithError(err).Error("failed create registration")
-		return utils.InternalServerErrorResponse(c, "failed to process registration")
+	receipt, err := h.svc.CreateRegistration(body)
+	if err != nil {
+		return writeError(c, err, "failed to process registration")
 	}
 
-	return c.NoContent(http.StatusCreated)
+	return utils.CreatedResponse(c, "registration created", receipt)
 }
 
 // ADMIN: GET /admin/registrations
@@ -56,17 +71,31 @@ func (h *RegistrationHandler) Create(c echo.Context) error {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(10)
-// @Param status query string false "Filter by status" Enums(new, validate, process, done)
+// @Param cursor query string false "Keyset pagination: empty for the first page, then meta.next_cursor (replaces page)"
+// @Param with_total query bool false "Also count total in cursor mode"
+// @Param q query string false "Search full name, email, NISN, origin school or tracking code"
+// @Param status query string false "Filter by status, comma-separated (new, validate, process, done, waitlisted, withdrawn, rejected)"
+// @Param assignee query string false "Assigned admin: me (my queue), none or an admin ID"
+// @Param from query string false "Registered on/after (YYYY-MM-DD)"
+// @Param to query string false "Registered on/before (YYYY-MM-DD)"
+// @Param sort query string false "Sort field" Enums(id, created_at, full_name, status) default(id)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
 // @Success 200 {object} RegistrationListResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/registrations [get]
 func (h *RegistrationHandler) AdminList(c echo.Context) error {
-	page, limit := utils.ParsePagination(c)
-	status := c.QueryParam("status")
-	
-	items, total, err := h.svc.GetAllRegistrations(page, limit, status)
+	q, err := utils.ParseListQuery(c, repository.RegistrationListSpec.SortFields(), registrationStatuses)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := utils.ParseAssignee(c, &q); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, page, err := h.svc.GetAllRegistrations(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed list registrations")
 		return utils.InternalServerErrorResponse(c, "failed to fetch registrations")
@@ -74,11 +103,7 @@ func (h *RegistrationHandler) AdminList(c echo.Context) error {
 
 	return utils.SuccessResponse(c, "registrations fetched", map[string]interface{}{
 		"items": items,
-		"meta": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"meta":  utils.ListMeta(q, page),
 	})
 }
 
@@ -103,15 +128,103 @@ func (h *RegistrationHandler) AdminGetByID(c echo.Context) error {
 
 	item, err := h.svc.GetRegistrationByID(uint(id64))
 	if err != nil {
-		logrus.WithError(err).Error("failed get registration by id")
-		return utils.NotFoundResponse(c, "registration not found")
+		return writeError(c, err, "failed to fetch registration")
 	}
 	return utils.SuccessResponse(c, "registration fetched", item)
 }
 
+// ADMIN: PUT /admin/registrations/:id
+// AdminUpdate godoc
+// @Summary Admin edit registration
+// @Description Replaces the data with the same rules as POST /registrations. version must be the one last read
+// @Description (409 when someone edited in between). Status, admission period and tracking code are not changed here.
+// @Description Each changed field is recorded (GET /admin/registrations/{id}/changes).
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Param request body dto.RegistrationDTO true "Registration payload with version"
+// @Success 200 {object} SuccessResponse[dto.RegistrationDTO]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id} [put]
+func (h *RegistrationHandler) AdminUpdate(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	var body dto.RegistrationDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	return h.adminSave(c, uint(id64), body)
+}
+
+// ADMIN: PATCH /admin/registrations/:id
+// AdminPatch godoc
+// @Summary Admin partially edit registration
+// @Description Only the fields present in the body change; the result must still pass the POST /registrations rules.
+// @Description version is required as for PUT.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Param request body RegistrationPatchRequest true "Changed fields with version"
+// @Success 200 {object} SuccessResponse[dto.RegistrationDTO]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id} [patch]
+func (h *RegistrationHandler) AdminPatch(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	// the body is decoded over the current data, so absent fields keep their value
+	body, err := h.svc.GetRegistrationByID(uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to fetch registration")
+	}
+	body.Version = 0
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	return h.adminSave(c, uint(id64), body)
+}
+
+func (h *RegistrationHandler) adminSave(c echo.Context, id uint, body dto.RegistrationDTO) error {
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+	if body.Version <= 0 {
+		return utils.UnprocessableEntityResponse(c, "version is required")
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	item, err := h.svc.UpdateRegistration(id, body, adminID)
+	if err != nil {
+		return writeError(c, err, "failed to update registration")
+	}
+	return utils.SuccessResponse(c, "registration updated", item)
+}
+
 // ADMIN: DELETE /admin/registrations/:id
 // AdminDelete godoc
 // @Summary Admin delete registration
+// @Description Deleting a registration that holds a seat promotes the next waitlisted registration.
 // @Tags Registrations (Admin)
 // @Security BearerAuth
 // @Produce json
@@ -120,6 +233,7 @@ func (h *RegistrationHandler) AdminGetByID(c echo.Context) error {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/registrations/{id} [delete]
 func (h *RegistrationHandler) AdminDelete(c echo.Context) error {
@@ -129,8 +243,7 @@ func (h *RegistrationHandler) AdminDelete(c echo.Context) error {
 	}
 
 	if err := h.svc.DeleteRegistration(uint(id64)); err != nil {
-		logrus.WithError(err).Error("failed delete registration")
-		return utils.InternalServerErrorResponse(c, "failed to delete registration")
+		return writeError(c, err, "failed to delete registration")
 	}
 	return c.NoContent(http.StatusNoContent)
 }
@@ -138,6 +251,8 @@ func (h *RegistrationHandler) AdminDelete(c echo.Context) error {
 // ADMIN: PATCH /admin/registrations/:id/status
 // AdminUpdateStatus godoc
 // @Summary Admin update registration status
+// @Description Moving a waitlisted/withdrawn/rejected registration back requires a free seat (409 otherwise).
+// @Description Moving a seat holder to waitlisted/withdrawn/rejected promotes the next waitlisted registration.
 // @Tags Registrations (Admin)
 // @Security BearerAuth
 // @Accept json
@@ -149,6 +264,7 @@ func (h *RegistrationHandler) AdminDelete(c echo.Context) error {
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/registrations/{id}/status [patch]
@@ -163,14 +279,164 @@ func (h *RegistrationHandler) AdminUpdateStatus(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid body")
 	}
 	if err := c.Validate(&body); err != nil {
-		return utils.UnprocessableEntityResponse(c, err.Error())
+		return utils.ValidationErrorResponse(c, err)
 	}
 
-	if err := h.svc.UpdateRegistrationStatus(uint(id64), models.RegistrationStatus(body.Status)); err != nil {
-		if err.Error() == "registration not found" {
-			return utils.NotFoundResponse(c, err.Error())
-		}
-		return utils.InternalServerErrorResponse(c, err.Error())
+	adminID, _ := utils.GetAdminID(c)
+	if err := h.svc.UpdateRegistrationStatus(uint(id64), models.RegistrationStatus(body.Status), adminID); err != nil {
+		return writeError(c, err, "failed to update registration status")
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+// ADMIN: GET /admin/registrations/:id/history
+// AdminStatusHistory godoc
+// @Summary Admin get registration status history
+// @Description Every status change, oldest first; reason is registered, quota_full, admin or promoted.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Success 200 {object} RegistrationStatusHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/history [get]
+func (h *RegistrationHandler) AdminStatusHistory(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	items, err := h.svc.GetStatusHistory(uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to fetch status history")
+	}
+	return utils.SuccessResponse(c, "status history fetched", items)
+}
+
+// PUBLIC: POST /registrations/edit-link
+// RequestEditLink godoc
+// @Summary Request an edit link by email
+// @Description Sends a link to the email address of the registration (valid REGISTRATION_EDIT_LINK_TTL, default 1 hour).
+// @Description Only while the status is new.
+// @Tags Registrations (Public)
+// @Accept json
+// @Produce json
+// @Param request body dto.EditLinkRequestDTO true "Tracking code and NISN"
+// @Success 200 {object} EditLinkSentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/edit-link [post]
+func (h *RegistrationHandler) RequestEditLink(c echo.Context) error {
+	var body dto.EditLinkRequestDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	sent, err := h.svc.RequestEditLink(c.Request().Context(), body.TrackingCode, body.NISN)
+	if err != nil {
+		return writeError(c, err, "failed to send edit link")
+	}
+	return utils.SuccessResponse(c, "edit link sent", sent)
+}
+
+// PUBLIC: GET /registrations/edit
+// GetForEdit godoc
+// @Summary Get a registration through its edit link
+// @Description Returns the current data to prefill the edit form.
+// @Tags Registrations (Public)
+// @Produce json
+// @Param token query string true "Token of the emailed edit link"
+// @Success 200 {object} SuccessResponse[dto.RegistrationDTO]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/edit [get]
+func (h *RegistrationHandler) GetForEdit(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return utils.BadRequestResponse(c, "token is required")
+	}
+
+	item, err := h.svc.GetForEdit(token)
+	if err != nil {
+		return writeError(c, err, "failed to fetch registration")
+	}
+	return utils.SuccessResponse(c, "registration fetched", item)
+}
+
+// PUBLIC: PUT /registrations/edit
+// UpdateByApplicant godoc
+// @Summary Edit a registration through its edit link
+// @Description Same rules as POST /registrations; email and NISN must stay unique within the admission period.
+// @Description Status, admission period and tracking code cannot be changed. Every changed field is recorded.
+// @Tags Registrations (Public)
+// @Accept json
+// @Produce json
+// @Param token query string true "Token of the emailed edit link"
+// @Param request body dto.RegistrationDTO true "Registration payload"
+// @Success 200 {object} SuccessResponse[dto.RegistrationDTO]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /registrations/edit [put]
+func (h *RegistrationHandler) UpdateByApplicant(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return utils.BadRequestResponse(c, "token is required")
+	}
+
+	var body dto.RegistrationDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.UpdateByApplicant(token, body)
+	if err != nil {
+		return writeError(c, err, "failed to update registration")
+	}
+	return utils.SuccessResponse(c, "registration updated", item)
+}
+
+// ADMIN: GET /admin/registrations/:id/changes
+// AdminChanges godoc
+// @Summary Admin get registration field changes
+// @Description Every edited field with old and new value, newest first; source is applicant or admin.
+// @Tags Registrations (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Success 200 {object} RegistrationChangeListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/changes [get]
+func (h *RegistrationHandler) AdminChanges(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	items, err := h.svc.GetChanges(uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to fetch registration changes")
+	}
+	return utils.SuccessResponse(c, "registration changes fetched", items)
+}
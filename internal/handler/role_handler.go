@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	svc service.RoleService
+}
+
+func NewRoleHandler(svc service.RoleService) *RoleHandler {
+	return &RoleHandler{svc: svc}
+}
+
+// ADMIN: GET /admin/permissions
+// Permissions godoc
+// @Summary Admin list every permission a role can grant
+// @Tags Roles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} PermissionListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/permissions [get]
+func (h *RoleHandler) Permissions(c echo.Context) error {
+	return utils.SuccessResponse(c, "permissions fetched", models.Permissions)
+}
+
+// ADMIN: GET /admin/roles
+// List godoc
+// @Summary Admin list roles
+// @Tags Roles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} RoleListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/roles [get]
+func (h *RoleHandler) List(c echo.Context) error {
+	items, err := h.svc.ListRoles()
+	if err != nil {
+		return writeError(c, err, "failed to fetch roles")
+	}
+	return utils.SuccessResponse(c, "roles fetched", items)
+}
+
+// ADMIN: GET /admin/roles/:name
+// Get godoc
+// @Summary Admin get role
+// @Tags Roles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} RoleResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/roles/{name} [get]
+func (h *RoleHandler) Get(c echo.Context) error {
+	item, err := h.svc.GetRole(models.Role(c.Param("name")))
+	if err != nil {
+		return writeError(c, err, "failed to fetch role")
+	}
+	return utils.SuccessResponse(c, "role fetched", item)
+}
+
+// ADMIN: POST /admin/roles
+// Create godoc
+// @Summary Admin create role
+// @Description Only permissions the requester holds can be granted.
+// @Tags Roles (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.RoleInputDTO true "Role payload"
+// @Success 201 {object} RoleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/roles [post]
+func (h *RoleHandler) Create(c echo.Context) error {
+	var body dto.RoleInputDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.CreateRole(utils.GetPermissions(c), body)
+	if err != nil {
+		return writeError(c, err, "failed to create role")
+	}
+	return utils.CreatedResponse(c, "role created", item)
+}
+
+// ADMIN: PUT /admin/roles/:name
+// Update godoc
+// @Summary Admin update role
+// @Description Replaces the description and permissions; superadmin cannot be changed. Admins of the role get the new permissions at their next login.
+// @Tags Roles (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param request body dto.RoleInputDTO true "Role payload (name ignored)"
+// @Success 200 {object} RoleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/roles/{name} [put]
+func (h *RoleHandler) Update(c echo.Context) error {
+	var body dto.RoleInputDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.UpdateRole(utils.GetPermissions(c), models.Role(c.Param("name")), body)
+	if err != nil {
+		return writeError(c, err, "failed to update role")
+	}
+	return utils.SuccessResponse(c, "role updated", item)
+}
+
+// ADMIN: DELETE /admin/roles/:name
+// Delete godoc
+// @Summary Admin delete role
+// @Description System roles and roles still given to admins cannot be deleted.
+// @Tags Roles (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/roles/{name} [delete]
+func (h *RoleHandler) Delete(c echo.Context) error {
+	if err := h.svc.DeleteRole(utils.GetPermissions(c), models.Role(c.Param("name"))); err != nil {
+		return writeError(c, err, "failed to delete role")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,502 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SelectionHandler struct {
+	svc service.SelectionService
+}
+
+func NewSelectionHandler(svc service.SelectionService) *SelectionHandler {
+	return &SelectionHandler{svc: svc}
+}
+
+func parseID(c echo.Context, name string) (uint, error) {
+	id64, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id64 == 0 {
+		return 0, errors.New("invalid " + name)
+	}
+	return uint(id64), nil
+}
+
+// ADMIN: GET /admin/admission-periods/:id/selection-components
+// Components godoc
+// @Summary Admin get selection components
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Success 200 {object} SelectionComponentListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/selection-components [get]
+func (h *SelectionHandler) Components(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, err := h.svc.GetComponents(id)
+	if err != nil {
+		return writeError(c, err, "failed to fetch selection components")
+	}
+	return utils.SuccessResponse(c, "selection components fetched", items)
+}
+
+// ADMIN: PUT /admin/admission-periods/:id/selection-components
+// SetComponents godoc
+// @Summary Admin replace selection components
+// @Description Components are matched by key; weights must add up to 100. Components with scores cannot be removed.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Param request body dto.SelectionComponentsDTO true "Components payload"
+// @Success 200 {object} SelectionComponentListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/selection-components [put]
+func (h *SelectionHandler) SetComponents(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.SelectionComponentsDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	items, err := h.svc.SetComponents(id, body.Components)
+	if err != nil {
+		return writeError(c, err, "failed to update selection components")
+	}
+	return utils.SuccessResponse(c, "selection components updated", items)
+}
+
+// ADMIN: GET /admin/selection-sessions
+// ListSessions godoc
+// @Summary Admin list selection sessions of an admission period
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param admission_period_id query int true "Admission period ID"
+// @Success 200 {object} SelectionSessionListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions [get]
+func (h *SelectionHandler) ListSessions(c echo.Context) error {
+	periodID, err := strconv.ParseUint(c.QueryParam("admission_period_id"), 10, 64)
+	if err != nil || periodID == 0 {
+		return utils.BadRequestResponse(c, "admission_period_id is required")
+	}
+
+	items, err := h.svc.GetSessions(uint(periodID))
+	if err != nil {
+		return writeError(c, err, "failed to fetch selection sessions")
+	}
+	return utils.SuccessResponse(c, "selection sessions fetched", items)
+}
+
+// ADMIN: POST /admin/selection-sessions
+// CreateSession godoc
+// @Summary Admin create selection session
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.SelectionSessionDTO true "Session payload"
+// @Success 201 {object} SelectionSessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions [post]
+func (h *SelectionHandler) CreateSession(c echo.Context) error {
+	var body dto.SelectionSessionDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.CreateSession(body)
+	if err != nil {
+		return writeError(c, err, "failed to create selection session")
+	}
+	return utils.CreatedResponse(c, "selection session created", item)
+}
+
+// ADMIN: GET /admin/selection-sessions/:id
+// GetSession godoc
+// @Summary Admin get selection session
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Session ID" minimum(1)
+// @Success 200 {object} SelectionSessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions/{id} [get]
+func (h *SelectionHandler) GetSession(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	item, err := h.svc.GetSessionByID(id)
+	if err != nil {
+		return writeError(c, err, "failed to fetch selection session")
+	}
+	return utils.SuccessResponse(c, "selection session fetched", item)
+}
+
+// ADMIN: PUT /admin/selection-sessions/:id
+// UpdateSession godoc
+// @Summary Admin update selection session
+// @Description The admission period of a session cannot change; capacity cannot go below the assigned applicants.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID" minimum(1)
+// @Param request body dto.SelectionSessionDTO true "Session payload"
+// @Success 200 {object} SelectionSessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions/{id} [put]
+func (h *SelectionHandler) UpdateSession(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.SelectionSessionDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	item, err := h.svc.UpdateSession(id, body)
+	if err != nil {
+		return writeError(c, err, "failed to update selection session")
+	}
+	return utils.SuccessResponse(c, "selection session updated", item)
+}
+
+// ADMIN: DELETE /admin/selection-sessions/:id
+// DeleteSession godoc
+// @Summary Admin delete selection session
+// @Description Assigned applicants become unassigned; scores are kept.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Session ID" minimum(1)
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions/{id} [delete]
+func (h *SelectionHandler) DeleteSession(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := h.svc.DeleteSession(id); err != nil {
+		return writeError(c, err, "failed to delete selection session")
+	}
+	return utils.NoContentResponse(c)
+}
+
+// ADMIN: GET /admin/selection-sessions/:id/candidates
+// SessionCandidates godoc
+// @Summary Admin list applicants assigned to a selection session
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Session ID" minimum(1)
+// @Success 200 {object} SelectionCandidateListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions/{id}/candidates [get]
+func (h *SelectionHandler) SessionCandidates(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	items, err := h.svc.GetSessionCandidates(id)
+	if err != nil {
+		return writeError(c, err, "failed to fetch session candidates")
+	}
+	return utils.SuccessResponse(c, "session candidates fetched", items)
+}
+
+// ADMIN: POST /admin/selection-sessions/:id/assignments
+// Assign godoc
+// @Summary Admin assign applicants to a selection session
+// @Description Registrations must belong to the session's period and have status validate, process or done.
+// @Description An applicant already assigned elsewhere is moved; 409 when the session capacity would be exceeded.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID" minimum(1)
+// @Param request body dto.SelectionAssignDTO true "Assignment payload"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions/{id}/assignments [post]
+func (h *SelectionHandler) Assign(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.SelectionAssignDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	if err := h.svc.Assign(id, body.RegistrationIDs); err != nil {
+		return writeError(c, err, "failed to assign applicants")
+	}
+	return utils.NoContentResponse(c)
+}
+
+// ADMIN: DELETE /admin/selection-sessions/:id/assignments/:registration_id
+// Unassign godoc
+// @Summary Admin remove an applicant from a selection session
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Session ID" minimum(1)
+// @Param registration_id path int true "Registration ID" minimum(1)
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/selection-sessions/{id}/assignments/{registration_id} [delete]
+func (h *SelectionHandler) Unassign(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	regID, err := parseID(c, "registration_id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := h.svc.Unassign(id, regID); err != nil {
+		return writeError(c, err, "failed to unassign applicant")
+	}
+	return utils.NoContentResponse(c)
+}
+
+// ADMIN: GET /admin/registrations/:id/scores
+// Scores godoc
+// @Summary Admin get selection scores of a registration
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Success 200 {object} SelectionResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/scores [get]
+func (h *SelectionHandler) Scores(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	item, err := h.svc.GetResult(id)
+	if err != nil {
+		return writeError(c, err, "failed to fetch selection scores")
+	}
+	return utils.SuccessResponse(c, "selection scores fetched", item)
+}
+
+// ADMIN: PUT /admin/registrations/:id/scores
+// SaveScores godoc
+// @Summary Admin enter selection scores of a registration
+// @Description Scores are per component (0..max_score); components not sent keep their score.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Registration ID" minimum(1)
+// @Param request body dto.SelectionScoresDTO true "Scores payload"
+// @Success 200 {object} SelectionResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/registrations/{id}/scores [put]
+func (h *SelectionHandler) SaveScores(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.SelectionScoresDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	item, err := h.svc.SaveScores(id, body.Scores, adminID)
+	if err != nil {
+		return writeError(c, err, "failed to save selection scores")
+	}
+	return utils.SuccessResponse(c, "selection scores saved", item)
+}
+
+// ADMIN: GET /admin/admission-periods/:id/ranking
+// Ranking godoc
+// @Summary Admin get the selection ranking of an admission period
+// @Description Applicants with status validate, process or done, ordered by weighted final score; ties go to the earlier registration.
+// @Description Applicants with missing scores have rank 0 and are listed last.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Param gender query string false "Rank within gender" Enums(male, female)
+// @Param program query string false "Rank within program" Enums(boarding, non_boarding)
+// @Success 200 {object} RankingListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/ranking [get]
+func (h *SelectionHandler) Ranking(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	gender := models.Gender(c.QueryParam("gender"))
+	switch gender {
+	case "", models.Male, models.Female:
+	default:
+		return utils.BadRequestResponse(c, "invalid gender")
+	}
+	program := models.Program(c.QueryParam("program"))
+	switch program {
+	case "", models.ProgramBoarding, models.ProgramNonBoarding:
+	default:
+		return utils.BadRequestResponse(c, "invalid program")
+	}
+
+	items, err := h.svc.GetRanking(id, gender, program)
+	if err != nil {
+		return writeError(c, err, "failed to fetch selection ranking")
+	}
+	return utils.SuccessResponse(c, "selection ranking fetched", items)
+}
+
+// ADMIN: POST /admin/admission-periods/:id/ranking/apply
+// ApplyRanking godoc
+// @Summary Admin apply the selection ranking
+// @Description Moves the top N ranked applicants (complete scores, final_score >= min_score) to process or done.
+// @Description gender/program rank within one quota group. Every change is recorded in the status history.
+// @Description The changes are applied together: if one fails (e.g. 409 quota full), none is applied.
+// @Tags Selection (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Admission period ID" minimum(1)
+// @Param request body dto.RankingApplyDTO true "Apply payload"
+// @Success 200 {object} RankingApplyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/admission-periods/{id}/ranking/apply [post]
+func (h *SelectionHandler) ApplyRanking(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var body dto.RankingApplyDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	result, err := h.svc.ApplyRanking(id, body, adminID)
+	if err != nil {
+		return writeError(c, err, "failed to apply selection ranking")
+	}
+	return utils.SuccessResponse(c, "selection ranking applied", result)
+}
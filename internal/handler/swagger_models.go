@@ -1,11 +1,17 @@
 package handler
 
-import "darulabror/internal/dto"
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/utils"
+)
 
+// PaginationMeta: page is returned in offset mode, next_cursor in cursor mode
+// (empty on the last page); total is omitted in cursor mode unless with_total=true.
 type PaginationMeta struct {
-	Page  int   `json:"page" example:"1"`
-	Limit int   `json:"limit" example:"10"`
-	Total int64 `json:"total" example:"123"`
+	Page       int    `json:"page,omitempty" example:"1"`
+	Limit      int    `json:"limit" example:"10"`
+	Total      int64  `json:"total,omitempty" example:"123"`
+	NextCursor string `json:"next_cursor,omitempty" example:"MTczNDU2Nzg5MDAwMDAwMDAwMC40Mg"`
 }
 
 type ListResponseData[T any] struct {
@@ -19,9 +25,14 @@ type SuccessResponse[T any] struct {
 	Data    T      `json:"data"`
 }
 
+// ErrorResponse: code is one of bad_request, validation, unauthorized, forbidden, not_found,
+// conflict, too_large, too_many_requests, internal or unavailable; errors lists the failed
+// fields of a 422.
 type ErrorResponse struct {
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message" example:"something went wrong"`
+	Status  string             `json:"status" example:"error"`
+	Code    string             `json:"code" example:"not_found"`
+	Message string             `json:"message" example:"something went wrong"`
+	Errors  []utils.FieldError `json:"errors,omitempty"`
 }
 
 // ===== Requests (named types so Swagger shows templates) =====
@@ -31,9 +42,23 @@ type AdminLoginRequest struct {
 	Password string `json:"password" validate:"required,min=6,max=100" example:"StrongPassword123"`
 }
 
-type AdminLoginResponseData struct {
-	Token string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	Admin dto.AdminDTO `json:"admin"`
+type AdminMFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	// Code is a 6-digit TOTP code or, on POST /admin/login/mfa, a recovery code
+	Code string `json:"code" validate:"required,max=20" example:"123456"`
+}
+
+type AdminMFATokenRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+type AdminMFACodeRequest struct {
+	Code string `json:"code" validate:"required,max=20" example:"123456"`
+}
+
+type AdminMFADisableRequest struct {
+	Password string `json:"password" validate:"required" example:"StrongPassword123"`
+	Code     string `json:"code" validate:"required,max=20" example:"123456"`
 }
 
 type ContactCreateRequest struct {
@@ -49,7 +74,15 @@ type ContactStatusUpdateRequest struct {
 }
 
 type RegistrationStatusUpdateRequest struct {
-	Status string `json:"status" validate:"required,oneof=new validate process done" example:"validate"`
+	Status string `json:"status" validate:"required,oneof=new validate process done waitlisted withdrawn rejected" example:"validate"`
+}
+
+// RegistrationPatchRequest: any subset of the RegistrationDTO fields, plus version.
+type RegistrationPatchRequest struct {
+	Version  int    `json:"version" example:"3"`
+	Phone    string `json:"phone,omitempty" example:"081234567890"`
+	Address  string `json:"address,omitempty" example:"Jl. Mawar 1, Kediri"`
+	FullName string `json:"full_name,omitempty" example:"Siti Aminah"`
 }
 
 type AdminChangePasswordRequest struct {
@@ -70,8 +103,91 @@ type ContactListItem struct {
 	Message   string `json:"message" example:"Hello..."`
 	Status    string `json:"status" example:"new"`
 	CreatedAt int64  `json:"created_at" example:"1734567890"`
+
+	AssignedTo *uint `json:"assigned_to" example:"2"`
 }
 
 type ContactListResponse = SuccessResponse[ListResponseData[ContactListItem]]
 
-type AdminLoginResponse = SuccessResponse[AdminLoginResponseData]
+type AdminLoginResponse = SuccessResponse[dto.AdminLoginDTO]
+
+type MFASetupResponse = SuccessResponse[dto.MFASetupDTO]
+
+type MFAStatusResponse = SuccessResponse[dto.MFAStatusDTO]
+
+type MFARecoveryCodesResponse = SuccessResponse[dto.MFARecoveryCodesDTO]
+
+type UploadTicketResponse = SuccessResponse[dto.UploadTicketDTO]
+
+type MediaUploadResponse = SuccessResponse[dto.MediaUploadDTO]
+
+type ExportJobResponse = SuccessResponse[dto.ExportJobDTO]
+
+type ImportReportResponse = SuccessResponse[dto.ImportReportDTO]
+
+type AdmissionPeriodResponse = SuccessResponse[dto.AdmissionPeriodDTO]
+
+type AdmissionPeriodListResponse = SuccessResponse[[]dto.AdmissionPeriodDTO]
+
+type RegistrationReceiptResponse = SuccessResponse[dto.RegistrationReceiptDTO]
+
+type RegistrationStatusHistoryResponse = SuccessResponse[[]dto.RegistrationStatusHistoryDTO]
+
+type RegistrationChangeListResponse = SuccessResponse[[]dto.RegistrationChangeDTO]
+
+type EditLinkSentResponse = SuccessResponse[dto.EditLinkSentDTO]
+
+type AdmissionQuotaListResponse = SuccessResponse[[]dto.AdmissionQuotaDTO]
+
+type SelectionComponentListResponse = SuccessResponse[[]dto.SelectionComponentDTO]
+
+type SelectionSessionResponse = SuccessResponse[dto.SelectionSessionDTO]
+
+type SelectionSessionListResponse = SuccessResponse[[]dto.SelectionSessionDTO]
+
+type SelectionCandidateListResponse = SuccessResponse[[]dto.SelectionCandidateDTO]
+
+type SelectionResultResponse = SuccessResponse[dto.SelectionResultDTO]
+
+type RankingListResponse = SuccessResponse[[]dto.RankingEntryDTO]
+
+type RankingApplyResponse = SuccessResponse[dto.RankingApplyResultDTO]
+
+type RegistrationTrackResponse = SuccessResponse[dto.RegistrationTrackDTO]
+
+type DocumentLinksResponse = SuccessResponse[dto.DocumentLinksDTO]
+
+type LetterTemplateResponse = SuccessResponse[dto.LetterTemplateDTO]
+
+type LetterTemplateListResponse = SuccessResponse[[]dto.LetterTemplateDTO]
+
+type InvoiceResponse = SuccessResponse[dto.InvoiceDTO]
+
+type InvoiceListResponse = SuccessResponse[[]dto.InvoiceDTO]
+
+type PaymentProofResponse = SuccessResponse[dto.PaymentProofDTO]
+
+type PaymentProofListResponse = SuccessResponse[ListResponseData[dto.PaymentProofDTO]]
+
+type AdminNoteResponse = SuccessResponse[dto.AdminNoteDTO]
+
+type AdminNoteListResponse = SuccessResponse[[]dto.AdminNoteDTO]
+
+type AssigneeListResponse = SuccessResponse[[]dto.AssigneeOptionDTO]
+
+type DashboardStatsResponse = SuccessResponse[dto.DashboardStatsDTO]
+
+type PopularArticleListResponse = SuccessResponse[[]dto.PopularArticleDTO]
+
+type ArticleAnalyticsResponse = SuccessResponse[dto.ArticleAnalyticsDTO]
+
+type ArticleTranslationResponse = SuccessResponse[dto.ArticleTranslationDTO]
+
+type ArticleTranslationListResponse = SuccessResponse[[]dto.ArticleTranslationDTO]
+
+type RoleResponse = SuccessResponse[dto.RoleDTO]
+
+type RoleListResponse = SuccessResponse[[]dto.RoleDTO]
+
+// PermissionListResponse: data is the permission names, e.g. "articles.publish"
+type PermissionListResponse = SuccessResponse[[]string]
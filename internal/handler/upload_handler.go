@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/repository"
+	"darulabror/internal/service"
+	"darulabror/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type UploadHandler struct {
+	svc service.UploadService
+}
+
+func NewUploadHandler(svc service.UploadService) *UploadHandler {
+	return &UploadHandler{svc: svc}
+}
+
+// ADMIN: POST /admin/uploads
+// Create godoc
+// @Summary Admin request a direct upload URL
+// @Description Returns a signed URL; the client uploads the file directly to the bucket (no API body limit),
+// @Description then calls POST /admin/uploads/{id}/complete. With resumable=true (GCS) the URL starts a resumable session.
+// @Tags Uploads (Admin)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UploadRequestDTO true "Upload payload"
+// @Success 201 {object} UploadTicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/uploads [post]
+func (h *UploadHandler) Create(c echo.Context) error {
+	var body dto.UploadRequestDTO
+	if err := c.Bind(&body); err != nil {
+		return utils.BadRequestResponse(c, "invalid body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	adminID, _ := utils.GetAdminID(c)
+	ticket, err := h.svc.CreateUpload(c.Request().Context(), adminID, body)
+	if err != nil {
+		if errors.Is(err, repository.ErrStorageNotConfigured) {
+			return utils.BadRequestResponse(c, "storage not configured: set STORAGE_DRIVER/PUBLIC_BUCKET to enable uploads")
+		}
+		return writeError(c, err, "failed to create upload")
+	}
+
+	return utils.CreatedResponse(c, "upload url created", ticket)
+}
+
+// ADMIN: POST /admin/uploads/:id/complete
+// Complete godoc
+// @Summary Admin complete a direct upload
+// @Description Verifies the uploaded object's size and type and registers it. Rejected objects are deleted.
+// @Tags Uploads (Admin)
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Upload ID" minimum(1)
+// @Success 200 {object} MediaUploadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/uploads/{id}/complete [post]
+func (h *UploadHandler) Complete(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	item, err := h.svc.CompleteUpload(c.Request().Context(), uint(id64))
+	if err != nil {
+		return writeError(c, err, "failed to complete upload")
+	}
+
+	return utils.SuccessResponse(c, "upload completed", item)
+}
+
+// LocalStorageHandler accepts signed PUT uploads for STORAGE_DRIVER=local,
+// standing in for the bucket endpoint during development.
+type LocalStorageHandler struct {
+	store repository.LocalStorageRepo
+}
+
+func NewLocalStorageHandler(store repository.LocalStorageRepo) *LocalStorageHandler {
+	return &LocalStorageHandler{store: store}
+}
+
+// maxLocalUploadSize mirrors the largest upload rule (article_content).
+const maxLocalUploadSize = 2 << 30
+
+func (h *LocalStorageHandler) Put(c echo.Context) error {
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxLocalUploadSize)
+	defer body.Close()
+
+	if _, err := h.store.UploadFile(c.Request().Context(), body, c.Param("*")); err != nil {
+		if errors.Is(err, repository.ErrInvalidObjectName) {
+			return utils.BadRequestResponse(c, err.Error())
+		}
+		logrus.WithError(err).Error("failed local direct upload")
+		return utils.InternalServerErrorResponse(c, "failed to store file")
+	}
+	return c.NoContent(http.StatusOK)
+}
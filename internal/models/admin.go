@@ -16,4 +16,6 @@ type Admin struct {
 	CreatedAt int64  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt int64  `gorm:"autoUpdateTime" json:"updated_at"`
 	IsActive  bool   `gorm:"not null;default:true" json:"is_active"`
+	// MFARequired makes the admin enroll a TOTP authenticator before the next login completes
+	MFARequired bool `gorm:"not null;default:false" json:"mfa_required"`
 }
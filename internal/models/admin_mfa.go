@@ -0,0 +1,37 @@
+package models
+
+// AdminMFA is the TOTP authenticator of an admin. Secret is encrypted (AES-GCM); EnabledAt
+// stays 0 until the admin confirms the authenticator with a first code.
+type AdminMFA struct {
+	AdminID   uint   `gorm:"primaryKey" json:"admin_id"`
+	Secret    string `gorm:"type:text;not null" json:"-"`
+	EnabledAt int64  `gorm:"not null;default:0" json:"enabled_at"`
+	// LastStep is the TOTP time step of the last accepted code, so a code works only once
+	LastStep int64 `gorm:"not null;default:0" json:"-"`
+	// FailedAttempts counts wrong codes in a row; LockedUntil blocks codes after too many
+	FailedAttempts int   `gorm:"not null;default:0" json:"-"`
+	LockedUntil    int64 `gorm:"not null;default:0" json:"-"`
+	CreatedAt      int64 `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      int64 `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AdminMFA) TableName() string {
+	return "admin_mfa"
+}
+
+func (m AdminMFA) Enabled() bool {
+	return m.EnabledAt > 0
+}
+
+// AdminRecoveryCode is a one-time code that replaces a TOTP code; only its SHA-256 is stored.
+type AdminRecoveryCode struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID   uint   `gorm:"not null;index" json:"admin_id"`
+	CodeHash  string `gorm:"type:text;not null" json:"-"`
+	UsedAt    int64  `gorm:"not null;default:0" json:"used_at"`
+	CreatedAt int64  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AdminRecoveryCode) TableName() string {
+	return "admin_recovery_codes"
+}
@@ -0,0 +1,19 @@
+package models
+
+// NoteSubject is the kind of record an admin note or assignment belongs to.
+type NoteSubject string
+
+const (
+	NoteSubjectRegistration NoteSubject = "registration"
+	NoteSubjectContact      NoteSubject = "contact"
+)
+
+// AdminNote is an internal note on a registration or contact; never shown to applicants.
+type AdminNote struct {
+	ID          uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubjectType NoteSubject `gorm:"type:text;not null;index:idx_admin_notes_subject;check:subject_type IN ('registration','contact')" json:"subject_type"`
+	SubjectID   uint        `gorm:"not null;index:idx_admin_notes_subject" json:"subject_id"`
+	AuthorID    uint        `gorm:"not null" json:"author_id"`
+	Body        string      `gorm:"type:text;not null" json:"body"` // markdown
+	CreatedAt   int64       `gorm:"autoCreateTime" json:"created_at"`
+}
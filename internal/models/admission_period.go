@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AdmissionPeriod is one intake wave (gelombang) of an academic year.
+// Registrations are accepted while OpensAt <= now < ClosesAt.
+type AdmissionPeriod struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AcademicYear    string    `gorm:"not null;uniqueIndex:idx_admission_periods_year_wave" json:"academic_year"` // e.g. 2025/2026
+	Wave            int       `gorm:"not null;uniqueIndex:idx_admission_periods_year_wave" json:"wave"`
+	Name            string    `gorm:"not null" json:"name"`
+	OpensAt         time.Time `gorm:"not null" json:"opens_at"`
+	ClosesAt        time.Time `gorm:"not null" json:"closes_at"`
+	Quota           int       `gorm:"not null;default:0" json:"quota"`            // 0 = unlimited
+	RegistrationFee int64     `gorm:"not null;default:0" json:"registration_fee"` // rupiah, paid after validation; 0 = no fee
+	CreatedAt       int64     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       int64     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p AdmissionPeriod) IsOpen(now time.Time) bool {
+	return !now.Before(p.OpensAt) && now.Before(p.ClosesAt)
+}
@@ -0,0 +1,12 @@
+package models
+
+// AdmissionQuota limits the seats of one gender/program group within an admission period.
+// Groups without a quota row are only limited by AdmissionPeriod.Quota.
+type AdmissionQuota struct {
+	ID                uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdmissionPeriodID uint    `gorm:"not null;uniqueIndex:idx_admission_quotas_group" json:"admission_period_id"`
+	Gender            Gender  `gorm:"type:text;not null;uniqueIndex:idx_admission_quotas_group" json:"gender"`
+	Program           Program `gorm:"type:text;not null;uniqueIndex:idx_admission_quotas_group" json:"program"`
+	Seats             int     `gorm:"not null" json:"seats"`
+	UpdatedAt         int64   `gorm:"autoUpdateTime" json:"updated_at"`
+}
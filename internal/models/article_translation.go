@@ -0,0 +1,56 @@
+package models
+
+import "gorm.io/datatypes"
+
+// Locale is a content language of articles (BCP 47 primary subtag).
+type Locale string
+
+const (
+	LocaleIndonesian Locale = "id"
+	LocaleEnglish    Locale = "en"
+	LocaleArabic     Locale = "ar"
+
+	// DefaultLocale is the language of the article itself; other locales are translations.
+	DefaultLocale = LocaleIndonesian
+)
+
+// Locales lists the supported locales, default first; fallbacks go to DefaultLocale.
+var Locales = []Locale{LocaleIndonesian, LocaleEnglish, LocaleArabic}
+
+func (l Locale) Valid() bool {
+	for _, v := range Locales {
+		if l == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir is the text direction of the locale: "rtl" for Arabic, otherwise "ltr".
+func (l Locale) Dir() string {
+	if l == LocaleArabic {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Translation statuses shown to admins.
+const (
+	TranslationMissing  = "missing"
+	TranslationOutdated = "outdated" // the article title/content changed after the translation
+	TranslationUpToDate = "up_to_date"
+)
+
+// ArticleTranslation is the title, slug and content of an article in a non-default locale.
+type ArticleTranslation struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	ArticleID uint           `gorm:"not null;uniqueIndex:idx_article_translations_article_locale" json:"article_id"`
+	Locale    Locale         `gorm:"type:text;not null;uniqueIndex:idx_article_translations_article_locale" json:"locale"`
+	Title     string         `gorm:"not null" json:"title"`
+	Slug      string         `gorm:"not null;uniqueIndex" json:"slug"`
+	Content   datatypes.JSON `gorm:"type:jsonb;not null" json:"content"`
+	// SourceHash is the hash of the article title and content the translation was made from.
+	SourceHash string `gorm:"not null" json:"source_hash"`
+	CreatedAt  int64  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  int64  `gorm:"autoUpdateTime" json:"updated_at"`
+}
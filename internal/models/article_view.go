@@ -0,0 +1,13 @@
+package models
+
+// ArticleViewDaily counts the views of one article on one day (WIB).
+// Rows are written in batches by the article view recorder.
+type ArticleViewDaily struct {
+	ArticleID uint   `gorm:"primaryKey" json:"article_id"`
+	Day       string `gorm:"primaryKey;type:date" json:"day"` // YYYY-MM-DD
+	Views     int64  `gorm:"not null;default:0" json:"views"`
+}
+
+func (ArticleViewDaily) TableName() string {
+	return "article_views_daily"
+}
@@ -5,6 +5,7 @@ import "gorm.io/datatypes"
 type Article struct {
 	ID          uint           `gorm:"primaryKey;autoIncrement" json:"id"`
 	Title       string         `gorm:"not null" json:"title"`
+	Slug        string         `gorm:"uniqueIndex" json:"slug"` // default-locale slug; translations have their own
 	PhotoHeader string         `gorm:"type:text" json:"photo_header"`
 	Content     datatypes.JSON `gorm:"type:jsonb;not null" json:"content"`
 	Author      string         `gorm:"not null" json:"author"`
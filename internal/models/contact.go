@@ -15,4 +15,7 @@ type Contact struct {
 	Message   string        `gorm:"type:text;not null" json:"message"`
 	Status    ContactStatus `gorm:"type:text;not null;default:'new';check:status IN ('new','in_progress','done')" json:"status"`
 	CreatedAt int64         `gorm:"autoCreateTime" json:"created_at"`
+
+	// AssignedTo is the admin handling the message; nil = unassigned.
+	AssignedTo *uint `gorm:"index" json:"assigned_to"`
 }
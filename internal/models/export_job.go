@@ -0,0 +1,27 @@
+package models
+
+import "gorm.io/datatypes"
+
+type ExportJobStatus string
+
+const (
+	ExportJobPending ExportJobStatus = "pending"
+	ExportJobRunning ExportJobStatus = "running"
+	ExportJobDone    ExportJobStatus = "done"
+	ExportJobFailed  ExportJobStatus = "failed"
+)
+
+// ExportJob is a background export whose result file lives in the private bucket.
+type ExportJob struct {
+	ID          uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	Kind        string          `gorm:"not null" json:"kind"` // registrations
+	Format      string          `gorm:"not null" json:"format"`
+	Params      datatypes.JSON  `gorm:"type:jsonb" json:"params"`
+	Status      ExportJobStatus `gorm:"type:text;not null;default:'pending';check:status IN ('pending','running','done','failed')" json:"status"`
+	ObjectName  string          `gorm:"type:text" json:"object_name"`
+	RowCount    int64           `gorm:"not null;default:0" json:"row_count"`
+	Error       string          `gorm:"type:text" json:"error"`
+	RequestedBy uint            `gorm:"not null" json:"requested_by"`
+	CreatedAt   int64           `gorm:"autoCreateTime" json:"created_at"`
+	CompletedAt int64           `json:"completed_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "gorm.io/datatypes"
+
+type InvoiceStatus string
+
+const (
+	InvoicePending InvoiceStatus = "pending"
+	InvoicePaid    InvoiceStatus = "paid"
+	InvoiceExpired InvoiceStatus = "expired"
+	InvoiceFailed  InvoiceStatus = "failed"
+)
+
+// Invoice is a registration fee charge at a payment gateway. A registration gets a new
+// invoice when the previous one expired or failed; at most one is paid.
+type Invoice struct {
+	ID             uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	RegistrationID uint          `gorm:"not null;index" json:"registration_id"`
+	Number         string        `gorm:"not null;uniqueIndex" json:"number"` // order id sent to the gateway
+	Amount         int64         `gorm:"not null" json:"amount"`             // rupiah
+	Status         InvoiceStatus `gorm:"type:text;not null;default:'pending';check:status IN ('pending','paid','expired','failed')" json:"status"`
+	Provider       string        `gorm:"not null" json:"provider"`
+	ExternalID     string        `json:"external_id"` // gateway transaction/invoice id
+	PaymentURL     string        `gorm:"type:text" json:"payment_url"`
+	ExpiresAt      int64         `gorm:"not null" json:"expires_at"`
+	PaidAt         *int64        `json:"paid_at"`
+	CreatedAt      int64         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      int64         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PaymentEvent is a webhook notification that was applied; (provider, event_id) is unique,
+// so a notification delivered twice is only applied once.
+type PaymentEvent struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Provider  string         `gorm:"not null;uniqueIndex:idx_payment_events_provider_event" json:"provider"`
+	EventID   string         `gorm:"not null;uniqueIndex:idx_payment_events_provider_event" json:"event_id"`
+	InvoiceID uint           `gorm:"not null;index" json:"invoice_id"`
+	Status    InvoiceStatus  `gorm:"type:text;not null" json:"status"`
+	Amount    int64          `gorm:"not null" json:"amount"`
+	Payload   datatypes.JSON `gorm:"type:jsonb" json:"-"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"created_at"`
+}
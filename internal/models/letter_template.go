@@ -0,0 +1,17 @@
+package models
+
+type LetterKind string
+
+const (
+	LetterAcceptance LetterKind = "acceptance"
+	LetterRejection  LetterKind = "rejection"
+)
+
+// LetterTemplate is the editable subject and body (Go text/template) of a decision letter.
+type LetterTemplate struct {
+	Kind      LetterKind `gorm:"primaryKey;type:text;check:kind IN ('acceptance','rejection')" json:"kind"`
+	Subject   string     `gorm:"not null" json:"subject"`
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	UpdatedBy uint       `json:"updated_by"`
+	UpdatedAt int64      `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type MediaUploadStatus string
+
+const (
+	MediaUploadPending   MediaUploadStatus = "pending"
+	MediaUploadCompleted MediaUploadStatus = "completed"
+)
+
+// MediaUpload tracks a direct-to-bucket upload from the moment a signed URL is issued
+// until the object is verified and registered.
+type MediaUpload struct {
+	ID          uint              `gorm:"primaryKey;autoIncrement" json:"id"`
+	ObjectName  string            `gorm:"not null;uniqueIndex" json:"object_name"`
+	FileName    string            `gorm:"not null" json:"file_name"`
+	ContentType string            `gorm:"not null" json:"content_type"`
+	Size        int64             `gorm:"not null" json:"size"`
+	Purpose     string            `gorm:"not null" json:"purpose"`
+	Status      MediaUploadStatus `gorm:"type:text;not null;default:'pending';check:status IN ('pending','completed')" json:"status"`
+	URL         string            `gorm:"type:text" json:"url"`
+	UploadedBy  uint              `gorm:"not null" json:"uploaded_by"`
+	CreatedAt   int64             `gorm:"autoCreateTime" json:"created_at"`
+	CompletedAt int64             `json:"completed_at"`
+}
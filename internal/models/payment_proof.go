@@ -0,0 +1,26 @@
+package models
+
+type PaymentProofStatus string
+
+const (
+	PaymentProofPending  PaymentProofStatus = "pending"
+	PaymentProofApproved PaymentProofStatus = "approved"
+	PaymentProofRejected PaymentProofStatus = "rejected"
+)
+
+// PaymentProof is a bank transfer receipt uploaded by an applicant. The file lives in the
+// private bucket; approving it creates a paid invoice (provider "manual").
+type PaymentProof struct {
+	ID             uint               `gorm:"primaryKey;autoIncrement" json:"id"`
+	RegistrationID uint               `gorm:"not null;index" json:"registration_id"`
+	ObjectName     string             `gorm:"not null" json:"object_name"`
+	ContentType    string             `gorm:"not null" json:"content_type"`
+	Size           int64              `gorm:"not null" json:"size"`
+	Amount         int64              `gorm:"not null" json:"amount"` // registration fee when uploaded
+	Status         PaymentProofStatus `gorm:"type:text;not null;default:'pending';check:status IN ('pending','approved','rejected')" json:"status"`
+	RejectReason   string             `gorm:"type:text" json:"reject_reason"`
+	InvoiceID      *uint              `json:"invoice_id"` // set when approved
+	ReviewedBy     *uint              `json:"reviewed_by"`
+	ReviewedAt     *int64             `json:"reviewed_at"`
+	CreatedAt      int64              `gorm:"autoCreateTime" json:"created_at"`
+}
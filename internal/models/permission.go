@@ -0,0 +1,97 @@
+package models
+
+// Permission is one action on the admin API; roles grant sets of them.
+type Permission string
+
+const (
+	PermDashboardView Permission = "dashboard.view"
+
+	PermArticlesView    Permission = "articles.view"
+	PermArticlesWrite   Permission = "articles.write"
+	PermArticlesPublish Permission = "articles.publish"
+	PermArticlesDelete  Permission = "articles.delete"
+
+	PermRegistrationsView   Permission = "registrations.view"
+	PermRegistrationsEdit   Permission = "registrations.edit"
+	PermRegistrationsDelete Permission = "registrations.delete"
+	PermRegistrationsExport Permission = "registrations.export"
+	PermRegistrationsImport Permission = "registrations.import"
+	PermPaymentsVerify      Permission = "payments.verify"
+	PermSelectionManage     Permission = "selection.manage"
+
+	PermContactsView   Permission = "contacts.view"
+	PermContactsReply  Permission = "contacts.reply"
+	PermContactsDelete Permission = "contacts.delete"
+
+	// PermNotesDelete allows deleting notes written by other admins (authors can always delete theirs).
+	PermNotesDelete Permission = "notes.delete"
+
+	PermAdmissionPeriodsManage Permission = "admission_periods.manage"
+	PermLetterTemplatesManage  Permission = "letter_templates.manage"
+	PermAdminsManage           Permission = "admins.manage"
+	PermRolesManage            Permission = "roles.manage"
+)
+
+// Permissions is the catalogue of every permission, in display order.
+var Permissions = []Permission{
+	PermDashboardView,
+	PermArticlesView, PermArticlesWrite, PermArticlesPublish, PermArticlesDelete,
+	PermRegistrationsView, PermRegistrationsEdit, PermRegistrationsDelete, PermRegistrationsExport, PermRegistrationsImport,
+	PermPaymentsVerify, PermSelectionManage,
+	PermContactsView, PermContactsReply, PermContactsDelete,
+	PermNotesDelete,
+	PermAdmissionPeriodsManage, PermLetterTemplatesManage, PermAdminsManage, PermRolesManage,
+}
+
+func (p Permission) Valid() bool {
+	for _, known := range Permissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultPermissions are the permissions of the built-in roles as seeded by the migration;
+// superadmin always has every permission. They also apply to tokens issued before permissions
+// were added to the JWT.
+func DefaultPermissions(role Role) []Permission {
+	switch role {
+	case Superadmin:
+		return Permissions
+	case Admins:
+		return []Permission{
+			PermDashboardView,
+			PermArticlesView, PermArticlesWrite, PermArticlesPublish, PermArticlesDelete,
+			PermRegistrationsView, PermRegistrationsEdit, PermRegistrationsDelete, PermRegistrationsExport, PermRegistrationsImport,
+			PermPaymentsVerify, PermSelectionManage,
+			PermContactsView, PermContactsReply, PermContactsDelete,
+		}
+	}
+	return nil
+}
+
+// AdminRole is a named set of permissions; admins.role refers to its name. System roles
+// (admin, superadmin) cannot be deleted and superadmin cannot be changed.
+type AdminRole struct {
+	Name        Role             `gorm:"primaryKey;type:text" json:"name"`
+	Description string           `gorm:"type:text;not null;default:''" json:"description"`
+	System      bool             `gorm:"not null;default:false" json:"system"`
+	Permissions []RolePermission `gorm:"foreignKey:Role;references:Name" json:"-"`
+	CreatedAt   int64            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   int64            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AdminRole) TableName() string {
+	return "roles"
+}
+
+// RolePermission grants one permission to a role.
+type RolePermission struct {
+	Role       Role       `gorm:"primaryKey;type:text" json:"role"`
+	Permission Permission `gorm:"primaryKey;type:text" json:"permission"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -16,6 +16,13 @@ const (
 	Female Gender = "female"
 )
 
+type Program string
+
+const (
+	ProgramBoarding    Program = "boarding"
+	ProgramNonBoarding Program = "non_boarding"
+)
+
 type RegistrationStatus string
 
 const (
@@ -23,16 +30,33 @@ const (
 	RegistrationStatusValidate RegistrationStatus = "validate"
 	RegistrationStatusProcess  RegistrationStatus = "process"
 	RegistrationStatusDone     RegistrationStatus = "done"
+	// Not holding a seat: waiting for a free seat / left the process / not accepted.
+	RegistrationStatusWaitlisted RegistrationStatus = "waitlisted"
+	RegistrationStatusWithdrawn  RegistrationStatus = "withdrawn"
+	RegistrationStatusRejected   RegistrationStatus = "rejected"
 )
 
+// HoldsSeat reports whether a registration in this status counts against the quota.
+func (s RegistrationStatus) HoldsSeat() bool {
+	return s != RegistrationStatusWaitlisted && s != RegistrationStatusWithdrawn && s != RegistrationStatusRejected
+}
+
 type Registration struct {
 	ID uint `gorm:"primaryKey" json:"id"`
 
+	// AdmissionPeriodID is nil for registrations made before admission periods existed.
+	// Email and NISN are unique per period, so a student can re-apply in a later year.
+	AdmissionPeriodID *uint `gorm:"uniqueIndex:idx_registrations_period_email;uniqueIndex:idx_registrations_period_nisn" json:"admission_period_id"`
+
+	// TrackingCode is shown on the registration card and used to check the status publicly.
+	TrackingCode string `gorm:"not null;uniqueIndex" json:"tracking_code"`
+
 	StudentType StudentType        `gorm:"type:text;check:student_type IN ('new','transfer');not null" json:"student_type"`
 	Gender      Gender             `gorm:"type:text;check:gender IN ('male','female');not null" json:"gender"`
-	Status      RegistrationStatus `gorm:"type:text;not null;default:'new';check:status IN ('new','validate','process','done')" json:"status"`
+	Program     Program            `gorm:"type:text;not null;default:'boarding';check:program IN ('boarding','non_boarding')" json:"program"`
+	Status      RegistrationStatus `gorm:"type:text;not null;default:'new';check:status IN ('new','validate','process','done','waitlisted','withdrawn','rejected')" json:"status"`
 
-	Email    string `gorm:"not null;uniqueIndex" json:"email"`
+	Email    string `gorm:"not null;uniqueIndex:idx_registrations_period_email" json:"email"`
 	FullName string `gorm:"not null" json:"full_name"`
 	Phone    string `gorm:"not null" json:"phone"`
 
@@ -42,7 +66,7 @@ type Registration struct {
 	Address      string `gorm:"type:text;not null" json:"address"`
 	OriginSchool string `gorm:"not null" json:"origin_school"`
 
-	NISN string `gorm:"not null;uniqueIndex" json:"nisn"`
+	NISN string `gorm:"not null;uniqueIndex:idx_registrations_period_nisn" json:"nisn"`
 
 	FatherName        string    `gorm:"not null" json:"father_name"`
 	FatherOccupation  string    `gorm:"not null" json:"father_occupation"`
@@ -54,5 +78,11 @@ type Registration struct {
 	PhoneMother       string    `gorm:"not null" json:"phone_mother"`
 	DateOfBirthMother time.Time `gorm:"not null" json:"date_of_birth_mother"`
 
+	// AssignedTo is the admin handling the registration; nil = unassigned.
+	AssignedTo *uint `gorm:"index" json:"assigned_to"`
+
+	// Version is incremented by every edit of the fields above (optimistic locking).
+	Version int `gorm:"not null;default:1" json:"version"`
+
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
@@ -0,0 +1,19 @@
+package models
+
+const (
+	ChangeSourceApplicant = "applicant" // edited through an emailed edit link
+	ChangeSourceAdmin     = "admin"
+)
+
+// RegistrationChange records one edited field of a registration.
+// Dates are stored as YYYY-MM-DD, everything else as the JSON field value.
+type RegistrationChange struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	RegistrationID uint   `gorm:"not null;index" json:"registration_id"`
+	Field          string `gorm:"not null" json:"field"`
+	OldValue       string `gorm:"type:text" json:"old_value"`
+	NewValue       string `gorm:"type:text" json:"new_value"`
+	Source         string `gorm:"not null" json:"source"`
+	ChangedBy      *uint  `json:"changed_by"` // admin id, nil for the applicant
+	CreatedAt      int64  `gorm:"autoCreateTime" json:"created_at"`
+}
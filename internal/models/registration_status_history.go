@@ -0,0 +1,20 @@
+package models
+
+const (
+	StatusReasonRegistered = "registered"
+	StatusReasonQuotaFull  = "quota_full"
+	StatusReasonAdmin      = "admin"
+	StatusReasonPromoted   = "promoted"
+	StatusReasonPaid       = "paid" // registration fee paid (validate -> process)
+)
+
+// RegistrationStatusHistory records every status change of a registration.
+type RegistrationStatusHistory struct {
+	ID             uint               `gorm:"primaryKey;autoIncrement" json:"id"`
+	RegistrationID uint               `gorm:"not null;index" json:"registration_id"`
+	FromStatus     RegistrationStatus `gorm:"type:text" json:"from_status"`
+	ToStatus       RegistrationStatus `gorm:"type:text;not null" json:"to_status"`
+	Reason         string             `gorm:"not null" json:"reason"`
+	ChangedBy      *uint              `json:"changed_by"` // admin id, nil = system
+	CreatedAt      int64              `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// SelectionComponent is one entrance test of an admission period (e.g. Qur'an reading,
+// written test, interview). Weights of a period add up to 100.
+type SelectionComponent struct {
+	ID                uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdmissionPeriodID uint    `gorm:"not null;uniqueIndex:idx_selection_components_key" json:"admission_period_id"`
+	Key               string  `gorm:"not null;uniqueIndex:idx_selection_components_key" json:"key"` // e.g. quran, written, interview
+	Name              string  `gorm:"not null" json:"name"`
+	Weight            float64 `gorm:"not null" json:"weight"`    // percent of the final score
+	MaxScore          float64 `gorm:"not null" json:"max_score"` // raw scores are 0..MaxScore
+	CreatedAt         int64   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         int64   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SelectionSession is a scheduled test slot applicants are assigned to.
+type SelectionSession struct {
+	ID                uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdmissionPeriodID uint      `gorm:"not null;index" json:"admission_period_id"`
+	Name              string    `gorm:"not null" json:"name"`
+	StartsAt          time.Time `gorm:"not null" json:"starts_at"`
+	Location          string    `gorm:"not null" json:"location"`
+	Capacity          int       `gorm:"not null" json:"capacity"`
+	CreatedAt         int64     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         int64     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SelectionAssignment puts a registration into a session; a registration has at most one session.
+type SelectionAssignment struct {
+	ID             uint  `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID      uint  `gorm:"not null;index" json:"session_id"`
+	RegistrationID uint  `gorm:"not null;uniqueIndex" json:"registration_id"`
+	CreatedAt      int64 `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// SelectionScore is the raw score of one registration for one component.
+type SelectionScore struct {
+	ID             uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	RegistrationID uint    `gorm:"not null;uniqueIndex:idx_selection_scores_component" json:"registration_id"`
+	ComponentID    uint    `gorm:"not null;uniqueIndex:idx_selection_scores_component" json:"component_id"`
+	Score          float64 `gorm:"not null" json:"score"`
+	ScoredBy       uint    `gorm:"not null" json:"scored_by"` // admin id
+	UpdatedAt      int64   `gorm:"autoUpdateTime" json:"updated_at"`
+}
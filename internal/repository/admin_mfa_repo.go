@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AdminMFARepo stores the TOTP authenticators and recovery codes of admins.
+type AdminMFARepo interface {
+	Get(adminID uint) (models.AdminMFA, error)
+	// Save creates or replaces the authenticator of an admin.
+	Save(mfa models.AdminMFA) error
+	// Enable saves a confirmed authenticator and replaces the recovery codes in one transaction.
+	Enable(mfa models.AdminMFA, codeHashes []string) error
+	// Delete removes the authenticator and the recovery codes of an admin.
+	Delete(adminID uint) error
+	// WithLock runs fn in one transaction holding the authenticator's row lock, with an
+	// AdminMFARepo bound to that transaction, so codes of one admin are checked one at a time.
+	WithLock(adminID uint, fn func(repo AdminMFARepo) error) error
+
+	ReplaceRecoveryCodes(adminID uint, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used; false when there is none with that hash.
+	UseRecoveryCode(adminID uint, codeHash string, usedAt int64) (bool, error)
+	CountUnusedRecoveryCodes(adminID uint) (int64, error)
+}
+
+type adminMFARepo struct {
+	db *gorm.DB
+}
+
+func NewAdminMFARepo(db *gorm.DB) AdminMFARepo {
+	return &adminMFARepo{db: db}
+}
+
+func (r *adminMFARepo) Get(adminID uint) (models.AdminMFA, error) {
+	var mfa models.AdminMFA
+	err := r.db.Where("admin_id = ?", adminID).First(&mfa).Error
+	return mfa, err
+}
+
+func (r *adminMFARepo) Save(mfa models.AdminMFA) error {
+	return saveAdminMFA(r.db, &mfa)
+}
+
+func saveAdminMFA(db *gorm.DB, mfa *models.AdminMFA) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "admin_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_step", "failed_attempts", "locked_until", "updated_at"}),
+	}).Create(mfa).Error
+}
+
+func (r *adminMFARepo) Enable(mfa models.AdminMFA, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveAdminMFA(tx, &mfa); err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, mfa.AdminID, codeHashes)
+	})
+}
+
+func (r *adminMFARepo) Delete(adminID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("admin_id = ?", adminID).Delete(&models.AdminRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("admin_id = ?", adminID).Delete(&models.AdminMFA{}).Error
+	})
+}
+
+func (r *adminMFARepo) WithLock(adminID uint, fn func(repo AdminMFARepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var mfa models.AdminMFA
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("admin_id = ?", adminID).First(&mfa).Error; err != nil {
+			return err
+		}
+		return fn(&adminMFARepo{db: tx})
+	})
+}
+
+func (r *adminMFARepo) ReplaceRecoveryCodes(adminID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, adminID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, adminID uint, codeHashes []string) error {
+	if err := tx.Where("admin_id = ?", adminID).Delete(&models.AdminRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	codes := make([]models.AdminRecoveryCode, len(codeHashes))
+	for i, h := range codeHashes {
+		codes[i] = models.AdminRecoveryCode{AdminID: adminID, CodeHash: h}
+	}
+	return tx.Create(&codes).Error
+}
+
+func (r *adminMFARepo) UseRecoveryCode(adminID uint, codeHash string, usedAt int64) (bool, error) {
+	// conditional update, so two requests cannot both use the same code
+	result := r.db.Model(&models.AdminRecoveryCode{}).
+		Where("admin_id = ? AND code_hash = ? AND used_at = 0", adminID, codeHash).
+		Update("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *adminMFARepo) CountUnusedRecoveryCodes(adminID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.AdminRecoveryCode{}).Where("admin_id = ? AND used_at = 0", adminID).Count(&n).Error
+	return n, err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var noteSubjectTables = map[models.NoteSubject]string{
+	models.NoteSubjectRegistration: "registrations",
+	models.NoteSubjectContact:      "contacts",
+}
+
+// NoteWithAuthor is a note with its author's username (empty when the admin was deleted).
+type NoteWithAuthor struct {
+	models.AdminNote
+	AuthorName string
+}
+
+// AdminNoteRepo stores internal notes and the assigned admin of registrations and contacts.
+type AdminNoteRepo interface {
+	Create(note *models.AdminNote) error
+	GetByID(id uint) (models.AdminNote, error)
+	// GetBySubject returns the notes of one record, oldest first.
+	GetBySubject(subject models.NoteSubject, subjectID uint) ([]NoteWithAuthor, error)
+	Delete(id uint) error
+	// SubjectExists reports whether the registration/contact exists.
+	SubjectExists(subject models.NoteSubject, subjectID uint) (bool, error)
+	// Assign sets assigned_to of a registration/contact; nil unassigns.
+	// gorm.ErrRecordNotFound when the record does not exist.
+	Assign(subject models.NoteSubject, subjectID uint, adminID *uint) error
+}
+
+type adminNoteRepo struct {
+	db *gorm.DB
+}
+
+func NewAdminNoteRepo(db *gorm.DB) AdminNoteRepo {
+	return &adminNoteRepo{db: db}
+}
+
+func (r *adminNoteRepo) Create(note *models.AdminNote) error {
+	return r.db.Create(note).Error
+}
+
+func (r *adminNoteRepo) GetByID(id uint) (models.AdminNote, error) {
+	var note models.AdminNote
+	err := r.db.First(&note, id).Error
+	return note, err
+}
+
+func (r *adminNoteRepo) GetBySubject(subject models.NoteSubject, subjectID uint) ([]NoteWithAuthor, error) {
+	var notes []NoteWithAuthor
+	err := r.db.Model(&models.AdminNote{}).
+		Select("admin_notes.*, COALESCE(admins.username, '') AS author_name").
+		Joins("LEFT JOIN admins ON admins.id = admin_notes.author_id").
+		Where("admin_notes.subject_type = ? AND admin_notes.subject_id = ?", subject, subjectID).
+		Order("admin_notes.created_at ASC, admin_notes.id ASC").
+		Scan(&notes).Error
+	return notes, err
+}
+
+func (r *adminNoteRepo) Delete(id uint) error {
+	return r.db.Delete(&models.AdminNote{}, id).Error
+}
+
+func (r *adminNoteRepo) SubjectExists(subject models.NoteSubject, subjectID uint) (bool, error) {
+	table, ok := noteSubjectTables[subject]
+	if !ok {
+		return false, errors.New("unknown note subject")
+	}
+	var count int64
+	err := r.db.Table(table).Where("id = ?", subjectID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *adminNoteRepo) Assign(subject models.NoteSubject, subjectID uint, adminID *uint) error {
+	table, ok := noteSubjectTables[subject]
+	if !ok {
+		return errors.New("unknown note subject")
+	}
+	res := r.db.Table(table).Where("id = ?", subjectID).Update("assigned_to", adminID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// deleteNotes removes the notes of a deleted registration/contact.
+func deleteNotes(tx *gorm.DB, subject models.NoteSubject, subjectID uint) error {
+	return tx.Where("subject_type = ? AND subject_id = ?", subject, subjectID).Delete(&models.AdminNote{}).Error
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"darulabror/internal/models"
+	"darulabror/internal/utils"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -9,12 +11,14 @@ import (
 type AdminRepository interface {
 	//Manage Admins by Superadmin
 	CreateAdmin(admin models.Admin) error
-	GetAllAdmins(page, limit int) ([]models.Admin, int64, error)
+	GetAllAdmins(q utils.ListQuery) ([]models.Admin, utils.PageInfo, error)
 	GetAdminByID(id uint) (models.Admin, error)
 	GetAdminByEmail(email string) (models.Admin, error)
 	UpdateAdmin(admin models.Admin) error
 	UpdatePassword(id uint, hashedPassword string) error
 	DeleteAdmin(id uint) error
+	// Active admins by username (assignment choices)
+	GetActiveAdmins() ([]models.Admin, error)
 }
 
 type adminRepository struct {
@@ -29,26 +33,11 @@ func (r *adminRepository) CreateAdmin(admin models.Admin) error {
 	return r.db.Create(&admin).Error
 }
 
-func (r *adminRepository) GetAllAdmins(page, limit int) ([]models.Admin, int64, error) {
-	var (
-		admins []models.Admin
-		total  int64
-	)
-
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 {
-		limit = 10
-	}
-	offset := (page - 1) * limit
-
-	if err := r.db.Model(&models.Admin{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	err := r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&admins).Error
-	return admins, total, err
+func (r *adminRepository) GetAllAdmins(q utils.ListQuery) ([]models.Admin, utils.PageInfo, error) {
+	query := AdminListSpec.Apply(r.db.Model(&models.Admin{}), q)
+	return findPage(query, AdminListSpec, q, func(a models.Admin) utils.Cursor {
+		return utils.Cursor{CreatedAt: time.Unix(a.CreatedAt, 0), ID: a.ID}
+	})
 }
 
 func (r *adminRepository) GetAdminByID(id uint) (models.Admin, error) {
@@ -71,6 +60,12 @@ func (r *adminRepository) DeleteAdmin(id uint) error {
 	return r.db.Delete(&models.Admin{}, id).Error
 }
 
+func (r *adminRepository) GetActiveAdmins() ([]models.Admin, error) {
+	var admins []models.Admin
+	err := r.db.Where("is_active = ?", true).Order("username ASC").Find(&admins).Error
+	return admins, err
+}
+
 func (r *adminRepository) UpdatePassword(id uint, hashedPassword string) error {
 	result := r.db.Model(&models.Admin{}).Where("id = ?", id).Update("password", hashedPassword)
 	if result.Error != nil {
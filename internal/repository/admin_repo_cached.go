@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"darulabror/internal/cache"
+	"darulabror/internal/models"
+	"strconv"
+	"time"
+)
+
+const activeAdminsTag = "active_admins"
+
+func adminTag(id uint) string {
+	return "admin:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// cachedAdminRepository caches admin lookups (login by email, profile and auth by id, assignees).
+// Entries include the password hash, so the cache must be as private as the database.
+type cachedAdminRepository struct {
+	AdminRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedAdminRepository decorates next; writes through it invalidate the affected entries.
+func NewCachedAdminRepository(next AdminRepository, c cache.Cache, ttl time.Duration) AdminRepository {
+	return &cachedAdminRepository{AdminRepository: next, cache: c, ttl: ttl}
+}
+
+func (r *cachedAdminRepository) GetAdminByID(id uint) (models.Admin, error) {
+	tag := adminTag(id)
+	return cache.Load(r.cache, tag, r.ttl, cache.Tagged[models.Admin](tag), func() (models.Admin, error) {
+		return r.AdminRepository.GetAdminByID(id)
+	})
+}
+
+func (r *cachedAdminRepository) GetAdminByEmail(email string) (models.Admin, error) {
+	// tagged by id, so updates (including a new email) drop the entry
+	byID := func(a models.Admin) []string { return []string{adminTag(a.ID)} }
+	return cache.Load(r.cache, "admin_email:"+email, r.ttl, byID, func() (models.Admin, error) {
+		return r.AdminRepository.GetAdminByEmail(email)
+	})
+}
+
+func (r *cachedAdminRepository) GetActiveAdmins() ([]models.Admin, error) {
+	return cache.Load(r.cache, "admins:active", r.ttl, cache.Tagged[[]models.Admin](activeAdminsTag), r.AdminRepository.GetActiveAdmins)
+}
+
+func (r *cachedAdminRepository) CreateAdmin(admin models.Admin) error {
+	if err := r.AdminRepository.CreateAdmin(admin); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, activeAdminsTag)
+	return nil
+}
+
+func (r *cachedAdminRepository) UpdateAdmin(admin models.Admin) error {
+	if err := r.AdminRepository.UpdateAdmin(admin); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, adminTag(admin.ID), activeAdminsTag)
+	return nil
+}
+
+func (r *cachedAdminRepository) UpdatePassword(id uint, hashedPassword string) error {
+	if err := r.AdminRepository.UpdatePassword(id, hashedPassword); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, adminTag(id))
+	return nil
+}
+
+func (r *cachedAdminRepository) DeleteAdmin(id uint) error {
+	if err := r.AdminRepository.DeleteAdmin(id); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, adminTag(id), activeAdminsTag)
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AdmissionPeriodRepo stores admission periods. Updates go through AdmissionQuotaRepo.UpdatePeriod,
+// which also promotes waitlisted registrations when the quota is raised.
+type AdmissionPeriodRepo interface {
+	Create(period *models.AdmissionPeriod) error
+	GetAll() ([]models.AdmissionPeriod, error)
+	GetByID(id uint) (models.AdmissionPeriod, error)
+	// GetOpen returns the period accepting registrations at now.
+	GetOpen(now time.Time) (models.AdmissionPeriod, error)
+	Delete(id uint) error
+
+	// ExistsOverlapping reports whether another period overlaps [opensAt, closesAt).
+	ExistsOverlapping(opensAt, closesAt time.Time, excludeID uint) (bool, error)
+	CountRegistrations(id uint) (int64, error)
+}
+
+type admissionPeriodRepo struct {
+	db *gorm.DB
+}
+
+func NewAdmissionPeriodRepo(db *gorm.DB) AdmissionPeriodRepo {
+	return &admissionPeriodRepo{db: db}
+}
+
+func (r *admissionPeriodRepo) Create(period *models.AdmissionPeriod) error {
+	return r.db.Create(period).Error
+}
+
+func (r *admissionPeriodRepo) GetAll() ([]models.AdmissionPeriod, error) {
+	var periods []models.AdmissionPeriod
+	err := r.db.Order("opens_at DESC").Find(&periods).Error
+	return periods, err
+}
+
+func (r *admissionPeriodRepo) GetByID(id uint) (models.AdmissionPeriod, error) {
+	var period models.AdmissionPeriod
+	err := r.db.First(&period, id).Error
+	return period, err
+}
+
+func (r *admissionPeriodRepo) GetOpen(now time.Time) (models.AdmissionPeriod, error) {
+	var period models.AdmissionPeriod
+	err := r.db.Where("opens_at <= ? AND closes_at > ?", now, now).
+		Order("opens_at ASC").
+		First(&period).Error
+	return period, err
+}
+
+func (r *admissionPeriodRepo) Delete(id uint) error {
+	result := r.db.Delete(&models.AdmissionPeriod{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *admissionPeriodRepo) ExistsOverlapping(opensAt, closesAt time.Time, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AdmissionPeriod{}).
+		Where("opens_at < ? AND closes_at > ? AND id <> ?", closesAt, opensAt, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *admissionPeriodRepo) CountRegistrations(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Registration{}).Where("admission_period_id = ?", id).Count(&count).Error
+	return count, err
+}
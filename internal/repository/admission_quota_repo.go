@@ -0,0 +1,518 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"errors"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoSeatAvailable is returned when a registration would need a seat but the quota is reached.
+	ErrNoSeatAvailable = errors.New("no seat available")
+	// ErrStatusChanged is returned when a registration no longer has the status an update requires.
+	ErrStatusChanged = errors.New("registration status changed")
+)
+
+var seatStatuses = []models.RegistrationStatus{
+	models.RegistrationStatusNew,
+	models.RegistrationStatusValidate,
+	models.RegistrationStatusProcess,
+	models.RegistrationStatusDone,
+}
+
+// SeatUsage is the occupancy of one gender/program group.
+type SeatUsage struct {
+	Gender     models.Gender
+	Program    models.Program
+	Taken      int64
+	Waitlisted int64
+}
+
+// AdmissionQuotaRepo manages quotas and every write that changes seat occupancy.
+// Those writes lock the admission period row (SELECT ... FOR UPDATE), so concurrent
+// registrations of one period are counted one at a time and never exceed a quota.
+type AdmissionQuotaRepo interface {
+	GetByPeriod(periodID uint) ([]models.AdmissionQuota, error)
+	// ReplaceForPeriod swaps the quota set and promotes waitlisted registrations into freed seats.
+	ReplaceForPeriod(periodID uint, quotas []models.AdmissionQuota) ([]models.Registration, error)
+	// UpdatePeriod saves the period and promotes waitlisted registrations when its quota was raised.
+	UpdatePeriod(period models.AdmissionPeriod) ([]models.Registration, error)
+	Usage(periodID uint) ([]SeatUsage, error)
+
+	// CreateRegistrations inserts regs (all of one period) in one transaction. Each gets status
+	// new, or waitlisted when its group is full; regs are updated with ID and Status.
+	CreateRegistrations(regs []models.Registration) error
+	// ChangeStatus returns the updated registration and the registrations promoted into a freed seat.
+	ChangeStatus(id uint, to models.RegistrationStatus, changedBy *uint) (models.Registration, []models.Registration, error)
+	// ChangeStatuses is ChangeStatus for several registrations in one transaction: when one
+	// change fails, none is applied.
+	ChangeStatuses(ids []uint, to models.RegistrationStatus, changedBy *uint) ([]models.Registration, error)
+	// DeleteRegistration deletes a registration and promotes the oldest waitlisted registration that fits.
+	DeleteRegistration(id uint) ([]models.Registration, error)
+	// UpdateRegistration is RegistrationRepo.Update for edits that may move a registration to another
+	// gender/program group. When requireStatus is set and the registration no longer has it, ErrStatusChanged
+	// is returned. Moving a seat holder into a full group fails with ErrNoSeatAvailable; the seat it leaves
+	// is offered to the waitlist.
+	UpdateRegistration(reg models.Registration, changes []models.RegistrationChange, requireStatus models.RegistrationStatus) ([]models.Registration, error)
+}
+
+type admissionQuotaRepo struct {
+	db *gorm.DB
+}
+
+func NewAdmissionQuotaRepo(db *gorm.DB) AdmissionQuotaRepo {
+	return &admissionQuotaRepo{db: db}
+}
+
+func (r *admissionQuotaRepo) GetByPeriod(periodID uint) ([]models.AdmissionQuota, error) {
+	var quotas []models.AdmissionQuota
+	err := r.db.Where("admission_period_id = ?", periodID).Order("gender, program").Find(&quotas).Error
+	return quotas, err
+}
+
+func (r *admissionQuotaRepo) ReplaceForPeriod(periodID uint, quotas []models.AdmissionQuota) ([]models.Registration, error) {
+	var promoted []models.Registration
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		period, err := lockPeriod(tx, periodID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("admission_period_id = ?", periodID).Delete(&models.AdmissionQuota{}).Error; err != nil {
+			return err
+		}
+		for i := range quotas {
+			quotas[i].ID = 0
+			quotas[i].AdmissionPeriodID = periodID
+		}
+		if len(quotas) > 0 {
+			if err := tx.Create(&quotas).Error; err != nil {
+				return err
+			}
+		}
+
+		promoted, err = promoteWaitlisted(tx, period)
+		return err
+	})
+	return promoted, err
+}
+
+func (r *admissionQuotaRepo) UpdatePeriod(period models.AdmissionPeriod) ([]models.Registration, error) {
+	var promoted []models.Registration
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockPeriod(tx, period.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(&period).Error; err != nil {
+			return err
+		}
+
+		var err error
+		promoted, err = promoteWaitlisted(tx, period)
+		return err
+	})
+	return promoted, err
+}
+
+func (r *admissionQuotaRepo) Usage(periodID uint) ([]SeatUsage, error) {
+	var usage []SeatUsage
+	err := r.db.Model(&models.Registration{}).
+		Select("gender, program, "+
+			"COUNT(*) FILTER (WHERE status IN ?) AS taken, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS waitlisted",
+			seatStatuses, models.RegistrationStatusWaitlisted).
+		Where("admission_period_id = ?", periodID).
+		Group("gender, program").
+		Scan(&usage).Error
+	return usage, err
+}
+
+func (r *admissionQuotaRepo) CreateRegistrations(regs []models.Registration) error {
+	if len(regs) == 0 {
+		return nil
+	}
+	if regs[0].AdmissionPeriodID == nil {
+		return errors.New("registration admission period is required")
+	}
+	periodID := *regs[0].AdmissionPeriodID
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		period, err := lockPeriod(tx, periodID)
+		if err != nil {
+			return err
+		}
+
+		for i := range regs {
+			c, err := countSeats(tx, period, regs[i].Gender, regs[i].Program, 0)
+			if err != nil {
+				return err
+			}
+			var reason string
+			regs[i].Status, reason = initialStatus(c)
+
+			if err := tx.Create(&regs[i]).Error; err != nil {
+				return registrationUniqueError(err)
+			}
+			if err := recordStatus(tx, regs[i].ID, "", regs[i].Status, reason, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *admissionQuotaRepo) ChangeStatus(id uint, to models.RegistrationStatus, changedBy *uint) (models.Registration, []models.Registration, error) {
+	var (
+		reg      models.Registration
+		promoted []models.Registration
+	)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reg, promoted, err = changeStatus(tx, id, to, changedBy)
+		return err
+	})
+	return reg, promoted, err
+}
+
+func (r *admissionQuotaRepo) ChangeStatuses(ids []uint, to models.RegistrationStatus, changedBy *uint) ([]models.Registration, error) {
+	var promoted []models.Registration
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			_, p, err := changeStatus(tx, id, to, changedBy)
+			if err != nil {
+				return err
+			}
+			promoted = append(promoted, p...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+func changeStatus(tx *gorm.DB, id uint, to models.RegistrationStatus, changedBy *uint) (models.Registration, []models.Registration, error) {
+	reg, period, err := lockRegistration(tx, id)
+	if err != nil {
+		return reg, nil, err
+	}
+
+	from := reg.Status
+	if from == to {
+		return reg, nil, nil
+	}
+	if period != nil {
+		err := admitStatusChange(from, to, func() (seatCount, error) {
+			return countSeats(tx, *period, reg.Gender, reg.Program, reg.ID)
+		})
+		if err != nil {
+			return reg, nil, err
+		}
+	}
+
+	if err := tx.Model(&models.Registration{}).Where("id = ?", reg.ID).Update("status", to).Error; err != nil {
+		return reg, nil, err
+	}
+	reg.Status = to
+	if err := recordStatus(tx, reg.ID, from, to, models.StatusReasonAdmin, changedBy); err != nil {
+		return reg, nil, err
+	}
+
+	if period != nil && from.HoldsSeat() && !to.HoldsSeat() {
+		promoted, err := promoteWaitlisted(tx, *period)
+		return reg, promoted, err
+	}
+	return reg, nil, nil
+}
+
+func (r *admissionQuotaRepo) DeleteRegistration(id uint) ([]models.Registration, error) {
+	var promoted []models.Registration
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reg, period, err := lockRegistration(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("registration_id = ?", reg.ID).Delete(&models.RegistrationStatusHistory{}).Error; err != nil {
+			return err
+		}
+		if err := deleteNotes(tx, models.NoteSubjectRegistration, reg.ID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Registration{}, reg.ID).Error; err != nil {
+			return err
+		}
+
+		if period != nil && reg.Status.HoldsSeat() {
+			promoted, err = promoteWaitlisted(tx, *period)
+			return err
+		}
+		return nil
+	})
+	return promoted, err
+}
+
+func (r *admissionQuotaRepo) UpdateRegistration(reg models.Registration, changes []models.RegistrationChange, requireStatus models.RegistrationStatus) ([]models.Registration, error) {
+	var promoted []models.Registration
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		current, period, err := lockRegistration(tx, reg.ID)
+		if err != nil {
+			return err
+		}
+		if requireStatus != "" && current.Status != requireStatus {
+			return ErrStatusChanged
+		}
+
+		moved := current.Gender != reg.Gender || current.Program != reg.Program
+		if period != nil && moved && current.Status.HoldsSeat() {
+			c, err := countSeats(tx, *period, reg.Gender, reg.Program, reg.ID)
+			if err != nil {
+				return err
+			}
+			if !c.free() {
+				return ErrNoSeatAvailable
+			}
+		}
+
+		if err := saveRegistration(tx, reg, changes); err != nil {
+			return err
+		}
+
+		if period != nil && moved && current.Status.HoldsSeat() {
+			promoted, err = promoteWaitlisted(tx, *period)
+			return err
+		}
+		return nil
+	})
+	return promoted, err
+}
+
+// lockRegistration loads a registration with its period locked (period first, so the lock
+// order matches CreateRegistrations). period is nil for registrations without a period.
+func lockRegistration(tx *gorm.DB, id uint) (models.Registration, *models.AdmissionPeriod, error) {
+	var reg models.Registration
+	if err := tx.First(&reg, id).Error; err != nil {
+		return reg, nil, err
+	}
+	if reg.AdmissionPeriodID == nil {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reg, id).Error
+		return reg, nil, err
+	}
+
+	period, err := lockPeriod(tx, *reg.AdmissionPeriodID)
+	if err != nil {
+		return reg, nil, err
+	}
+	// re-read: the status may have changed while waiting for the lock
+	if err := tx.First(&reg, id).Error; err != nil {
+		return reg, nil, err
+	}
+	return reg, &period, nil
+}
+
+func lockPeriod(tx *gorm.DB, periodID uint) (models.AdmissionPeriod, error) {
+	var period models.AdmissionPeriod
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&period, periodID).Error
+	return period, err
+}
+
+// seatCount is the occupancy a seat decision is made on.
+type seatCount struct {
+	periodQuota int64 // 0 = unlimited
+	periodTaken int64
+	groupQuota  int64 // -1 = the gender/program group has no quota
+	groupTaken  int64
+}
+
+// free reports whether one more registration fits: the period-wide quota is checked
+// first, so a full period admits nobody even when the group still has seats.
+func (c seatCount) free() bool {
+	if c.periodQuota > 0 && c.periodTaken >= c.periodQuota {
+		return false
+	}
+	return c.groupQuota < 0 || c.groupTaken < c.groupQuota
+}
+
+// initialStatus is the status of a new registration: new, or waitlisted when its group is full.
+func initialStatus(c seatCount) (models.RegistrationStatus, string) {
+	if !c.free() {
+		return models.RegistrationStatusWaitlisted, models.StatusReasonQuotaFull
+	}
+	return models.RegistrationStatusNew, models.StatusReasonRegistered
+}
+
+// admitStatusChange returns ErrNoSeatAvailable when from -> to needs a seat that is not free.
+// counts is only called when the change needs a seat.
+func admitStatusChange(from, to models.RegistrationStatus, counts func() (seatCount, error)) error {
+	if from.HoldsSeat() || !to.HoldsSeat() {
+		return nil
+	}
+	c, err := counts()
+	if err != nil {
+		return err
+	}
+	if !c.free() {
+		return ErrNoSeatAvailable
+	}
+	return nil
+}
+
+// seatGroup is a gender/program group of a period.
+type seatGroup struct {
+	gender  models.Gender
+	program models.Program
+}
+
+// seatPlan is the occupancy of a whole period, for decisions that span all its groups.
+type seatPlan struct {
+	periodQuota int64 // 0 = unlimited
+	periodTaken int64
+	groupQuota  map[seatGroup]int64 // groups without a quota are missing
+	groupTaken  map[seatGroup]int64
+}
+
+func (p seatPlan) count(g seatGroup) seatCount {
+	c := seatCount{periodQuota: p.periodQuota, periodTaken: p.periodTaken, groupQuota: -1, groupTaken: p.groupTaken[g]}
+	if q, ok := p.groupQuota[g]; ok {
+		c.groupQuota = q
+	}
+	return c
+}
+
+// pickPromotions returns the waitlisted registrations that get the free seats: oldest first
+// across all groups, skipping those whose group is still full.
+func pickPromotions(p seatPlan, waitlisted []models.Registration) []models.Registration {
+	sorted := append([]models.Registration(nil), waitlisted...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	taken := make(map[seatGroup]int64, len(p.groupTaken))
+	for g, n := range p.groupTaken {
+		taken[g] = n
+	}
+	p.groupTaken = taken
+
+	var picked []models.Registration
+	for _, reg := range sorted {
+		if p.periodQuota > 0 && p.periodTaken >= p.periodQuota {
+			break
+		}
+		g := seatGroup{reg.Gender, reg.Program}
+		if !p.count(g).free() {
+			continue
+		}
+		p.periodTaken++
+		p.groupTaken[g]++
+		picked = append(picked, reg)
+	}
+	return picked
+}
+
+// countSeats counts the period-wide and gender/program occupancy; excludeID is not counted.
+// Must run inside a transaction holding the period lock.
+func countSeats(tx *gorm.DB, period models.AdmissionPeriod, gender models.Gender, program models.Program, excludeID uint) (seatCount, error) {
+	c := seatCount{periodQuota: int64(period.Quota), groupQuota: -1}
+	seats := func() *gorm.DB {
+		return tx.Model(&models.Registration{}).
+			Where("admission_period_id = ? AND status IN ? AND id <> ?", period.ID, seatStatuses, excludeID)
+	}
+
+	if c.periodQuota > 0 {
+		if err := seats().Count(&c.periodTaken).Error; err != nil {
+			return c, err
+		}
+	}
+
+	var quota models.AdmissionQuota
+	err := tx.Where("admission_period_id = ? AND gender = ? AND program = ?", period.ID, gender, program).First(&quota).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	c.groupQuota = int64(quota.Seats)
+	err = seats().Where("gender = ? AND program = ?", gender, program).Count(&c.groupTaken).Error
+	return c, err
+}
+
+// loadSeatPlan reads the quotas and seat holders of every group of the period.
+// Must run inside a transaction holding the period lock.
+func loadSeatPlan(tx *gorm.DB, period models.AdmissionPeriod) (seatPlan, error) {
+	p := seatPlan{
+		periodQuota: int64(period.Quota),
+		groupQuota:  map[seatGroup]int64{},
+		groupTaken:  map[seatGroup]int64{},
+	}
+
+	var quotas []models.AdmissionQuota
+	if err := tx.Where("admission_period_id = ?", period.ID).Find(&quotas).Error; err != nil {
+		return p, err
+	}
+	for _, q := range quotas {
+		p.groupQuota[seatGroup{q.Gender, q.Program}] = int64(q.Seats)
+	}
+
+	var usage []SeatUsage
+	if err := tx.Model(&models.Registration{}).
+		Select("gender, program, COUNT(*) AS taken").
+		Where("admission_period_id = ? AND status IN ?", period.ID, seatStatuses).
+		Group("gender, program").
+		Scan(&usage).Error; err != nil {
+		return p, err
+	}
+	for _, u := range usage {
+		p.groupTaken[seatGroup{u.Gender, u.Program}] = u.Taken
+		p.periodTaken += u.Taken
+	}
+	return p, nil
+}
+
+// promoteWaitlisted moves the oldest waitlisted registrations of the period to "new" while
+// their group and the period have free seats. Must run inside a transaction holding the period lock.
+func promoteWaitlisted(tx *gorm.DB, period models.AdmissionPeriod) ([]models.Registration, error) {
+	var waitlisted []models.Registration
+	if err := tx.Where("admission_period_id = ? AND status = ?", period.ID, models.RegistrationStatusWaitlisted).
+		Find(&waitlisted).Error; err != nil {
+		return nil, err
+	}
+	if len(waitlisted) == 0 {
+		return nil, nil
+	}
+
+	plan, err := loadSeatPlan(tx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := pickPromotions(plan, waitlisted)
+	for i := range promoted {
+		if err := tx.Model(&models.Registration{}).Where("id = ?", promoted[i].ID).
+			Update("status", models.RegistrationStatusNew).Error; err != nil {
+			return nil, err
+		}
+		if err := recordStatus(tx, promoted[i].ID, models.RegistrationStatusWaitlisted, models.RegistrationStatusNew, models.StatusReasonPromoted, nil); err != nil {
+			return nil, err
+		}
+		promoted[i].Status = models.RegistrationStatusNew
+	}
+	return promoted, nil
+}
+
+func recordStatus(tx *gorm.DB, regID uint, from, to models.RegistrationStatus, reason string, changedBy *uint) error {
+	return tx.Create(&models.RegistrationStatusHistory{
+		RegistrationID: regID,
+		FromStatus:     from,
+		ToStatus:       to,
+		Reason:         reason,
+		ChangedBy:      changedBy,
+	}).Error
+}
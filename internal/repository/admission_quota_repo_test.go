@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSeatCountFree(t *testing.T) {
+	tests := []struct {
+		name string
+		c    seatCount
+		want bool
+	}{
+		{"no quotas", seatCount{groupQuota: -1}, true},
+		{"group has seats", seatCount{groupQuota: 2, groupTaken: 1}, true},
+		{"group full", seatCount{groupQuota: 2, groupTaken: 2}, false},
+		{"period full, group has seats", seatCount{periodQuota: 10, periodTaken: 10, groupQuota: 5, groupTaken: 1}, false},
+		{"period full, group without quota", seatCount{periodQuota: 10, periodTaken: 10, groupQuota: -1}, false},
+		{"period has seats, group full", seatCount{periodQuota: 10, periodTaken: 3, groupQuota: 3, groupTaken: 3}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.c.free(); got != tt.want {
+			t.Errorf("%s: free() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInitialStatus(t *testing.T) {
+	if status, reason := initialStatus(seatCount{groupQuota: 1, groupTaken: 1}); status != models.RegistrationStatusWaitlisted || reason != models.StatusReasonQuotaFull {
+		t.Errorf("full group: initialStatus() = %s, %s", status, reason)
+	}
+	if status, _ := initialStatus(seatCount{groupQuota: 1}); status != models.RegistrationStatusNew {
+		t.Errorf("free group: initialStatus() = %s, want new", status)
+	}
+}
+
+func TestAdmitStatusChange(t *testing.T) {
+	full := func() (seatCount, error) { return seatCount{groupQuota: 1, groupTaken: 1}, nil }
+
+	if err := admitStatusChange(models.RegistrationStatusWaitlisted, models.RegistrationStatusNew, full); !errors.Is(err, ErrNoSeatAvailable) {
+		t.Errorf("waitlisted -> new on a full group: error = %v, want %v", err, ErrNoSeatAvailable)
+	}
+	if err := admitStatusChange(models.RegistrationStatusNew, models.RegistrationStatusProcess, full); err != nil {
+		t.Errorf("seat holder -> seat holder: error = %v, want nil", err)
+	}
+	if err := admitStatusChange(models.RegistrationStatusNew, models.RegistrationStatusWithdrawn, full); err != nil {
+		t.Errorf("withdraw: error = %v, want nil", err)
+	}
+	free := func() (seatCount, error) { return seatCount{groupQuota: 2, groupTaken: 1}, nil }
+	if err := admitStatusChange(models.RegistrationStatusWaitlisted, models.RegistrationStatusNew, free); err != nil {
+		t.Errorf("waitlisted -> new with a free seat: error = %v, want nil", err)
+	}
+}
+
+func TestPickPromotions(t *testing.T) {
+	base := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	waitlisted := []models.Registration{
+		{ID: 9, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 7, CreatedAt: base},
+		{ID: 4, CreatedAt: base.Add(time.Hour)},
+		{ID: 3, CreatedAt: base},
+	}
+	group := seatGroup{}
+
+	// a withdrawal or delete freed one seat in a group of 5
+	got := pickPromotions(seatPlan{groupQuota: map[seatGroup]int64{group: 5}, groupTaken: map[seatGroup]int64{group: 4}}, waitlisted)
+	if len(got) != 1 || got[0].ID != 3 {
+		t.Fatalf("one free seat: promoted %+v, want the oldest registration (3)", got)
+	}
+
+	got = pickPromotions(seatPlan{periodQuota: 20, periodTaken: 18}, waitlisted)
+	if len(got) != 2 || got[0].ID != 3 || got[1].ID != 7 {
+		t.Errorf("period quota with 2 seats left: promoted %+v, want 3 and 7", got)
+	}
+	if got := pickPromotions(seatPlan{groupQuota: map[seatGroup]int64{group: 1}, groupTaken: map[seatGroup]int64{group: 1}}, waitlisted); len(got) != 0 {
+		t.Errorf("full group: promoted %+v, want none", got)
+	}
+}
+
+func TestPickPromotionsAcrossGroups(t *testing.T) {
+	base := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	maleBoarding := seatGroup{models.Male, models.ProgramBoarding}
+	femaleBoarding := seatGroup{models.Female, models.ProgramBoarding}
+	maleNonBoarding := seatGroup{models.Male, models.ProgramNonBoarding}
+	reg := func(id uint, g seatGroup, age time.Duration) models.Registration {
+		return models.Registration{ID: id, Gender: g.gender, Program: g.program, CreatedAt: base.Add(age)}
+	}
+	waitlisted := []models.Registration{
+		reg(1, maleBoarding, 0),
+		reg(2, femaleBoarding, time.Hour),
+		reg(3, maleNonBoarding, 2*time.Hour),
+		reg(4, femaleBoarding, 3*time.Hour),
+	}
+	ids := func(regs []models.Registration) []uint {
+		var out []uint
+		for _, r := range regs {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		plan seatPlan
+		want []uint
+	}{
+		{
+			// a male boarding seat holder withdrew, but the period is the limit: the oldest
+			// waitlisted registration gets the seat, whatever its group
+			name: "freed seat goes to the oldest of the period",
+			plan: seatPlan{periodQuota: 10, periodTaken: 9},
+			want: []uint{1},
+		},
+		{
+			name: "full groups are skipped",
+			plan: seatPlan{
+				periodQuota: 10, periodTaken: 9,
+				groupQuota: map[seatGroup]int64{maleBoarding: 5, femaleBoarding: 4},
+				groupTaken: map[seatGroup]int64{maleBoarding: 5, femaleBoarding: 4},
+			},
+			want: []uint{3},
+		},
+		{
+			name: "raised period quota fills several groups",
+			plan: seatPlan{
+				periodQuota: 13, periodTaken: 10,
+				groupQuota: map[seatGroup]int64{femaleBoarding: 5},
+				groupTaken: map[seatGroup]int64{femaleBoarding: 4},
+			},
+			want: []uint{1, 2, 3},
+		},
+		{
+			name: "raised group quota",
+			plan: seatPlan{
+				groupQuota: map[seatGroup]int64{maleBoarding: 3, femaleBoarding: 6, maleNonBoarding: 2},
+				groupTaken: map[seatGroup]int64{maleBoarding: 3, femaleBoarding: 4, maleNonBoarding: 2},
+			},
+			want: []uint{2, 4},
+		},
+		{
+			name: "unlimited period without group quotas",
+			plan: seatPlan{},
+			want: []uint{1, 2, 3, 4},
+		},
+		{
+			name: "period full",
+			plan: seatPlan{periodQuota: 10, periodTaken: 10},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		taken := maps.Clone(tt.plan.groupTaken)
+		got := ids(pickPromotions(tt.plan, waitlisted))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: promoted %v, want %v", tt.name, got, tt.want)
+		}
+		if !maps.Equal(tt.plan.groupTaken, taken) {
+			t.Errorf("%s: pickPromotions changed the plan's group counts", tt.name)
+		}
+	}
+}
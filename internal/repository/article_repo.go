@@ -3,17 +3,21 @@ package repository
 import (
 	"darulabror/internal/models"
 	"darulabror/internal/utils"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type ArticleRepo interface {
 	Create(article models.Article) error
-	GetAll(page, limit int) ([]models.Article, int64, error)
-	GetPublished(page, limit int) ([]models.Article, int64, error)
+	GetAll(q utils.ListQuery) ([]models.Article, utils.PageInfo, error)
+	GetPublished(q utils.ListQuery) ([]models.Article, utils.PageInfo, error)
 	GetByID(id uint) (models.Article, error)
 	Update(article models.Article) error
 	Delete(id uint) error
+	// Aggregates (dashboard)
+	CountByStatus() ([]KeyCount, error)
+	LatestPublished(limit int) ([]models.Article, error)
 }
 
 type articleRepo struct {
@@ -28,38 +32,37 @@ func (a *articleRepo) Create(article models.Article) error {
 	return a.db.Create(&article).Error
 }
 
-func (a *articleRepo) GetAll(page, limit int) ([]models.Article, int64, error) {
-	var (
-		articles []models.Article
-		total    int64
-	)
-
-	_, limit, offset := utils.NormalizePageLimit(page, limit)
-
-	if err := a.db.Model(&models.Article{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	err := a.db.Order("id DESC").Limit(limit).Offset(offset).Find(&articles).Error
-	return articles, total, err
+func (a *articleRepo) GetAll(q utils.ListQuery) ([]models.Article, utils.PageInfo, error) {
+	query := ArticleListSpec.Apply(a.db.Model(&models.Article{}), q)
+	return findPage(query, ArticleListSpec, q, articleCursor)
 }
 
-func (a *articleRepo) GetPublished(page, limit int) ([]models.Article, int64, error) {
-	var (
-		articles []models.Article
-		total    int64
-	)
-
-	_, limit, offset := utils.NormalizePageLimit(page, limit)
+func (a *articleRepo) CountByStatus() ([]KeyCount, error) {
+	var rows []KeyCount
+	err := a.db.Model(&models.Article{}).
+		Select("status AS key, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	return rows, err
+}
 
-	q := a.db.Model(&models.Article{}).Where("status = ?", "published")
+func (a *articleRepo) LatestPublished(limit int) ([]models.Article, error) {
+	var articles []models.Article
+	err := a.db.Select("id, title, author, status, photo_header, created_at, updated_at").
+		Where("status = ?", "published").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
 
-	if err := q.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+func (a *articleRepo) GetPublished(q utils.ListQuery) ([]models.Article, utils.PageInfo, error) {
+	query := a.db.Model(&models.Article{}).Where("status = ?", "published")
+	return findPage(query, ArticleListSpec, q, articleCursor)
+}
 
-	err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&articles).Error
-	return articles, total, err
+func articleCursor(a models.Article) utils.Cursor {
+	return utils.Cursor{CreatedAt: time.Unix(a.CreatedAt, 0), ID: a.ID}
 }
 
 func (a *articleRepo) GetByID(id uint) (models.Article, error) {
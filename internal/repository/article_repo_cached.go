@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"darulabror/internal/cache"
+	"darulabror/internal/models"
+	"darulabror/internal/utils"
+	"strconv"
+	"time"
+)
+
+const articleListsTag = "article_lists"
+
+func articleTag(id uint) string {
+	return "article:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// cachedArticleRepo caches the public reads (published lists, article by id) of an ArticleRepo.
+// Admin lists and aggregates always hit the database.
+type cachedArticleRepo struct {
+	ArticleRepo
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+type articlePage struct {
+	Items []models.Article
+	Page  utils.PageInfo
+}
+
+// NewCachedArticleRepo decorates next; writes through it invalidate the affected entries.
+func NewCachedArticleRepo(next ArticleRepo, c cache.Cache, ttl time.Duration) ArticleRepo {
+	return &cachedArticleRepo{ArticleRepo: next, cache: c, ttl: ttl}
+}
+
+func (r *cachedArticleRepo) GetPublished(q utils.ListQuery) ([]models.Article, utils.PageInfo, error) {
+	page, err := cache.Load(r.cache, "articles:published:"+q.CacheKey(), r.ttl, cache.Tagged[articlePage](articleListsTag), func() (articlePage, error) {
+		items, page, err := r.ArticleRepo.GetPublished(q)
+		return articlePage{Items: items, Page: page}, err
+	})
+	return page.Items, page.Page, err
+}
+
+func (r *cachedArticleRepo) GetByID(id uint) (models.Article, error) {
+	tag := articleTag(id)
+	return cache.Load(r.cache, tag, r.ttl, cache.Tagged[models.Article](tag), func() (models.Article, error) {
+		return r.ArticleRepo.GetByID(id)
+	})
+}
+
+func (r *cachedArticleRepo) Create(article models.Article) error {
+	if err := r.ArticleRepo.Create(article); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, articleListsTag)
+	return nil
+}
+
+func (r *cachedArticleRepo) Update(article models.Article) error {
+	if err := r.ArticleRepo.Update(article); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, articleTag(article.ID), articleListsTag)
+	return nil
+}
+
+func (r *cachedArticleRepo) Delete(id uint) error {
+	if err := r.ArticleRepo.Delete(id); err != nil {
+		return err
+	}
+	cache.Invalidate(r.cache, articleTag(id), articleListsTag)
+	return nil
+}
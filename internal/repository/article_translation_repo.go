@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleTranslationRepo interface {
+	GetByArticleIDs(articleIDs []uint) ([]models.ArticleTranslation, error)
+	Get(articleID uint, locale models.Locale) (models.ArticleTranslation, error)
+	// Save creates or replaces the translation of an article in its locale.
+	Save(t *models.ArticleTranslation) error
+	Delete(articleID uint, locale models.Locale) error
+	// SlugOwner finds the article and locale using slug, among articles and translations.
+	// Returns gorm.ErrRecordNotFound when the slug is free.
+	SlugOwner(slug string) (uint, models.Locale, error)
+}
+
+type articleTranslationRepo struct {
+	db *gorm.DB
+}
+
+func NewArticleTranslationRepo(db *gorm.DB) ArticleTranslationRepo {
+	return &articleTranslationRepo{db: db}
+}
+
+func (r *articleTranslationRepo) GetByArticleIDs(articleIDs []uint) ([]models.ArticleTranslation, error) {
+	var out []models.ArticleTranslation
+	if len(articleIDs) == 0 {
+		return out, nil
+	}
+	err := r.db.Where("article_id IN ?", articleIDs).Order("article_id ASC, locale ASC").Find(&out).Error
+	return out, err
+}
+
+func (r *articleTranslationRepo) Get(articleID uint, locale models.Locale) (models.ArticleTranslation, error) {
+	var t models.ArticleTranslation
+	err := r.db.Where("article_id = ? AND locale = ?", articleID, locale).First(&t).Error
+	return t, err
+}
+
+func (r *articleTranslationRepo) Save(t *models.ArticleTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "slug", "content", "source_hash", "updated_at"}),
+	}).Create(t).Error
+}
+
+func (r *articleTranslationRepo) Delete(articleID uint, locale models.Locale) error {
+	result := r.db.Where("article_id = ? AND locale = ?", articleID, locale).Delete(&models.ArticleTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *articleTranslationRepo) SlugOwner(slug string) (uint, models.Locale, error) {
+	var article models.Article
+	err := r.db.Select("id").Where("slug = ?", slug).First(&article).Error
+	if err == nil {
+		return article.ID, models.DefaultLocale, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, "", err
+	}
+
+	var t models.ArticleTranslation
+	if err := r.db.Select("article_id, locale").Where("slug = ?", slug).First(&t).Error; err != nil {
+		return 0, "", err
+	}
+	return t.ArticleID, t.Locale, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ArticleViewCount is a batch of views of one article on one day (YYYY-MM-DD, WIB).
+type ArticleViewCount struct {
+	ArticleID uint
+	Day       string
+	Views     int64
+}
+
+// PopularArticle is a published article with its views in a window.
+type PopularArticle struct {
+	ID          uint
+	Title       string
+	Author      string
+	PhotoHeader string
+	CreatedAt   int64
+	Views       int64
+}
+
+type ArticleViewRepo interface {
+	// AddViews adds the counts to article_views_daily; counts of deleted articles are skipped.
+	// Each article and day must appear at most once.
+	AddViews(counts []ArticleViewCount) error
+	// Popular ranks published articles by their views on days >= since (YYYY-MM-DD).
+	Popular(since string, limit int) ([]PopularArticle, error)
+	// DailyViews counts the views of an article per day in [from, to]; days without views are absent.
+	DailyViews(articleID uint, from, to string) ([]DailyCount, error)
+	TotalViews(articleID uint) (int64, error)
+}
+
+type articleViewRepo struct {
+	db *gorm.DB
+}
+
+func NewArticleViewRepo(db *gorm.DB) ArticleViewRepo {
+	return &articleViewRepo{db: db}
+}
+
+func (r *articleViewRepo) AddViews(counts []ArticleViewCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(counts))
+	args := make([]interface{}, 0, len(counts)*3)
+	for _, c := range counts {
+		values = append(values, "(?::bigint, ?::date, ?::bigint)")
+		args = append(args, c.ArticleID, c.Day, c.Views)
+	}
+
+	// join articles so a view recorded just before a delete does not fail the whole batch
+	sql := "INSERT INTO article_views_daily (article_id, day, views) " +
+		"SELECT v.article_id, v.day, v.views FROM (VALUES " + strings.Join(values, ", ") + ") AS v(article_id, day, views) " +
+		"JOIN articles a ON a.id = v.article_id " +
+		"ON CONFLICT (article_id, day) DO UPDATE SET views = article_views_daily.views + EXCLUDED.views"
+	return r.db.Exec(sql, args...).Error
+}
+
+func (r *articleViewRepo) Popular(since string, limit int) ([]PopularArticle, error) {
+	var rows []PopularArticle
+	err := r.db.Table("article_views_daily v").
+		Select("a.id, a.title, a.author, a.photo_header, a.created_at, SUM(v.views) AS views").
+		Joins("JOIN articles a ON a.id = v.article_id").
+		Where("a.status = ? AND v.day >= ?", "published", since).
+		Group("a.id").
+		Order("views DESC, a.id DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *articleViewRepo) DailyViews(articleID uint, from, to string) ([]DailyCount, error) {
+	var rows []DailyCount
+	err := r.db.Model(&models.ArticleViewDaily{}).
+		Select("to_char(day, 'YYYY-MM-DD') AS day, views AS count").
+		Where("article_id = ? AND day >= ? AND day <= ?", articleID, from, to).
+		Order("day ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *articleViewRepo) TotalViews(articleID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.ArticleViewDaily{}).
+		Select("COALESCE(SUM(views), 0)").
+		Where("article_id = ?", articleID).
+		Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"darulabror/internal/cache"
+	"darulabror/internal/models"
+	"darulabror/internal/utils"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type countingArticleRepo struct {
+	ArticleRepo
+	articles map[uint]models.Article
+	reads    int
+}
+
+func (r *countingArticleRepo) GetByID(id uint) (models.Article, error) {
+	r.reads++
+	a, ok := r.articles[id]
+	if !ok {
+		return models.Article{}, gorm.ErrRecordNotFound
+	}
+	return a, nil
+}
+
+func (r *countingArticleRepo) GetPublished(q utils.ListQuery) ([]models.Article, utils.PageInfo, error) {
+	r.reads++
+	return []models.Article{r.articles[1]}, utils.PageInfo{}, nil
+}
+
+func (r *countingArticleRepo) Update(a models.Article) error {
+	r.articles[a.ID] = a
+	return nil
+}
+
+func TestCachedArticleRepo(t *testing.T) {
+	next := &countingArticleRepo{articles: map[uint]models.Article{1: {ID: 1, Title: "Old"}}}
+	repo := NewCachedArticleRepo(next, cache.NewMemory(100), time.Minute)
+	q := utils.ListQuery{Page: 1, Limit: 10}
+
+	repo.GetByID(1)
+	repo.GetPublished(q)
+	if a, _ := repo.GetByID(1); a.Title != "Old" {
+		t.Errorf("cached title = %q", a.Title)
+	}
+	repo.GetPublished(q)
+	if next.reads != 2 {
+		t.Errorf("reads = %d, want 2 (repeats served from cache)", next.reads)
+	}
+
+	// misses are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := repo.GetByID(9); err != gorm.ErrRecordNotFound {
+			t.Fatalf("GetByID(9) error = %v", err)
+		}
+	}
+	if next.reads != 4 {
+		t.Errorf("reads = %d, want 4 (not found is not cached)", next.reads)
+	}
+
+	repo.Update(models.Article{ID: 1, Title: "New"})
+	if a, _ := repo.GetByID(1); a.Title != "New" {
+		t.Errorf("title after update = %q, want New", a.Title)
+	}
+	if items, _, _ := repo.GetPublished(q); items[0].Title != "New" {
+		t.Errorf("list after update = %+v, want the new title", items)
+	}
+}
+
+type countingAdminRepo struct {
+	AdminRepository
+	admin models.Admin
+	reads int
+}
+
+func (r *countingAdminRepo) GetAdminByEmail(email string) (models.Admin, error) {
+	r.reads++
+	return r.admin, nil
+}
+
+func (r *countingAdminRepo) UpdatePassword(id uint, hashedPassword string) error {
+	r.admin.Password = hashedPassword
+	return nil
+}
+
+func TestCachedAdminRepositoryEmailInvalidatedByID(t *testing.T) {
+	next := &countingAdminRepo{admin: models.Admin{ID: 3, Email: "a@darulabror.com", Password: "old"}}
+	repo := NewCachedAdminRepository(next, cache.NewMemory(100), time.Minute)
+
+	repo.GetAdminByEmail("a@darulabror.com")
+	repo.GetAdminByEmail("a@darulabror.com")
+	if next.reads != 1 {
+		t.Fatalf("reads = %d, want 1", next.reads)
+	}
+
+	repo.UpdatePassword(3, "new")
+	if a, _ := repo.GetAdminByEmail("a@darulabror.com"); a.Password != "new" {
+		t.Errorf("password after update = %q, want new (email entry tagged by id)", a.Password)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"darulabror/internal/models"
 	"darulabror/internal/utils"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -11,11 +12,13 @@ type ContactRepository interface {
 	// Public methods for contact Admin
 	CreateContact(email, subject, message string) error
 	// Admin methods for contact
-	GetAllContacts(page, limit int, status string) ([]models.Contact, int64, error)
+	GetAllContacts(q utils.ListQuery) ([]models.Contact, utils.PageInfo, error)
 	GetContactByID(id uint) (*models.Contact, error)
 	UpdateContact(id uint, email, subject, message string) error
 	UpdateContactStatus(id uint, status models.ContactStatus) error
 	DeleteContact(id uint) error
+	// Backlog counts contacts per status and age bucket (ContactAgeBuckets) at now.
+	Backlog(now time.Time) ([]ContactBacklog, error)
 }
 
 type contactRepository struct {
@@ -35,27 +38,11 @@ func (r *contactRepository) CreateContact(email, subject, message string) error
 	}).Error
 }
 
-func (r *contactRepository) GetAllContacts(page, limit int, status string) ([]models.Contact, int64, error) {
-	var (
-		contacts []models.Contact
-		total    int64
-	)
-
-	_, limit, offset := utils.NormalizePageLimit(page, limit)
-
-	query := r.db.Model(&models.Contact{})
-	
-	// Apply status filter if provided
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&contacts).Error
-	return contacts, total, err
+func (r *contactRepository) GetAllContacts(q utils.ListQuery) ([]models.Contact, utils.PageInfo, error) {
+	query := ContactListSpec.Apply(r.db.Model(&models.Contact{}), q)
+	return findPage(query, ContactListSpec, q, func(c models.Contact) utils.Cursor {
+		return utils.Cursor{CreatedAt: time.Unix(c.CreatedAt, 0), ID: c.ID}
+	})
 }
 
 func (r *contactRepository) GetContactByID(id uint) (*models.Contact, error) {
@@ -77,7 +64,26 @@ func (r *contactRepository) UpdateContact(id uint, email, subject, message strin
 }
 
 func (r *contactRepository) DeleteContact(id uint) error {
-	return r.db.Delete(&models.Contact{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteNotes(tx, models.NoteSubjectContact, id); err != nil {
+			return err
+		}
+		return tx.Delete(&models.Contact{}, id).Error
+	})
+}
+
+func (r *contactRepository) Backlog(now time.Time) ([]ContactBacklog, error) {
+	day := int64(24 * time.Hour / time.Second)
+	bucket := "CASE WHEN created_at >= ? THEN 'lt_1d' WHEN created_at >= ? THEN '1d_3d' " +
+		"WHEN created_at >= ? THEN '3d_7d' ELSE 'gt_7d' END"
+
+	var rows []ContactBacklog
+	err := r.db.Model(&models.Contact{}).
+		Select("status, "+bucket+" AS bucket, COUNT(*) AS count",
+			now.Unix()-day, now.Unix()-3*day, now.Unix()-7*day).
+		Group("status, bucket").
+		Scan(&rows).Error
+	return rows, err
 }
 
 func (r *contactRepository) UpdateContactStatus(id uint, status models.ContactStatus) error {
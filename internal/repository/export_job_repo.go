@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ExportJobRepo interface {
+	Create(job *models.ExportJob) error
+	GetByID(id uint) (models.ExportJob, error)
+	Update(job models.ExportJob) error
+}
+
+type exportJobRepo struct {
+	db *gorm.DB
+}
+
+func NewExportJobRepo(db *gorm.DB) ExportJobRepo {
+	return &exportJobRepo{db: db}
+}
+
+func (r *exportJobRepo) Create(job *models.ExportJob) error {
+	if job.Status == "" {
+		job.Status = models.ExportJobPending
+	}
+	return r.db.Create(job).Error
+}
+
+func (r *exportJobRepo) GetByID(id uint) (models.ExportJob, error) {
+	var job models.ExportJob
+	err := r.db.First(&job, id).Error
+	return job, err
+}
+
+func (r *exportJobRepo) Update(job models.ExportJob) error {
+	return r.db.Save(&job).Error
+}
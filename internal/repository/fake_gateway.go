@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"darulabror/internal/models"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// FakeGateway is a local provider for development and tests: the "payment page" is an API
+// route that sends a signed webhook to ourselves (see PaymentService.SimulateFakePayment).
+type FakeGateway interface {
+	PaymentGateway
+	// Notify builds a signed webhook body and header for an invoice.
+	Notify(invoiceNumber string, status models.InvoiceStatus, amount int64) (http.Header, []byte, error)
+}
+
+type fakeGateway struct {
+	baseURL string
+	key     []byte
+}
+
+// NewFakeGateway signs webhooks with HMAC-SHA256(key, body) in the X-Fake-Signature header.
+func NewFakeGateway(baseURL string, key []byte) FakeGateway {
+	return &fakeGateway{baseURL: strings.TrimRight(baseURL, "/"), key: key}
+}
+
+func (g *fakeGateway) Name() string { return "fake" }
+
+func (g *fakeGateway) CreateCharge(_ context.Context, req ChargeRequest) (Charge, error) {
+	return Charge{
+		ExternalID: "fake-" + req.InvoiceNumber,
+		PaymentURL: g.baseURL + "/v1/payments/fake/" + req.InvoiceNumber,
+	}, nil
+}
+
+type fakeNotification struct {
+	InvoiceNumber string               `json:"invoice_number"`
+	Status        models.InvoiceStatus `json:"status"`
+	Amount        int64                `json:"amount"`
+}
+
+func (g *fakeGateway) Notify(invoiceNumber string, status models.InvoiceStatus, amount int64) (http.Header, []byte, error) {
+	body, err := json.Marshal(fakeNotification{InvoiceNumber: invoiceNumber, Status: status, Amount: amount})
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("X-Fake-Signature", g.sign(body))
+	return header, body, nil
+}
+
+func (g *fakeGateway) ParseWebhook(header http.Header, body []byte) (PaymentNotification, error) {
+	if !hmac.Equal([]byte(header.Get("X-Fake-Signature")), []byte(g.sign(body))) {
+		return PaymentNotification{}, ErrInvalidWebhook
+	}
+
+	var n fakeNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return PaymentNotification{}, ErrInvalidWebhook
+	}
+	return PaymentNotification{
+		EventID:       "fake-" + n.InvoiceNumber + ":" + string(n.Status),
+		InvoiceNumber: n.InvoiceNumber,
+		ExternalID:    "fake-" + n.InvoiceNumber,
+		Status:        n.Status,
+		Amount:        n.Amount,
+		Payload:       body,
+	}, nil
+}
+
+func (g *fakeGateway) sign(body []byte) string {
+	m := hmac.New(sha256.New, g.key)
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
@@ -4,26 +4,20 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/sirupsen/logrus"
 )
 
-var ErrStorageNotConfigured = errors.New("gcs storage is not configured")
-
-type GCPStorageRepo interface {
-	UploadFile(ctx context.Context, file io.Reader, objectName string) (string, error)
-	GenerateSignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error)
-}
-
 type gcpStorageRepo struct {
 	client     *storage.Client
 	bucketName string
 	isPublic   bool
 }
 
-func NewGCPStorageRepo(client *storage.Client, bucketName string, isPublic bool) GCPStorageRepo {
+func NewGCPStorageRepo(client *storage.Client, bucketName string, isPublic bool) ObjectStore {
 	return &gcpStorageRepo{
 		client:     client,
 		bucketName: bucketName,
@@ -114,6 +108,67 @@ func (r *gcpStorageRepo) GenerateSignedURL(ctx context.Context, objectName strin
 	return url, nil
 }
 
+// GenerateUploadURL — signed PUT, or signed POST that starts a resumable session
+// (client sends the returned headers, then PUTs chunks to the Location it gets back)
+func (r *gcpStorageRepo) GenerateUploadURL(ctx context.Context, objectName, contentType string, resumable bool, expire time.Duration) (UploadTarget, error) {
+	_ = ctx
+
+	if err := r.validate(); err != nil {
+		return UploadTarget{}, err
+	}
+
+	opts := &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      http.MethodPut,
+		ContentType: contentType,
+		Expires:     time.Now().Add(expire),
+	}
+	headers := map[string]string{"Content-Type": contentType}
+	if resumable {
+		opts.Method = http.MethodPost
+		opts.Headers = []string{"x-goog-resumable:start"}
+		headers["x-goog-resumable"] = "start"
+	}
+
+	url, err := r.client.Bucket(r.bucketName).SignedURL(objectName, opts)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"bucket": r.bucketName,
+			"object": objectName,
+		}).Error("failed generating signed upload url")
+		return UploadTarget{}, err
+	}
+
+	return UploadTarget{URL: url, Method: opts.Method, Headers: headers}, nil
+}
+
+func (r *gcpStorageRepo) StatObject(ctx context.Context, objectName string) (ObjectInfo, error) {
+	if err := r.validate(); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	attrs, err := r.client.Bucket(r.bucketName).Object(objectName).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: attrs.Size, ContentType: attrs.ContentType}, nil
+}
+
+func (r *gcpStorageRepo) DeleteObject(ctx context.Context, objectName string) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+
+	err := r.client.Bucket(r.bucketName).Object(objectName).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
 // NOTE:
 // - Kalau bucket public: GenerateSignedURL() cuma return public URL (signed URL tidak diperlukan).
 // - Mode private belum dipakai sekarang, tapi disiapkan untuk kebutuhan future (restricted media).
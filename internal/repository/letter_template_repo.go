@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type LetterTemplateRepo interface {
+	// Get returns gorm.ErrRecordNotFound while the built-in template is in use.
+	Get(kind models.LetterKind) (models.LetterTemplate, error)
+	Save(tpl models.LetterTemplate) error
+}
+
+type letterTemplateRepo struct {
+	db *gorm.DB
+}
+
+func NewLetterTemplateRepo(db *gorm.DB) LetterTemplateRepo {
+	return &letterTemplateRepo{db: db}
+}
+
+func (r *letterTemplateRepo) Get(kind models.LetterKind) (models.LetterTemplate, error) {
+	var tpl models.LetterTemplate
+	err := r.db.Where("kind = ?", kind).First(&tpl).Error
+	return tpl, err
+}
+
+func (r *letterTemplateRepo) Save(tpl models.LetterTemplate) error {
+	return r.db.Save(&tpl).Error
+}
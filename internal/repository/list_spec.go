@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"darulabror/internal/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListSpec describes how a utils.ListQuery maps onto one table: which columns the
+// free-text search covers and which sort keys are allowed.
+type ListSpec struct {
+	searchColumns  []string
+	sortColumns    []string // accepted ?sort= values, equal to the column names
+	defaultSort    string
+	statusColumn   string
+	assigneeColumn string // set with withAssignee for lists filtered by ?assignee=
+	unixTime       bool   // created_at is stored as unix seconds instead of timestamptz
+}
+
+func newListSpec(searchColumns, sortColumns []string, defaultSort, statusColumn string, unixTime bool) ListSpec {
+	return ListSpec{
+		searchColumns: searchColumns,
+		sortColumns:   sortColumns,
+		defaultSort:   defaultSort,
+		statusColumn:  statusColumn,
+		unixTime:      unixTime,
+	}
+}
+
+// withAssignee enables the assigned-admin filter on column.
+func (s ListSpec) withAssignee(column string) ListSpec {
+	s.assigneeColumn = column
+	return s
+}
+
+var (
+	RegistrationListSpec = newListSpec([]string{"full_name", "email", "nisn", "origin_school", "tracking_code"},
+		[]string{"id", "created_at", "full_name", "status"}, "id", "status", false).withAssignee("assigned_to")
+	ContactListSpec = newListSpec([]string{"email", "subject"},
+		[]string{"id", "created_at", "email", "status"}, "id", "status", true).withAssignee("assigned_to")
+	ArticleListSpec = newListSpec([]string{"title", "author"},
+		[]string{"id", "created_at", "updated_at", "title", "status"}, "id", "status", true)
+	AdminListSpec = newListSpec([]string{"username", "email"},
+		[]string{"id", "created_at", "username", "email"}, "id", "", true)
+)
+
+// SortFields lists the accepted values of ?sort=.
+func (s ListSpec) SortFields() []string {
+	return s.sortColumns
+}
+
+// Apply adds the WHERE clauses (search, status, assignee, created_at range).
+func (s ListSpec) Apply(query *gorm.DB, q utils.ListQuery) *gorm.DB {
+	if q.Search != "" && len(s.searchColumns) > 0 {
+		pattern := "%" + escapeLike(q.Search) + "%"
+		conds := make([]string, 0, len(s.searchColumns))
+		args := make([]interface{}, 0, len(s.searchColumns))
+		for _, col := range s.searchColumns {
+			conds = append(conds, col+" ILIKE ?")
+			args = append(args, pattern)
+		}
+		query = query.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+	if len(q.Statuses) > 0 && s.statusColumn != "" {
+		query = query.Where(s.statusColumn+" IN ?", q.Statuses)
+	}
+	if q.Assignee != nil && s.assigneeColumn != "" {
+		if *q.Assignee == 0 {
+			query = query.Where(s.assigneeColumn + " IS NULL")
+		} else {
+			query = query.Where(s.assigneeColumn+" = ?", *q.Assignee)
+		}
+	}
+	if q.From != nil {
+		query = query.Where("created_at >= ?", s.timeArg(*q.From))
+	}
+	if q.To != nil {
+		query = query.Where("created_at < ?", s.timeArg(*q.To))
+	}
+	return query
+}
+
+// Order returns the ORDER BY clause; id breaks ties so pages are stable.
+func (s ListSpec) Order(q utils.ListQuery) string {
+	col := s.defaultSort
+	for _, c := range s.sortColumns {
+		if c == q.Sort {
+			col = c
+		}
+	}
+	dir := " ASC"
+	if q.Desc {
+		dir = " DESC"
+	}
+	if col == "id" {
+		return "id" + dir
+	}
+	return col + dir + ", id" + dir
+}
+
+func (s ListSpec) timeArg(t time.Time) interface{} {
+	if s.unixTime {
+		return t.Unix()
+	}
+	return t
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// findPage runs query in OFFSET mode (page/limit, always counted) or keyset mode
+// (cursor on created_at, id; one extra row is fetched to detect the last page).
+// key returns the cursor position of an item.
+func findPage[T any](query *gorm.DB, spec ListSpec, q utils.ListQuery, key func(T) utils.Cursor) ([]T, utils.PageInfo, error) {
+	var (
+		items []T
+		info  utils.PageInfo
+	)
+
+	_, limit, offset := utils.NormalizePageLimit(q.Page, q.Limit)
+
+	if !q.CursorMode || q.WithTotal {
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			return nil, info, err
+		}
+		info.Total = &total
+	}
+
+	if !q.CursorMode {
+		err := query.Order(spec.Order(q)).Limit(limit).Offset(offset).Find(&items).Error
+		return items, info, err
+	}
+
+	dir, cmp := " DESC", "<"
+	if !q.Desc {
+		dir, cmp = " ASC", ">"
+	}
+	if q.Cursor != nil {
+		query = query.Where("(created_at, id) "+cmp+" (?, ?)", spec.timeArg(q.Cursor.CreatedAt), q.Cursor.ID)
+	}
+	if err := query.Order("created_at" + dir + ", id" + dir).Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, info, err
+	}
+	if len(items) > limit {
+		items = items[:limit]
+		info.NextCursor = utils.EncodeCursor(key(items[limit-1]))
+	}
+	return items, info, nil
+}
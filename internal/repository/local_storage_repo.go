@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidObjectName = errors.New("invalid object name")
+	ErrInvalidSignature  = errors.New("invalid or expired signature")
+)
+
+// LocalStorageRepo stores objects on local disk. Files are served by an Echo static route
+// mounted at RoutePrefix(); private objects require an HMAC-signed URL.
+type LocalStorageRepo interface {
+	ObjectStore
+	BaseDir() string
+	RoutePrefix() string
+	IsPublic() bool
+	VerifySignature(method, objectName, expires, signature string) error
+}
+
+type localStorageRepo struct {
+	baseDir    string
+	baseURL    string
+	signingKey []byte
+	isPublic   bool
+}
+
+// NewLocalStorageRepo — baseURL is the absolute URL the static route is reachable at,
+// e.g. http://localhost:8080/media/public
+func NewLocalStorageRepo(baseDir, baseURL string, signingKey []byte, isPublic bool) LocalStorageRepo {
+	return &localStorageRepo{
+		baseDir:    baseDir,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: signingKey,
+		isPublic:   isPublic,
+	}
+}
+
+func (r *localStorageRepo) BaseDir() string { return r.baseDir }
+
+func (r *localStorageRepo) IsPublic() bool { return r.isPublic }
+
+func (r *localStorageRepo) RoutePrefix() string {
+	u, err := url.Parse(r.baseURL)
+	if err != nil || u.Path == "" {
+		return "/media"
+	}
+	return u.Path
+}
+
+func (r *localStorageRepo) validate() error {
+	if r.baseDir == "" || r.baseURL == "" || len(r.signingKey) == 0 {
+		return ErrStorageNotConfigured
+	}
+	return nil
+}
+
+// objectPath resolves objectName inside baseDir and rejects path traversal.
+func (r *localStorageRepo) objectPath(objectName string) (string, error) {
+	clean := path.Clean("/" + objectName)
+	if clean == "/" || strings.Contains(objectName, "..") {
+		return "", ErrInvalidObjectName
+	}
+	return filepath.Join(r.baseDir, filepath.FromSlash(clean)), nil
+}
+
+// UploadFile — write file to disk (temp file + rename so readers never see partial files)
+func (r *localStorageRepo) UploadFile(ctx context.Context, file io.Reader, objectName string) (string, error) {
+	if err := r.validate(); err != nil {
+		return "", err
+	}
+
+	dst, err := r.objectPath(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, file); err != nil {
+		logrus.WithError(err).WithField("object", objectName).Error("local upload failed")
+		_ = tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", err
+	}
+
+	if r.isPublic {
+		url := r.baseURL + "/" + strings.TrimPrefix(objectName, "/")
+		logrus.WithFields(logrus.Fields{
+			"object": objectName,
+			"url":    url,
+		}).Info("public file uploaded to local storage")
+		return url, nil
+	}
+
+	logrus.WithField("object", objectName).Info("private file uploaded to local storage")
+	return objectName, nil
+}
+
+// GenerateSignedURL — public store returns plain URL, private store returns HMAC-signed URL
+func (r *localStorageRepo) GenerateSignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error) {
+	_ = ctx
+
+	if err := r.validate(); err != nil {
+		return "", err
+	}
+
+	objectName = strings.TrimPrefix(objectName, "/")
+	if r.isPublic {
+		return r.baseURL + "/" + objectName, nil
+	}
+	return r.signedURL(http.MethodGet, objectName, expire), nil
+}
+
+func (r *localStorageRepo) signedURL(method, objectName string, expire time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(expire).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", r.sign(method, objectName, expires))
+	return r.baseURL + "/" + objectName + "?" + q.Encode()
+}
+
+func (r *localStorageRepo) sign(method, objectName, expires string) string {
+	mac := hmac.New(sha256.New, r.signingKey)
+	mac.Write([]byte(method + "\n" + strings.TrimPrefix(objectName, "/") + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a signature produced by GenerateSignedURL.
+func (r *localStorageRepo) VerifySignature(method, objectName, expires, signature string) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidSignature
+	}
+	expected := r.sign(method, objectName, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// GenerateUploadURL — HMAC-signed PUT to the local static route (see routes.RegisterLocalStorage)
+func (r *localStorageRepo) GenerateUploadURL(ctx context.Context, objectName, contentType string, resumable bool, expire time.Duration) (UploadTarget, error) {
+	_ = ctx
+	_ = resumable
+
+	if err := r.validate(); err != nil {
+		return UploadTarget{}, err
+	}
+	if _, err := r.objectPath(objectName); err != nil {
+		return UploadTarget{}, err
+	}
+
+	return UploadTarget{
+		URL:     r.signedURL(http.MethodPut, strings.TrimPrefix(objectName, "/"), expire),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+// videoBrands are the ftyp major brands of the allowed ISO base media videos (MP4, M4V,
+// QuickTime). The generic ISO brands are also written by QuickTime, so for those the .mov
+// extension decides.
+var videoBrands = map[string]string{
+	"qt  ": "video/quicktime",
+	"isom": "", "iso2": "", "iso4": "", "iso5": "", "iso6": "", "mp41": "", "mp42": "", "avc1": "",
+	"M4V ": "video/mp4", "M4VH": "video/mp4", "M4VP": "video/mp4", "dash": "video/mp4",
+}
+
+// sniffContentType is http.DetectContentType, except that ISO base media files are typed by
+// their major brand: the standard sniffer only knows MP4 and reports QuickTime as
+// application/octet-stream. Files with an unlisted brand are application/octet-stream.
+func sniffContentType(head []byte, name string) string {
+	if len(head) < 12 || string(head[4:8]) != "ftyp" {
+		return http.DetectContentType(head)
+	}
+	ct, ok := videoBrands[string(head[8:12])]
+	switch {
+	case !ok:
+		return "application/octet-stream"
+	case ct != "":
+		return ct
+	case strings.EqualFold(path.Ext(name), ".mov"):
+		return "video/quicktime"
+	default:
+		return "video/mp4"
+	}
+}
+
+// StatObject sniffs the content type from the stored bytes (local disk keeps no metadata).
+func (r *localStorageRepo) StatObject(ctx context.Context, objectName string) (ObjectInfo, error) {
+	_ = ctx
+
+	if err := r.validate(); err != nil {
+		return ObjectInfo{}, err
+	}
+	p, err := r.objectPath(objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+
+	return ObjectInfo{Size: st.Size(), ContentType: sniffContentType(head[:n], objectName)}, nil
+}
+
+func (r *localStorageRepo) DeleteObject(ctx context.Context, objectName string) error {
+	_ = ctx
+
+	if err := r.validate(); err != nil {
+		return err
+	}
+	p, err := r.objectPath(objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageRepoUploadPublic(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStorageRepo(dir, "http://localhost:8080/media/public/", []byte("secret"), true)
+
+	got, err := store.UploadFile(context.Background(), strings.NewReader("hello"), "articles/a.txt")
+	if err != nil {
+		t.Fatalf("UploadFile() error = %v", err)
+	}
+	if want := "http://localhost:8080/media/public/articles/a.txt"; got != want {
+		t.Errorf("UploadFile() = %q, want %q", got, want)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "articles", "a.txt"))
+	if err != nil || string(b) != "hello" {
+		t.Errorf("stored file = %q (err %v), want %q", b, err, "hello")
+	}
+	if prefix := store.RoutePrefix(); prefix != "/media/public" {
+		t.Errorf("RoutePrefix() = %q, want %q", prefix, "/media/public")
+	}
+}
+
+func TestLocalStorageRepoRejectsTraversal(t *testing.T) {
+	store := NewLocalStorageRepo(t.TempDir(), "http://localhost/media", []byte("secret"), true)
+
+	for _, name := range []string{"../escape.txt", "a/../../escape.txt", ""} {
+		if _, err := store.UploadFile(context.Background(), strings.NewReader("x"), name); err == nil {
+			t.Errorf("UploadFile(%q) expected error", name)
+		}
+	}
+}
+
+func TestLocalStorageRepoSignedURL(t *testing.T) {
+	store := NewLocalStorageRepo(t.TempDir(), "http://localhost/media/private", []byte("secret"), false)
+
+	got, err := store.UploadFile(context.Background(), strings.NewReader("x"), "proofs/r.jpg")
+	if err != nil {
+		t.Fatalf("UploadFile() error = %v", err)
+	}
+	if got != "proofs/r.jpg" {
+		t.Errorf("private UploadFile() = %q, want object name", got)
+	}
+
+	signed, err := store.GenerateSignedURL(context.Background(), "proofs/r.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateSignedURL() error = %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("invalid signed url %q: %v", signed, err)
+	}
+	exp, sig := u.Query().Get("expires"), u.Query().Get("signature")
+
+	tests := []struct {
+		name    string
+		method  string
+		object  string
+		expires string
+		sig     string
+		wantErr bool
+	}{
+		{"valid", http.MethodGet, "proofs/r.jpg", exp, sig, false},
+		{"other object", http.MethodGet, "proofs/other.jpg", exp, sig, true},
+		{"other method", http.MethodPut, "proofs/r.jpg", exp, sig, true},
+		{"tampered expiry", http.MethodGet, "proofs/r.jpg", "9999999999", sig, true},
+		{"expired", http.MethodGet, "proofs/r.jpg", "1", sig, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.VerifySignature(tt.method, tt.object, tt.expires, tt.sig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocalStorageRepoStatObject(t *testing.T) {
+	store := NewLocalStorageRepo(t.TempDir(), "http://localhost/media", []byte("secret"), true)
+
+	mov := append([]byte{0, 0, 0, 0x14}, []byte("ftypqt  \x00\x00\x02\x00qt  ")...)
+	tests := []struct {
+		name string
+		body []byte
+		want string
+	}{
+		{"quicktime", mov, "video/quicktime"},
+		{"png", []byte("\x89PNG\r\n\x1a\n0000"), "image/png"},
+		{"unknown", []byte{0, 1, 2, 3}, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := "articles/" + tt.name
+			if _, err := store.UploadFile(context.Background(), strings.NewReader(string(tt.body)), object); err != nil {
+				t.Fatalf("UploadFile() error = %v", err)
+			}
+			info, err := store.StatObject(context.Background(), object)
+			if err != nil {
+				t.Fatalf("StatObject() error = %v", err)
+			}
+			if info.ContentType != tt.want || info.Size != int64(len(tt.body)) {
+				t.Errorf("StatObject() = %+v, want %s of %d bytes", info, tt.want, len(tt.body))
+			}
+		})
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	ftyp := func(major string, compatible ...string) []byte {
+		b := []byte("\x00\x00\x00\x00ftyp" + major + "\x00\x00\x02\x00")
+		for _, c := range compatible {
+			b = append(b, c...)
+		}
+		b[3] = byte(len(b))
+		return b
+	}
+	tests := []struct {
+		name string
+		head []byte
+		file string
+		want string
+	}{
+		{"quicktime brand", ftyp("qt  ", "qt  "), "clip.mov", "video/quicktime"},
+		{"quicktime brand, other extension", ftyp("qt  "), "clip.mp4", "video/quicktime"},
+		{"isom mov", ftyp("isom", "isom", "mp41"), "clip.MOV", "video/quicktime"},
+		{"isom mp4", ftyp("isom", "isom", "iso2", "avc1", "mp41"), "clip.mp4", "video/mp4"},
+		{"mp42", ftyp("mp42", "mp42", "isom"), "clip", "video/mp4"},
+		{"m4v", ftyp("M4V ", "M4V ", "M4A ", "mp42", "isom"), "clip.m4v", "video/mp4"},
+		{"m4v mov", ftyp("M4V ", "qt  "), "clip.mov", "video/mp4"},
+		{"heic is not a video", ftyp("heic", "mif1", "heic"), "photo.mov", "application/octet-stream"},
+		{"3gp is not allowed", ftyp("3gp4", "isom", "mp42"), "clip.3gp", "application/octet-stream"},
+		{"png", []byte("\x89PNG\r\n\x1a\n0000"), "a.png", "image/png"},
+		{"short", []byte{0, 0, 0}, "a", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffContentType(tt.head, "articles/content/1_"+tt.file); got != tt.want {
+				t.Errorf("sniffContentType(%q, %q) = %s, want %s", tt.head[:min(len(tt.head), 12)], tt.file, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MailMessage is a plain-text email.
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends notifications to applicants.
+// Implementations: SMTP (NewSMTPMailer) and a logger for development (NewLogMailer).
+type Mailer interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer sends through host:port; STARTTLS is used when the server offers it.
+// Empty username means no authentication.
+func NewSMTPMailer(host string, port int, username, password, from string) Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpMailer{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg MailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.To)
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + m.from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return smtp.SendMail(m.addr, m.auth, smtpAddress(m.from), []string{msg.To}, []byte(b.String()))
+}
+
+// smtpAddress returns the address of `Name <addr>`.
+func smtpAddress(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+type logMailer struct{}
+
+// NewLogMailer only logs messages (SMTP_HOST not set).
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+func (logMailer) Send(_ context.Context, msg MailMessage) error {
+	logrus.WithFields(logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info("email (not sent, SMTP_HOST is empty)\n" + msg.Body)
+	return nil
+}
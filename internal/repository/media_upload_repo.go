@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MediaUploadRepo interface {
+	Create(upload *models.MediaUpload) error
+	GetByID(id uint) (models.MediaUpload, error)
+	MarkCompleted(id uint, url string, size int64, contentType string) error
+	Delete(id uint) error
+}
+
+type mediaUploadRepo struct {
+	db *gorm.DB
+}
+
+func NewMediaUploadRepo(db *gorm.DB) MediaUploadRepo {
+	return &mediaUploadRepo{db: db}
+}
+
+func (r *mediaUploadRepo) Create(upload *models.MediaUpload) error {
+	if upload.Status == "" {
+		upload.Status = models.MediaUploadPending
+	}
+	return r.db.Create(upload).Error
+}
+
+func (r *mediaUploadRepo) GetByID(id uint) (models.MediaUpload, error) {
+	var upload models.MediaUpload
+	err := r.db.First(&upload, id).Error
+	return upload, err
+}
+
+func (r *mediaUploadRepo) MarkCompleted(id uint, url string, size int64, contentType string) error {
+	result := r.db.Model(&models.MediaUpload{}).
+		Where("id = ? AND status = ?", id, models.MediaUploadPending).
+		Updates(map[string]interface{}{
+			"status":       models.MediaUploadCompleted,
+			"url":          url,
+			"size":         size,
+			"content_type": contentType,
+			"completed_at": time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *mediaUploadRepo) Delete(id uint) error {
+	return r.db.Delete(&models.MediaUpload{}, id).Error
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"darulabror/internal/models"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	midtransSnapSandbox    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransSnapProduction = "https://app.midtrans.com/snap/v1/transactions"
+)
+
+type midtransGateway struct {
+	client    *http.Client
+	serverKey string
+	snapURL   string
+}
+
+// NewMidtransGateway uses Snap (hosted payment page). An empty server key returns ErrPaymentNotConfigured.
+func NewMidtransGateway(client *http.Client, serverKey string, production bool) PaymentGateway {
+	snapURL := midtransSnapSandbox
+	if production {
+		snapURL = midtransSnapProduction
+	}
+	return &midtransGateway{client: client, serverKey: serverKey, snapURL: snapURL}
+}
+
+func (g *midtransGateway) Name() string { return "midtrans" }
+
+func (g *midtransGateway) CreateCharge(ctx context.Context, req ChargeRequest) (Charge, error) {
+	if g.serverKey == "" {
+		return Charge{}, ErrPaymentNotConfigured
+	}
+
+	minutes := int(math.Ceil(time.Until(req.ExpiresAt).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	body := map[string]interface{}{
+		"transaction_details": map[string]interface{}{
+			"order_id":     req.InvoiceNumber,
+			"gross_amount": req.Amount,
+		},
+		"item_details": []map[string]interface{}{{
+			"id":       "registration-fee",
+			"price":    req.Amount,
+			"quantity": 1,
+			"name":     req.Description,
+		}},
+		"customer_details": map[string]interface{}{
+			"first_name": req.CustomerName,
+			"email":      req.CustomerEmail,
+			"phone":      req.CustomerPhone,
+		},
+		"expiry": map[string]interface{}{
+			"duration": minutes,
+			"unit":     "minute",
+		},
+	}
+
+	var out struct {
+		Token       string `json:"token"`
+		RedirectURL string `json:"redirect_url"`
+	}
+	if err := postJSON(ctx, g.client, g.snapURL, g.serverKey, body, &out); err != nil {
+		return Charge{}, err
+	}
+	return Charge{ExternalID: out.Token, PaymentURL: out.RedirectURL}, nil
+}
+
+// ParseWebhook checks signature_key = SHA512(order_id + status_code + gross_amount + server_key).
+func (g *midtransGateway) ParseWebhook(_ http.Header, body []byte) (PaymentNotification, error) {
+	if g.serverKey == "" {
+		return PaymentNotification{}, ErrPaymentNotConfigured
+	}
+
+	var n struct {
+		OrderID           string `json:"order_id"`
+		StatusCode        string `json:"status_code"`
+		GrossAmount       string `json:"gross_amount"`
+		SignatureKey      string `json:"signature_key"`
+		TransactionID     string `json:"transaction_id"`
+		TransactionStatus string `json:"transaction_status"`
+		FraudStatus       string `json:"fraud_status"`
+	}
+	if err := json.Unmarshal(body, &n); err != nil {
+		return PaymentNotification{}, ErrInvalidWebhook
+	}
+
+	sum := sha512.Sum512([]byte(n.OrderID + n.StatusCode + n.GrossAmount + g.serverKey))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(n.SignatureKey)) != 1 {
+		return PaymentNotification{}, ErrInvalidWebhook
+	}
+
+	// gross_amount is sent as "250000.00"
+	amount, err := strconv.ParseFloat(n.GrossAmount, 64)
+	if err != nil {
+		return PaymentNotification{}, ErrInvalidWebhook
+	}
+
+	var status models.InvoiceStatus
+	switch n.TransactionStatus {
+	case "settlement":
+		status = models.InvoicePaid
+	case "capture":
+		if n.FraudStatus == "" || n.FraudStatus == "accept" {
+			status = models.InvoicePaid
+		}
+	case "expire":
+		status = models.InvoiceExpired
+	case "deny", "cancel", "failure":
+		status = models.InvoiceFailed
+	}
+
+	return PaymentNotification{
+		EventID:       n.TransactionID + ":" + n.TransactionStatus,
+		InvoiceNumber: n.OrderID,
+		ExternalID:    n.TransactionID,
+		Status:        status,
+		Amount:        int64(math.Round(amount)),
+		Payload:       body,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"darulabror/internal/utils"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrStorageNotConfigured = utils.NewDomainError(utils.CodeUnavailable, "object storage is not configured")
+	ErrObjectNotFound       = errors.New("object not found")
+)
+
+// UploadTarget describes how a client uploads directly to the bucket (bypassing the API).
+// Headers must be sent exactly as returned, otherwise the signature is rejected.
+type UploadTarget struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+}
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// ObjectStore is the storage backend used for media uploads.
+// Implementations: GCS (gcs_repo.go), local disk (local_storage_repo.go), S3/MinIO (s3_storage_repo.go).
+type ObjectStore interface {
+	// UploadFile returns the public URL (public store) or the object name (private store).
+	UploadFile(ctx context.Context, file io.Reader, objectName string) (string, error)
+	GenerateSignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error)
+
+	// Direct uploads (signed PUT, or resumable session where the backend supports it)
+	GenerateUploadURL(ctx context.Context, objectName, contentType string, resumable bool, expire time.Duration) (UploadTarget, error)
+	StatObject(ctx context.Context, objectName string) (ObjectInfo, error)
+	DeleteObject(ctx context.Context, objectName string) error
+}
+
+// GCPStorageRepo is kept so existing call sites keep compiling; new code should use ObjectStore.
+type GCPStorageRepo = ObjectStore
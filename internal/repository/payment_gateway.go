@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"darulabror/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrPaymentNotConfigured = errors.New("payment gateway is not configured")
+	ErrInvalidWebhook       = errors.New("invalid webhook signature")
+)
+
+// ChargeRequest is what a gateway needs to open a payment page for an invoice.
+type ChargeRequest struct {
+	InvoiceNumber string
+	Amount        int64 // rupiah
+	Description   string
+	CustomerName  string
+	CustomerEmail string
+	CustomerPhone string
+	ExpiresAt     time.Time
+}
+
+// Charge is the gateway side of an invoice.
+type Charge struct {
+	ExternalID string
+	PaymentURL string
+}
+
+// PaymentNotification is a verified webhook, normalized across gateways.
+type PaymentNotification struct {
+	EventID       string // unique per provider; the same delivery retried has the same id
+	InvoiceNumber string
+	ExternalID    string
+	Status        models.InvoiceStatus // "" when the event does not change the invoice (e.g. still pending)
+	Amount        int64
+	Payload       []byte
+}
+
+// PaymentGateway creates charges and verifies webhooks of one provider.
+// Implementations: Midtrans Snap (midtrans_gateway.go), Xendit invoices (xendit_gateway.go),
+// fake (fake_gateway.go, local development).
+type PaymentGateway interface {
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (Charge, error)
+	// ParseWebhook verifies the notification and returns ErrInvalidWebhook when it is not authentic.
+	ParseWebhook(header http.Header, body []byte) (PaymentNotification, error)
+}
+
+// postJSON sends a JSON request with basic auth (user = API key, empty password) and decodes the reply.
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(apiKey, "")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("payment gateway returned %d: %s", res.StatusCode, bytes.TrimSpace(data))
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha512"
+	"darulabror/internal/models"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func midtransNotification(serverKey, status, signature string) []byte {
+	const orderID, statusCode, gross = "PSB-000042-01", "200", "250000.00"
+	if signature == "" {
+		sum := sha512.Sum512([]byte(orderID + statusCode + gross + serverKey))
+		signature = hex.EncodeToString(sum[:])
+	}
+	body, _ := json.Marshal(map[string]string{
+		"order_id":           orderID,
+		"status_code":        statusCode,
+		"gross_amount":       gross,
+		"signature_key":      signature,
+		"transaction_id":     "trx-1",
+		"transaction_status": status,
+	})
+	return body
+}
+
+func TestMidtransParseWebhook(t *testing.T) {
+	g := NewMidtransGateway(http.DefaultClient, "server-key", false)
+
+	n, err := g.ParseWebhook(nil, midtransNotification("server-key", "settlement", ""))
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if n.Status != models.InvoicePaid || n.Amount != 250000 || n.InvoiceNumber != "PSB-000042-01" || n.EventID != "trx-1:settlement" {
+		t.Errorf("ParseWebhook() = %+v", n)
+	}
+
+	if n, _ := g.ParseWebhook(nil, midtransNotification("server-key", "pending", "")); n.Status != "" {
+		t.Errorf("pending status = %q, want none", n.Status)
+	}
+	if _, err := g.ParseWebhook(nil, midtransNotification("other-key", "settlement", "")); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("wrong key: error = %v, want %v", err, ErrInvalidWebhook)
+	}
+}
+
+func TestMidtransCreateCharge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "server-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			TransactionDetails struct {
+				OrderID     string `json:"order_id"`
+				GrossAmount int64  `json:"gross_amount"`
+			} `json:"transaction_details"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.TransactionDetails.OrderID != "PSB-000042-01" || body.TransactionDetails.GrossAmount != 250000 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"tok-1","redirect_url":"https://pay.example/tok-1"}`))
+	}))
+	defer srv.Close()
+
+	g := NewMidtransGateway(srv.Client(), "server-key", false).(*midtransGateway)
+	g.snapURL = srv.URL
+
+	charge, err := g.CreateCharge(context.Background(), ChargeRequest{
+		InvoiceNumber: "PSB-000042-01",
+		Amount:        250000,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge() error = %v", err)
+	}
+	if charge.ExternalID != "tok-1" || charge.PaymentURL != "https://pay.example/tok-1" {
+		t.Errorf("CreateCharge() = %+v", charge)
+	}
+}
+
+func TestXenditParseWebhook(t *testing.T) {
+	g := NewXenditGateway(http.DefaultClient, "secret", "callback-token")
+	body := []byte(`{"id":"inv-1","external_id":"PSB-000042-01","status":"PAID","amount":250000,"paid_amount":250000}`)
+
+	header := http.Header{}
+	header.Set("X-Callback-Token", "callback-token")
+	n, err := g.ParseWebhook(header, body)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if n.Status != models.InvoicePaid || n.Amount != 250000 || n.InvoiceNumber != "PSB-000042-01" {
+		t.Errorf("ParseWebhook() = %+v", n)
+	}
+
+	header.Set("X-Callback-Token", "wrong")
+	if _, err := g.ParseWebhook(header, body); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("wrong token: error = %v, want %v", err, ErrInvalidWebhook)
+	}
+}
+
+func TestFakeGatewayNotify(t *testing.T) {
+	g := NewFakeGateway("http://localhost:8080", []byte("key"))
+
+	header, body, err := g.Notify("PSB-000042-01", models.InvoicePaid, 250000)
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	n, err := g.ParseWebhook(header, body)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if n.Status != models.InvoicePaid || n.Amount != 250000 {
+		t.Errorf("ParseWebhook() = %+v", n)
+	}
+
+	if _, err := g.ParseWebhook(http.Header{}, body); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("unsigned: error = %v, want %v", err, ErrInvalidWebhook)
+	}
+}
@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"darulabror/internal/utils"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrProofReviewed = errors.New("payment proof was already reviewed")
+	ErrAlreadyPaid   = errors.New("registration fee is already paid")
+)
+
+var PaymentProofListSpec = newListSpec(nil, []string{"id", "created_at"}, "id", "status", true)
+
+// PendingProof is a queue item: the proof with its registration.
+type PendingProof struct {
+	Proof        models.PaymentProof
+	Registration models.Registration
+}
+
+// ProofDecision tells what approving a proof changed.
+type ProofDecision struct {
+	Proof    models.PaymentProof
+	Invoice  models.Invoice
+	Advanced bool // the registration moved from validate to process
+}
+
+// ReconcileResult tells what a webhook changed.
+type ReconcileResult struct {
+	Invoice   models.Invoice
+	Duplicate bool // the event was already applied
+	Advanced  bool // the registration moved from validate to process
+	// DoublePaid: the registration was already paid by another invoice (e.g. an approved
+	// transfer proof), so this payment was recorded but not settled and needs a refund.
+	DoublePaid bool
+}
+
+type PaymentRepo interface {
+	CreateInvoice(inv *models.Invoice) error
+	// ExpireInvoice marks a pending invoice expired (a webhook may have paid it meanwhile).
+	ExpireInvoice(id uint) error
+	GetInvoiceByNumber(number string) (models.Invoice, error)
+	// GetLatestInvoice returns gorm.ErrRecordNotFound when the registration has no invoice.
+	GetLatestInvoice(registrationID uint) (models.Invoice, error)
+	GetInvoices(registrationID uint) ([]models.Invoice, error)
+	CountInvoices(registrationID uint) (int64, error)
+	// AttachCharge stores the gateway charge of an invoice inserted before calling the gateway.
+	AttachCharge(id uint, externalID, paymentURL string) error
+	// WithRegistrationLock runs fn in one transaction holding the registration's row lock, with
+	// a PaymentRepo bound to that transaction, so invoices of one registration are opened one
+	// at a time.
+	WithRegistrationLock(registrationID uint, fn func(repo PaymentRepo) error) error
+
+	// Reconcile records the event and applies it in one transaction; an event seen before is
+	// not applied again. A paid invoice never changes, and an invoice is not settled when the
+	// registration already has a paid one. When the invoice becomes paid and the registration
+	// is in validate, it moves to process.
+	Reconcile(event models.PaymentEvent) (ReconcileResult, error)
+
+	// Transfer proofs
+	CreateProof(proof *models.PaymentProof) error
+	GetProof(id uint) (models.PaymentProof, error)
+	// GetLatestProof returns gorm.ErrRecordNotFound when the registration has no proof.
+	GetLatestProof(registrationID uint) (models.PaymentProof, error)
+	// GetProofs lists proofs of q.Statuses (all when empty) with their registrations.
+	GetProofs(q utils.ListQuery) ([]PendingProof, utils.PageInfo, error)
+	// ApproveProof marks a pending proof approved and stores inv as its paid invoice, expires the
+	// registration's pending gateway invoices, then moves a registration in validate to process.
+	// ErrAlreadyPaid when another invoice is paid. Run it inside WithRegistrationLock, which
+	// also covers numbering inv.
+	ApproveProof(id, adminID uint, inv models.Invoice) (ProofDecision, error)
+	RejectProof(id, adminID uint, reason string) (models.PaymentProof, error)
+}
+
+type paymentRepo struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepo(db *gorm.DB) PaymentRepo {
+	return &paymentRepo{db: db}
+}
+
+func (r *paymentRepo) CreateInvoice(inv *models.Invoice) error {
+	return r.db.Create(inv).Error
+}
+
+func (r *paymentRepo) ExpireInvoice(id uint) error {
+	return r.db.Model(&models.Invoice{}).
+		Where("id = ? AND status = ?", id, models.InvoicePending).
+		Update("status", models.InvoiceExpired).Error
+}
+
+func (r *paymentRepo) GetInvoiceByNumber(number string) (models.Invoice, error) {
+	var inv models.Invoice
+	err := r.db.Where("number = ?", number).First(&inv).Error
+	return inv, err
+}
+
+func (r *paymentRepo) GetLatestInvoice(registrationID uint) (models.Invoice, error) {
+	var inv models.Invoice
+	err := r.db.Where("registration_id = ?", registrationID).Order("id DESC").First(&inv).Error
+	return inv, err
+}
+
+func (r *paymentRepo) GetInvoices(registrationID uint) ([]models.Invoice, error) {
+	var invs []models.Invoice
+	err := r.db.Where("registration_id = ?", registrationID).Order("id DESC").Find(&invs).Error
+	return invs, err
+}
+
+func (r *paymentRepo) CountInvoices(registrationID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.Invoice{}).Where("registration_id = ?", registrationID).Count(&n).Error
+	return n, err
+}
+
+func (r *paymentRepo) AttachCharge(id uint, externalID, paymentURL string) error {
+	return r.db.Model(&models.Invoice{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"external_id": externalID,
+		"payment_url": paymentURL,
+	}).Error
+}
+
+func (r *paymentRepo) WithRegistrationLock(registrationID uint, fn func(repo PaymentRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, _, err := lockRegistration(tx, registrationID); err != nil {
+			return err
+		}
+		return fn(&paymentRepo{db: tx})
+	})
+}
+
+func (r *paymentRepo) Reconcile(event models.PaymentEvent) (ReconcileResult, error) {
+	var res ReconcileResult
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ins := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).Create(&event)
+		if ins.Error != nil {
+			return ins.Error
+		}
+
+		// registration before invoice, the lock order of WithRegistrationLock
+		if err := tx.First(&res.Invoice, event.InvoiceID).Error; err != nil {
+			return err
+		}
+		if _, _, err := lockRegistration(tx, res.Invoice.RegistrationID); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&res.Invoice, event.InvoiceID).Error; err != nil {
+			return err
+		}
+		if ins.RowsAffected == 0 {
+			res.Duplicate = true
+			return nil
+		}
+		if res.Invoice.Status == models.InvoicePaid || res.Invoice.Status == event.Status {
+			return nil
+		}
+		if event.Status == models.InvoicePaid {
+			paid, err := countPaidInvoices(tx, res.Invoice.RegistrationID)
+			if err != nil {
+				return err
+			}
+			if paid > 0 {
+				res.DoublePaid = true
+				return nil
+			}
+		}
+
+		res.Invoice.Status = event.Status
+		updates := map[string]interface{}{"status": event.Status}
+		if event.Status == models.InvoicePaid {
+			now := time.Now().Unix()
+			res.Invoice.PaidAt = &now
+			updates["paid_at"] = now
+		}
+		if err := tx.Model(&models.Invoice{}).Where("id = ?", res.Invoice.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if event.Status != models.InvoicePaid {
+			return nil
+		}
+
+		var err error
+		res.Advanced, err = advanceOnPayment(tx, res.Invoice.RegistrationID, nil)
+		return err
+	})
+	return res, err
+}
+
+// advanceOnPayment moves a registration in validate to process once its fee is paid.
+// Both statuses hold a seat, so quotas are not affected.
+func advanceOnPayment(tx *gorm.DB, registrationID uint, changedBy *uint) (bool, error) {
+	reg, _, err := lockRegistration(tx, registrationID)
+	if err != nil {
+		return false, err
+	}
+	if reg.Status != models.RegistrationStatusValidate {
+		return false, nil
+	}
+	if err := tx.Model(&models.Registration{}).Where("id = ?", reg.ID).
+		Update("status", models.RegistrationStatusProcess).Error; err != nil {
+		return false, err
+	}
+	err = recordStatus(tx, reg.ID, models.RegistrationStatusValidate, models.RegistrationStatusProcess, models.StatusReasonPaid, changedBy)
+	return err == nil, err
+}
+
+func (r *paymentRepo) CreateProof(proof *models.PaymentProof) error {
+	return r.db.Create(proof).Error
+}
+
+func (r *paymentRepo) GetProof(id uint) (models.PaymentProof, error) {
+	var proof models.PaymentProof
+	err := r.db.First(&proof, id).Error
+	return proof, err
+}
+
+func (r *paymentRepo) GetLatestProof(registrationID uint) (models.PaymentProof, error) {
+	var proof models.PaymentProof
+	err := r.db.Where("registration_id = ?", registrationID).Order("id DESC").First(&proof).Error
+	return proof, err
+}
+
+func (r *paymentRepo) GetProofs(q utils.ListQuery) ([]PendingProof, utils.PageInfo, error) {
+	query := PaymentProofListSpec.Apply(r.db.Model(&models.PaymentProof{}), q)
+	proofs, page, err := findPage(query, PaymentProofListSpec, q, func(p models.PaymentProof) utils.Cursor {
+		return utils.Cursor{CreatedAt: time.Unix(p.CreatedAt, 0), ID: p.ID}
+	})
+	if err != nil || len(proofs) == 0 {
+		return nil, page, err
+	}
+
+	ids := make([]uint, 0, len(proofs))
+	for _, p := range proofs {
+		ids = append(ids, p.RegistrationID)
+	}
+	var regs []models.Registration
+	if err := r.db.Where("id IN ?", ids).Find(&regs).Error; err != nil {
+		return nil, page, err
+	}
+	byID := make(map[uint]models.Registration, len(regs))
+	for _, reg := range regs {
+		byID[reg.ID] = reg
+	}
+
+	out := make([]PendingProof, 0, len(proofs))
+	for _, p := range proofs {
+		out = append(out, PendingProof{Proof: p, Registration: byID[p.RegistrationID]})
+	}
+	return out, page, nil
+}
+
+func (r *paymentRepo) ApproveProof(id, adminID uint, inv models.Invoice) (ProofDecision, error) {
+	var res ProofDecision
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&res.Proof, id).Error; err != nil {
+			return err
+		}
+		if res.Proof.Status != models.PaymentProofPending {
+			return ErrProofReviewed
+		}
+
+		paid, err := countPaidInvoices(tx, res.Proof.RegistrationID)
+		if err != nil {
+			return err
+		}
+		if paid > 0 {
+			return ErrAlreadyPaid
+		}
+
+		// the applicant must not pay the open gateway invoice as well
+		if err := tx.Model(&models.Invoice{}).
+			Where("registration_id = ? AND status = ?", res.Proof.RegistrationID, models.InvoicePending).
+			Update("status", models.InvoiceExpired).Error; err != nil {
+			return err
+		}
+
+		now := time.Now().Unix()
+		inv.RegistrationID = res.Proof.RegistrationID
+		inv.Status = models.InvoicePaid
+		inv.PaidAt = &now
+		if err := tx.Create(&inv).Error; err != nil {
+			return err
+		}
+		res.Invoice = inv
+
+		res.Proof.Status = models.PaymentProofApproved
+		res.Proof.InvoiceID = &inv.ID
+		res.Proof.ReviewedBy = &adminID
+		res.Proof.ReviewedAt = &now
+		if err := tx.Model(&models.PaymentProof{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":      res.Proof.Status,
+			"invoice_id":  inv.ID,
+			"reviewed_by": adminID,
+			"reviewed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		res.Advanced, err = advanceOnPayment(tx, res.Proof.RegistrationID, &adminID)
+		return err
+	})
+	return res, err
+}
+
+func countPaidInvoices(tx *gorm.DB, registrationID uint) (int64, error) {
+	var n int64
+	err := tx.Model(&models.Invoice{}).
+		Where("registration_id = ? AND status = ?", registrationID, models.InvoicePaid).
+		Count(&n).Error
+	return n, err
+}
+
+func (r *paymentRepo) RejectProof(id, adminID uint, reason string) (models.PaymentProof, error) {
+	now := time.Now().Unix()
+	res := r.db.Model(&models.PaymentProof{}).
+		Where("id = ? AND status = ?", id, models.PaymentProofPending).
+		Updates(map[string]interface{}{
+			"status":        models.PaymentProofRejected,
+			"reject_reason": reason,
+			"reviewed_by":   adminID,
+			"reviewed_at":   now,
+		})
+	if res.Error != nil {
+		return models.PaymentProof{}, res.Error
+	}
+
+	proof, err := r.GetProof(id)
+	if err == nil && res.RowsAffected == 0 {
+		err = ErrProofReviewed
+	}
+	return proof, err
+}
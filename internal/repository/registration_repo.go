@@ -4,25 +4,79 @@ import (
 	"darulabror/internal/models"
 	"darulabror/internal/utils"
 	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// RegistrationFilter narrows bulk reads (export). Zero values mean "no filter".
+type RegistrationFilter struct {
+	AdmissionPeriodID uint
+	Status            string
+	StudentType       string
+	Gender            string
+	Program           string
+	From              *time.Time // created_at >= From
+	To                *time.Time // created_at < To
+}
+
+// ErrVersionConflict is returned when a registration was edited since it was read.
+var ErrVersionConflict = errors.New("registration version conflict")
+
+// ErrDuplicateEmail and ErrDuplicateNISN are returned when a write hits the per-period unique
+// index, i.e. another request took the email or NISN after the service checked it.
+var (
+	ErrDuplicateEmail = errors.New("registration email already used in the period")
+	ErrDuplicateNISN  = errors.New("registration nisn already used in the period")
+)
+
+// registrationUniqueError maps a unique violation (SQLSTATE 23505) of the per-period
+// email/NISN index to ErrDuplicateEmail or ErrDuplicateNISN.
+func registrationUniqueError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case "idx_registrations_period_email":
+		return ErrDuplicateEmail
+	case "idx_registrations_period_nisn":
+		return ErrDuplicateNISN
+	}
+	return err
+}
+
 type RegistrationRepo interface {
 	// Public Registration Management
 	Create(reg models.Registration) error
 	// Admin Registration Management
-	GetAll(page, limit int, status string) ([]models.Registration, int64, error)
+	GetAll(q utils.ListQuery) ([]models.Registration, utils.PageInfo, error)
 	GetByID(id uint) (models.Registration, error)
 	GetByEmail(email string) (models.Registration, error)
 	GetByNISN(nisn string) (models.Registration, error)
+	GetByTrackingCode(code string) (models.Registration, error)
 
-	Update(reg models.Registration) error
+	// Update saves the editable fields of reg when its version is still reg.Version and records
+	// changes. ErrVersionConflict when the registration was edited in the meantime.
+	Update(reg models.Registration, changes []models.RegistrationChange) error
 	UpdateStatus(id uint, status models.RegistrationStatus) error
 	Delete(id uint) error
-	// Existence Checks
-	ExistsByEmail(email string) (bool, error)
-	ExistsByNISN(nisn string) (bool, error)
+	// Existence Checks (email and NISN are unique per admission period)
+	ExistsByEmail(periodID uint, email string) (bool, error)
+	ExistsByNISN(periodID uint, nisn string) (bool, error)
+	// Status changes, oldest first
+	GetStatusHistory(id uint) ([]models.RegistrationStatusHistory, error)
+	// Field edits, newest first
+	GetChanges(id uint) ([]models.RegistrationChange, error)
+	// Aggregates (dashboard); only filter.AdmissionPeriodID is used by Breakdown and TopOriginSchools
+	Breakdown(filter RegistrationFilter) (RegistrationBreakdown, error)
+	TopOriginSchools(filter RegistrationFilter, limit int) ([]KeyCount, error)
+	// DailyCounts counts registrations per day in [from, to); days without registrations are absent.
+	DailyCounts(filter RegistrationFilter, from, to time.Time) ([]DailyCount, error)
+	// Bulk reads (export)
+	Count(filter RegistrationFilter) (int64, error)
+	FindInBatches(filter RegistrationFilter, batchSize int, fn func(batch []models.Registration) error) error
 }
 
 type registrationRepo struct {
@@ -41,27 +95,11 @@ func (r *registrationRepo) Create(reg models.Registration) error {
 	return r.db.Create(&reg).Error
 }
 
-func (r *registrationRepo) GetAll(page, limit int, status string) ([]models.Registration, int64, error) {
-	var (
-		regs  []models.Registration
-		total int64
-	)
-
-	_, limit, offset := utils.NormalizePageLimit(page, limit)
-
-	query := r.db.Model(&models.Registration{})
-	
-	// Apply status filter if provided
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&regs).Error
-	return regs, total, err
+func (r *registrationRepo) GetAll(q utils.ListQuery) ([]models.Registration, utils.PageInfo, error) {
+	query := RegistrationListSpec.Apply(r.db.Model(&models.Registration{}), q)
+	return findPage(query, RegistrationListSpec, q, func(reg models.Registration) utils.Cursor {
+		return utils.Cursor{CreatedAt: reg.CreatedAt, ID: reg.ID}
+	})
 }
 
 func (r *registrationRepo) GetByID(id uint) (models.Registration, error) {
@@ -82,12 +120,58 @@ func (r *registrationRepo) GetByNISN(nisn string) (models.Registration, error) {
 	return reg, err
 }
 
-func (r *registrationRepo) Update(reg models.Registration) error {
+func (r *registrationRepo) GetByTrackingCode(code string) (models.Registration, error) {
+	var reg models.Registration
+	err := r.db.Where("tracking_code = ?", code).First(&reg).Error
+	return reg, err
+}
+
+func (r *registrationRepo) Update(reg models.Registration, changes []models.RegistrationChange) error {
 	// Pastikan ID ada
 	if reg.ID == 0 {
 		return errors.New("registration id is required")
 	}
-	return r.db.Save(&reg).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return saveRegistration(tx, reg, changes)
+	})
+}
+
+// registrationEditableColumns are the columns an edit writes; status, period,
+// tracking code and created_at are never changed by an edit.
+var registrationEditableColumns = []string{
+	"student_type", "gender", "program", "email", "full_name", "phone",
+	"place_of_birth", "date_of_birth", "address", "origin_school", "nisn",
+	"father_name", "father_occupation", "phone_father", "date_of_birth_father",
+	"mother_name", "mother_occupation", "phone_mother", "date_of_birth_mother",
+}
+
+// saveRegistration writes the editable fields of reg if the row still has reg.Version,
+// increments the version and inserts changes.
+func saveRegistration(tx *gorm.DB, reg models.Registration, changes []models.RegistrationChange) error {
+	columns := append(append([]string{}, registrationEditableColumns...), "version")
+	expected := reg.Version
+	reg.Version++
+
+	res := tx.Model(&models.Registration{}).Where("id = ? AND version = ?", reg.ID, expected).
+		Select(columns).Updates(reg)
+	if res.Error != nil {
+		return registrationUniqueError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		var count int64
+		if err := tx.Model(&models.Registration{}).Where("id = ?", reg.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return ErrVersionConflict
+	}
+
+	if len(changes) > 0 {
+		return tx.Create(&changes).Error
+	}
+	return nil
 }
 
 func (r *registrationRepo) Delete(id uint) error {
@@ -105,14 +189,120 @@ func (r *registrationRepo) UpdateStatus(id uint, status models.RegistrationStatu
 	return nil
 }
 
-func (r *registrationRepo) ExistsByEmail(email string) (bool, error) {
+func (r *registrationRepo) ExistsByEmail(periodID uint, email string) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Registration{}).Where("email = ?", email).Count(&count).Error
+	err := r.db.Model(&models.Registration{}).Where("admission_period_id = ? AND email = ?", periodID, email).Count(&count).Error
 	return count > 0, err
 }
 
-func (r *registrationRepo) ExistsByNISN(nisn string) (bool, error) {
+func (r *registrationRepo) ExistsByNISN(periodID uint, nisn string) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Registration{}).Where("nisn = ?", nisn).Count(&count).Error
+	err := r.db.Model(&models.Registration{}).Where("admission_period_id = ? AND nisn = ?", periodID, nisn).Count(&count).Error
 	return count > 0, err
 }
+
+func (r *registrationRepo) GetStatusHistory(id uint) ([]models.RegistrationStatusHistory, error) {
+	var history []models.RegistrationStatusHistory
+	err := r.db.Where("registration_id = ?", id).Order("created_at ASC, id ASC").Find(&history).Error
+	return history, err
+}
+
+func (r *registrationRepo) GetChanges(id uint) ([]models.RegistrationChange, error) {
+	var changes []models.RegistrationChange
+	err := r.db.Where("registration_id = ?", id).Order("created_at DESC, id DESC").Find(&changes).Error
+	return changes, err
+}
+
+func (r *registrationRepo) Breakdown(filter RegistrationFilter) (RegistrationBreakdown, error) {
+	// one scan for all three groupings; the columns not grouped in a row are NULL
+	var rows []struct {
+		Status      *string
+		Gender      *string
+		StudentType *string
+		Count       int64
+	}
+	err := r.applyFilter(r.db.Model(&models.Registration{}), filter).
+		Select("status, gender, student_type, COUNT(*) AS count").
+		Group("GROUPING SETS ((status), (gender), (student_type))").
+		Scan(&rows).Error
+	if err != nil {
+		return RegistrationBreakdown{}, err
+	}
+
+	var out RegistrationBreakdown
+	for _, row := range rows {
+		switch {
+		case row.Status != nil:
+			out.ByStatus = append(out.ByStatus, KeyCount{Key: *row.Status, Count: row.Count})
+			out.Total += row.Count
+		case row.Gender != nil:
+			out.ByGender = append(out.ByGender, KeyCount{Key: *row.Gender, Count: row.Count})
+		case row.StudentType != nil:
+			out.ByStudentType = append(out.ByStudentType, KeyCount{Key: *row.StudentType, Count: row.Count})
+		}
+	}
+	return out, nil
+}
+
+func (r *registrationRepo) TopOriginSchools(filter RegistrationFilter, limit int) ([]KeyCount, error) {
+	var rows []KeyCount
+	err := r.applyFilter(r.db.Model(&models.Registration{}), filter).
+		Select("origin_school AS key, COUNT(*) AS count").
+		Group("origin_school").
+		Order("count DESC, origin_school ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *registrationRepo) DailyCounts(filter RegistrationFilter, from, to time.Time) ([]DailyCount, error) {
+	var rows []DailyCount
+	day := "to_char(created_at AT TIME ZONE '" + statsTimeZone + "', 'YYYY-MM-DD')"
+	err := r.applyFilter(r.db.Model(&models.Registration{}), filter).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Select(day + " AS day, COUNT(*) AS count").
+		Group("day").
+		Order("day ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *registrationRepo) applyFilter(query *gorm.DB, filter RegistrationFilter) *gorm.DB {
+	if filter.AdmissionPeriodID != 0 {
+		query = query.Where("admission_period_id = ?", filter.AdmissionPeriodID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.StudentType != "" {
+		query = query.Where("student_type = ?", filter.StudentType)
+	}
+	if filter.Gender != "" {
+		query = query.Where("gender = ?", filter.Gender)
+	}
+	if filter.Program != "" {
+		query = query.Where("program = ?", filter.Program)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+func (r *registrationRepo) Count(filter RegistrationFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(r.db.Model(&models.Registration{}), filter).Count(&count).Error
+	return count, err
+}
+
+// FindInBatches streams matching registrations ordered by id, batchSize rows at a time.
+func (r *registrationRepo) FindInBatches(filter RegistrationFilter, batchSize int, fn func(batch []models.Registration) error) error {
+	var batch []models.Registration
+	return r.applyFilter(r.db.Model(&models.Registration{}), filter).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRegistrationUniqueError(t *testing.T) {
+	other := errors.New("connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"email", &pgconn.PgError{Code: "23505", ConstraintName: "idx_registrations_period_email"}, ErrDuplicateEmail},
+		{"wrapped nisn", fmt.Errorf("update: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_registrations_period_nisn"}), ErrDuplicateNISN},
+		{"other index", &pgconn.PgError{Code: "23505", ConstraintName: "registrations_pkey"}, nil},
+		{"other code", &pgconn.PgError{Code: "23503", ConstraintName: "idx_registrations_period_email"}, nil},
+		{"not postgres", other, other},
+	}
+	for _, tt := range tests {
+		got := registrationUniqueError(tt.err)
+		if tt.want == nil {
+			if got != tt.err {
+				t.Errorf("%s: registrationUniqueError() = %v, want the error unchanged", tt.name, got)
+			}
+			continue
+		}
+		if !errors.Is(got, tt.want) {
+			t.Errorf("%s: registrationUniqueError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
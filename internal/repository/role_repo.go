@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepo stores admin roles and their permissions.
+type RoleRepo interface {
+	// List returns every role with its permissions, by name.
+	List() ([]models.AdminRole, error)
+	Get(name models.Role) (models.AdminRole, error)
+	Create(role models.AdminRole) error
+	// Update saves the description and replaces the permissions of a role.
+	Update(role models.AdminRole) error
+	Delete(name models.Role) error
+	// CountAdmins counts the admins holding a role.
+	CountAdmins(name models.Role) (int64, error)
+}
+
+type roleRepo struct {
+	db *gorm.DB
+}
+
+func NewRoleRepo(db *gorm.DB) RoleRepo {
+	return &roleRepo{db: db}
+}
+
+func (r *roleRepo) List() ([]models.AdminRole, error) {
+	var roles []models.AdminRole
+	err := r.db.Preload("Permissions", orderByPermission).Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepo) Get(name models.Role) (models.AdminRole, error) {
+	var role models.AdminRole
+	err := r.db.Preload("Permissions", orderByPermission).Where("name = ?", name).First(&role).Error
+	return role, err
+}
+
+func (r *roleRepo) Create(role models.AdminRole) error {
+	// the permissions are inserted by the association
+	return r.db.Create(&role).Error
+}
+
+func (r *roleRepo) Update(role models.AdminRole) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AdminRole{}).Where("name = ?", role.Name).Update("description", role.Description)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("role = ?", role.Name).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+		return tx.Create(&role.Permissions).Error
+	})
+}
+
+func (r *roleRepo) Delete(name models.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", name).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("name = ?", name).Delete(&models.AdminRole{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *roleRepo) CountAdmins(name models.Role) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.Admin{}).Where("role = ?", name).Count(&n).Error
+	return n, err
+}
+
+func orderByPermission(db *gorm.DB) *gorm.DB {
+	return db.Order("permission ASC")
+}
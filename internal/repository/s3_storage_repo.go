@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/sirupsen/logrus"
+)
+
+type s3StorageRepo struct {
+	client        *minio.Client
+	bucketName    string
+	publicBaseURL string
+	isPublic      bool
+}
+
+// NewS3StorageRepo works with any S3-compatible endpoint (AWS S3, MinIO, R2, ...).
+// publicBaseURL is optional (CDN / custom domain); defaults to <endpoint>/<bucket>.
+func NewS3StorageRepo(client *minio.Client, bucketName, publicBaseURL string, isPublic bool) ObjectStore {
+	return &s3StorageRepo{
+		client:        client,
+		bucketName:    bucketName,
+		publicBaseURL: strings.TrimRight(publicBaseURL, "/"),
+		isPublic:      isPublic,
+	}
+}
+
+func (r *s3StorageRepo) validate() error {
+	if r.client == nil || r.bucketName == "" {
+		return ErrStorageNotConfigured
+	}
+	return nil
+}
+
+func (r *s3StorageRepo) publicURL(objectName string) string {
+	if r.publicBaseURL != "" {
+		return r.publicBaseURL + "/" + objectName
+	}
+	return strings.TrimRight(r.client.EndpointURL().String(), "/") + "/" + r.bucketName + "/" + objectName
+}
+
+// UploadFile — handle file upload to S3-compatible bucket
+func (r *s3StorageRepo) UploadFile(ctx context.Context, file io.Reader, objectName string) (string, error) {
+	if err := r.validate(); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
+	defer cancel()
+
+	opts := minio.PutObjectOptions{ContentType: mime.TypeByExtension(filepath.Ext(objectName))}
+	if _, err := r.client.PutObject(ctx, r.bucketName, objectName, file, -1, opts); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"bucket": r.bucketName,
+			"object": objectName,
+		}).Error("s3 upload failed")
+		return "", err
+	}
+
+	if r.isPublic {
+		url := r.publicURL(objectName)
+		logrus.WithFields(logrus.Fields{
+			"bucket": r.bucketName,
+			"object": objectName,
+			"url":    url,
+		}).Info("public file uploaded to s3")
+		return url, nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"bucket": r.bucketName,
+		"object": objectName,
+	}).Info("private file uploaded to s3")
+	return objectName, nil
+}
+
+// GenerateSignedURL — presigned GET for private objects
+func (r *s3StorageRepo) GenerateSignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error) {
+	if err := r.validate(); err != nil {
+		return "", err
+	}
+
+	if r.isPublic {
+		return r.publicURL(objectName), nil
+	}
+
+	u, err := r.client.PresignedGetObject(ctx, r.bucketName, objectName, expire, nil)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"bucket": r.bucketName,
+			"object": objectName,
+		}).Error("failed generating s3 presigned url")
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// GenerateUploadURL — presigned PUT bound to the content type (S3 has no resumable PUT session)
+func (r *s3StorageRepo) GenerateUploadURL(ctx context.Context, objectName, contentType string, resumable bool, expire time.Duration) (UploadTarget, error) {
+	_ = resumable
+
+	if err := r.validate(); err != nil {
+		return UploadTarget{}, err
+	}
+
+	u, err := r.client.PresignHeader(ctx, http.MethodPut, r.bucketName, objectName, expire, nil, http.Header{
+		"Content-Type": []string{contentType},
+	})
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"bucket": r.bucketName,
+			"object": objectName,
+		}).Error("failed generating s3 presigned upload url")
+		return UploadTarget{}, err
+	}
+
+	return UploadTarget{
+		URL:     u.String(),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+func (r *s3StorageRepo) StatObject(ctx context.Context, objectName string) (ObjectInfo, error) {
+	if err := r.validate(); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	info, err := r.client.StatObject(ctx, r.bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: info.Size, ContentType: info.ContentType}, nil
+}
+
+func (r *s3StorageRepo) DeleteObject(ctx context.Context, objectName string) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	return r.client.RemoveObject(ctx, r.bucketName, objectName, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"darulabror/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrSessionFull is returned when an assignment would exceed the session capacity.
+	ErrSessionFull = errors.New("selection session is full")
+	// ErrComponentInUse is returned when removing a component that already has scores.
+	ErrComponentInUse = errors.New("selection component has scores")
+)
+
+// SelectionCandidate is a registration taking part in the selection of a period.
+type SelectionCandidate struct {
+	ID        uint
+	FullName  string
+	NISN      string
+	Gender    models.Gender
+	Program   models.Program
+	Status    models.RegistrationStatus
+	SessionID *uint
+	CreatedAt time.Time // ranking tie-break: earlier registration first
+}
+
+// SelectionCandidateStatuses are the statuses that take part in the selection (after validate).
+var SelectionCandidateStatuses = []models.RegistrationStatus{
+	models.RegistrationStatusValidate,
+	models.RegistrationStatusProcess,
+	models.RegistrationStatusDone,
+}
+
+type SelectionRepo interface {
+	// Components
+	GetComponents(periodID uint) ([]models.SelectionComponent, error)
+	// ReplaceComponents upserts components by key and deletes the missing ones (ErrComponentInUse if scored).
+	ReplaceComponents(periodID uint, components []models.SelectionComponent) error
+
+	// Sessions
+	CreateSession(session *models.SelectionSession) error
+	GetSessions(periodID uint) ([]models.SelectionSession, error)
+	GetSessionByID(id uint) (models.SelectionSession, error)
+	UpdateSession(session models.SelectionSession) error
+	DeleteSession(id uint) error
+	CountAssignments(sessionID uint) (int64, error)
+
+	// Assignments
+	// Assign moves the registrations into the session (replacing earlier assignments); ErrSessionFull over capacity.
+	Assign(sessionID uint, registrationIDs []uint) error
+	Unassign(sessionID, registrationID uint) error
+	GetSessionCandidates(sessionID uint) ([]SelectionCandidate, error)
+
+	// Scores
+	GetScores(registrationID uint) ([]models.SelectionScore, error)
+	SaveScores(scores []models.SelectionScore) error
+	GetPeriodScores(periodID uint) ([]models.SelectionScore, error)
+	// GetCandidates lists registrations of the period in SelectionCandidateStatuses; empty gender/program = all.
+	GetCandidates(periodID uint, gender models.Gender, program models.Program) ([]SelectionCandidate, error)
+}
+
+type selectionRepo struct {
+	db *gorm.DB
+}
+
+func NewSelectionRepo(db *gorm.DB) SelectionRepo {
+	return &selectionRepo{db: db}
+}
+
+func (r *selectionRepo) GetComponents(periodID uint) ([]models.SelectionComponent, error) {
+	var components []models.SelectionComponent
+	err := r.db.Where("admission_period_id = ?", periodID).Order("id ASC").Find(&components).Error
+	return components, err
+}
+
+func (r *selectionRepo) ReplaceComponents(periodID uint, components []models.SelectionComponent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []models.SelectionComponent
+		if err := tx.Where("admission_period_id = ?", periodID).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		byKey := make(map[string]models.SelectionComponent, len(existing))
+		for _, c := range existing {
+			byKey[c.Key] = c
+		}
+
+		keep := make(map[string]bool, len(components))
+		for _, c := range components {
+			keep[c.Key] = true
+			c.AdmissionPeriodID = periodID
+			if old, ok := byKey[c.Key]; ok {
+				c.ID = old.ID
+				c.CreatedAt = old.CreatedAt
+				if err := tx.Save(&c).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			c.ID = 0
+			if err := tx.Create(&c).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, c := range existing {
+			if keep[c.Key] {
+				continue
+			}
+			var scored int64
+			if err := tx.Model(&models.SelectionScore{}).Where("component_id = ?", c.ID).Count(&scored).Error; err != nil {
+				return err
+			}
+			if scored > 0 {
+				return ErrComponentInUse
+			}
+			if err := tx.Delete(&models.SelectionComponent{}, c.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *selectionRepo) CreateSession(session *models.SelectionSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *selectionRepo) GetSessions(periodID uint) ([]models.SelectionSession, error) {
+	var sessions []models.SelectionSession
+	err := r.db.Where("admission_period_id = ?", periodID).Order("starts_at ASC, id ASC").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *selectionRepo) GetSessionByID(id uint) (models.SelectionSession, error) {
+	var session models.SelectionSession
+	err := r.db.First(&session, id).Error
+	return session, err
+}
+
+func (r *selectionRepo) UpdateSession(session models.SelectionSession) error {
+	return r.db.Save(&session).Error
+}
+
+func (r *selectionRepo) DeleteSession(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ?", id).Delete(&models.SelectionAssignment{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.SelectionSession{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *selectionRepo) CountAssignments(sessionID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SelectionAssignment{}).Where("session_id = ?", sessionID).Count(&count).Error
+	return count, err
+}
+
+func (r *selectionRepo) Assign(sessionID uint, registrationIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// the session lock serializes assignments, so capacity is never exceeded
+		var session models.SelectionSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, sessionID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("registration_id IN ?", registrationIDs).Delete(&models.SelectionAssignment{}).Error; err != nil {
+			return err
+		}
+
+		var assigned int64
+		if err := tx.Model(&models.SelectionAssignment{}).Where("session_id = ?", sessionID).Count(&assigned).Error; err != nil {
+			return err
+		}
+		if assigned+int64(len(registrationIDs)) > int64(session.Capacity) {
+			return ErrSessionFull
+		}
+
+		assignments := make([]models.SelectionAssignment, 0, len(registrationIDs))
+		for _, id := range registrationIDs {
+			assignments = append(assignments, models.SelectionAssignment{SessionID: sessionID, RegistrationID: id})
+		}
+		return tx.Create(&assignments).Error
+	})
+}
+
+func (r *selectionRepo) Unassign(sessionID, registrationID uint) error {
+	result := r.db.Where("session_id = ? AND registration_id = ?", sessionID, registrationID).
+		Delete(&models.SelectionAssignment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// candidateColumns selects SelectionCandidate fields from registrations r joined with assignments a.
+const candidateColumns = "r.id, r.full_name, r.nisn, r.gender, r.program, r.status, a.session_id, r.created_at"
+
+func (r *selectionRepo) GetSessionCandidates(sessionID uint) ([]SelectionCandidate, error) {
+	var candidates []SelectionCandidate
+	err := r.db.Table("selection_assignments AS a").
+		Select(candidateColumns).
+		Joins("JOIN registrations AS r ON r.id = a.registration_id").
+		Where("a.session_id = ?", sessionID).
+		Order("r.full_name ASC").
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+func (r *selectionRepo) GetScores(registrationID uint) ([]models.SelectionScore, error) {
+	var scores []models.SelectionScore
+	err := r.db.Where("registration_id = ?", registrationID).Order("component_id ASC").Find(&scores).Error
+	return scores, err
+}
+
+func (r *selectionRepo) SaveScores(scores []models.SelectionScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "registration_id"}, {Name: "component_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "scored_by", "updated_at"}),
+	}).Create(&scores).Error
+}
+
+func (r *selectionRepo) GetPeriodScores(periodID uint) ([]models.SelectionScore, error) {
+	var scores []models.SelectionScore
+	err := r.db.Model(&models.SelectionScore{}).
+		Joins("JOIN selection_components ON selection_components.id = selection_scores.component_id").
+		Where("selection_components.admission_period_id = ?", periodID).
+		Find(&scores).Error
+	return scores, err
+}
+
+func (r *selectionRepo) GetCandidates(periodID uint, gender models.Gender, program models.Program) ([]SelectionCandidate, error) {
+	query := r.db.Table("registrations AS r").
+		Select(candidateColumns).
+		Joins("LEFT JOIN selection_assignments AS a ON a.registration_id = r.id").
+		Where("r.admission_period_id = ? AND r.status IN ?", periodID, SelectionCandidateStatuses)
+	if gender != "" {
+		query = query.Where("r.gender = ?", gender)
+	}
+	if program != "" {
+		query = query.Where("r.program = ?", program)
+	}
+
+	var candidates []SelectionCandidate
+	err := query.Order("r.created_at ASC, r.id ASC").Scan(&candidates).Error
+	return candidates, err
+}
@@ -0,0 +1,34 @@
+package repository
+
+// KeyCount is one group of an aggregate (e.g. status "new": 12).
+type KeyCount struct {
+	Key   string
+	Count int64
+}
+
+// DailyCount is the number of rows created on one day (YYYY-MM-DD, WIB).
+type DailyCount struct {
+	Day   string
+	Count int64
+}
+
+// RegistrationBreakdown holds registration counts per status, gender and student type.
+type RegistrationBreakdown struct {
+	Total         int64
+	ByStatus      []KeyCount
+	ByGender      []KeyCount
+	ByStudentType []KeyCount
+}
+
+// ContactBacklog counts contacts of one status by age (ContactAgeBuckets).
+type ContactBacklog struct {
+	Status string
+	Bucket string
+	Count  int64
+}
+
+// ContactAgeBuckets are the age groups of ContactBacklog, youngest first.
+var ContactAgeBuckets = []string{"lt_1d", "1d_3d", "3d_7d", "gt_7d"}
+
+// statsTimeZone is the day boundary of daily counts.
+const statsTimeZone = "Asia/Jakarta"
@@ -0,0 +1,505 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/sirupsen/logrus"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMFAIssuer       = "Darul Abror"
+	defaultMFAChallengeTTL = 5 * time.Minute
+
+	// purposes of the challenge tokens issued by the first login step
+	mfaPurposeVerify = "mfa"
+	mfaPurposeEnroll = "mfa_enroll"
+
+	totpPeriod = 30
+	// after maxMFAAttempts wrong codes in a row, codes are refused for mfaLockout
+	maxMFAAttempts    = 5
+	mfaLockout        = 15 * time.Minute
+	recoveryCodeCount = 10
+)
+
+// MFAConfig is loaded by config.LoadMFAConfig.
+type MFAConfig struct {
+	Issuer        string        // name shown in authenticator apps (default "Darul Abror")
+	EncryptionKey []byte        // key of the stored TOTP secrets (hashed to an AES-256 key)
+	ChallengeTTL  time.Duration // lifetime of the mfa_token of the first login step (default 5 minutes)
+}
+
+var totpOpts = totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// mfaChallengeClaims is the mfa_token; it has no role, so JWTAuth never accepts it.
+type mfaChallengeClaims struct {
+	AdminID uint   `json:"admin_id"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+func (s *adminService) signMFAChallenge(admin models.Admin, purpose string) (dto.AdminLoginDTO, error) {
+	exp := time.Now().Add(s.mfaCfg.ChallengeTTL)
+	claims := mfaChallengeClaims{
+		AdminID: admin.ID,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		logrus.WithError(err).Error("failed sign mfa token")
+		return dto.AdminLoginDTO{}, err
+	}
+	return dto.AdminLoginDTO{
+		MFARequired:           purpose == mfaPurposeVerify,
+		MFAEnrollmentRequired: purpose == mfaPurposeEnroll,
+		MFAToken:              signed,
+		MFATokenExpiresAt:     exp.Unix(),
+	}, nil
+}
+
+// challengeAdmin checks an mfa_token of purpose and returns its (still active) admin.
+func (s *adminService) challengeAdmin(token, purpose string) (models.Admin, error) {
+	var claims mfaChallengeClaims
+	parsed, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return s.jwtSecret, nil
+	})
+	if err != nil || !parsed.Valid || claims.Purpose != purpose || claims.AdminID == 0 {
+		return models.Admin{}, ErrInvalidMFAToken
+	}
+
+	admin, err := s.repo.GetAdminByID(claims.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Admin{}, ErrInvalidMFAToken
+		}
+		logrus.WithError(err).WithField("id", claims.AdminID).Error("failed to get admin by id")
+		return models.Admin{}, err
+	}
+	if !admin.IsActive {
+		return models.Admin{}, ErrAdminInactive
+	}
+	return admin, nil
+}
+
+func (s *adminService) VerifyLoginMFA(mfaToken, code string) (dto.AdminLoginDTO, error) {
+	admin, err := s.challengeAdmin(mfaToken, mfaPurposeVerify)
+	if err != nil {
+		return dto.AdminLoginDTO{}, err
+	}
+	mfa, err := s.enabledMFA(admin.ID)
+	if err != nil {
+		return dto.AdminLoginDTO{}, err
+	}
+	if err := s.checkMFACode(&mfa, code, true); err != nil {
+		return dto.AdminLoginDTO{}, err
+	}
+	return s.issueToken(admin)
+}
+
+func (s *adminService) SetupLoginMFA(mfaToken string) (dto.MFASetupDTO, error) {
+	admin, err := s.challengeAdmin(mfaToken, mfaPurposeEnroll)
+	if err != nil {
+		return dto.MFASetupDTO{}, err
+	}
+	return s.setupMFA(admin)
+}
+
+func (s *adminService) EnrollLoginMFA(mfaToken, code string) (dto.AdminLoginDTO, error) {
+	admin, err := s.challengeAdmin(mfaToken, mfaPurposeEnroll)
+	if err != nil {
+		return dto.AdminLoginDTO{}, err
+	}
+	codes, err := s.enableMFA(admin.ID, code)
+	if err != nil {
+		return dto.AdminLoginDTO{}, err
+	}
+	out, err := s.issueToken(admin)
+	if err != nil {
+		return dto.AdminLoginDTO{}, err
+	}
+	out.RecoveryCodes = codes
+	return out, nil
+}
+
+func (s *adminService) GetMFAStatus(adminID uint) (dto.MFAStatusDTO, error) {
+	admin, err := s.getAdmin(adminID)
+	if err != nil {
+		return dto.MFAStatusDTO{}, err
+	}
+	out := dto.MFAStatusDTO{Required: admin.MFARequired}
+
+	mfa, err := s.mfa.Get(adminID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !mfa.Enabled()) {
+		return out, nil
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("id", adminID).Error("failed get admin mfa")
+		return dto.MFAStatusDTO{}, err
+	}
+	out.Enabled, out.EnabledAt = true, mfa.EnabledAt
+	if out.RecoveryCodesLeft, err = s.mfa.CountUnusedRecoveryCodes(adminID); err != nil {
+		logrus.WithError(err).WithField("id", adminID).Error("failed count recovery codes")
+		return dto.MFAStatusDTO{}, err
+	}
+	return out, nil
+}
+
+func (s *adminService) SetupMFA(adminID uint) (dto.MFASetupDTO, error) {
+	admin, err := s.getAdmin(adminID)
+	if err != nil {
+		return dto.MFASetupDTO{}, err
+	}
+	return s.setupMFA(admin)
+}
+
+func (s *adminService) EnableMFA(adminID uint, code string) ([]string, error) {
+	return s.enableMFA(adminID, code)
+}
+
+func (s *adminService) RegenerateRecoveryCodes(adminID uint, code string) ([]string, error) {
+	mfa, err := s.enabledMFA(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMFACode(&mfa, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfa.ReplaceRecoveryCodes(adminID, hashes); err != nil {
+		logrus.WithError(err).WithField("id", adminID).Error("failed replace recovery codes")
+		return nil, err
+	}
+	logrus.WithField("id", adminID).Info("admin recovery codes regenerated")
+	return codes, nil
+}
+
+func (s *adminService) DisableMFA(adminID uint, password, code string) error {
+	admin, err := s.getAdmin(adminID)
+	if err != nil {
+		return err
+	}
+	if admin.MFARequired {
+		return ErrMFARequired
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	mfa, err := s.enabledMFA(adminID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkMFACode(&mfa, code, true); err != nil {
+		return err
+	}
+
+	if err := s.mfa.Delete(adminID); err != nil {
+		logrus.WithError(err).WithField("id", adminID).Error("failed delete admin mfa")
+		return err
+	}
+	logrus.WithField("id", adminID).Info("admin mfa disabled")
+	return nil
+}
+
+func (s *adminService) ResetMFA(held []models.Permission, id uint) error {
+	admin, err := s.getAdmin(id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkRoleGrant(held, admin.Role); err != nil {
+		return err
+	}
+
+	if err := s.mfa.Delete(id); err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed delete admin mfa")
+		return err
+	}
+	logrus.WithField("id", id).Info("admin mfa reset")
+	return nil
+}
+
+func (s *adminService) getAdmin(id uint) (models.Admin, error) {
+	admin, err := s.repo.GetAdminByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Admin{}, ErrNotFoundAdmin
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed to get admin by id")
+		return models.Admin{}, err
+	}
+	return admin, nil
+}
+
+func (s *adminService) enabledMFA(adminID uint) (models.AdminMFA, error) {
+	mfa, err := s.mfa.Get(adminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.AdminMFA{}, ErrMFANotEnabled
+		}
+		logrus.WithError(err).WithField("id", adminID).Error("failed get admin mfa")
+		return models.AdminMFA{}, err
+	}
+	if !mfa.Enabled() {
+		return models.AdminMFA{}, ErrMFANotEnabled
+	}
+	return mfa, nil
+}
+
+// setupMFA stores a new pending secret; it replaces an earlier unconfirmed one.
+func (s *adminService) setupMFA(admin models.Admin) (dto.MFASetupDTO, error) {
+	if mfa, err := s.mfa.Get(admin.ID); err == nil && mfa.Enabled() {
+		return dto.MFASetupDTO{}, ErrMFAAlreadyEnabled
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.WithError(err).WithField("id", admin.ID).Error("failed get admin mfa")
+		return dto.MFASetupDTO{}, err
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.mfaCfg.Issuer,
+		AccountName: admin.Email,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("failed generate totp key")
+		return dto.MFASetupDTO{}, err
+	}
+	sealed, err := sealMFASecret(s.mfaKey, key.Secret())
+	if err != nil {
+		logrus.WithError(err).Error("failed encrypt totp secret")
+		return dto.MFASetupDTO{}, err
+	}
+	if err := s.mfa.Save(models.AdminMFA{AdminID: admin.ID, Secret: sealed}); err != nil {
+		logrus.WithError(err).WithField("id", admin.ID).Error("failed save admin mfa")
+		return dto.MFASetupDTO{}, err
+	}
+
+	qr, err := qrDataURL(key)
+	if err != nil {
+		logrus.WithError(err).Error("failed render totp qr code")
+		return dto.MFASetupDTO{}, err
+	}
+	logrus.WithField("id", admin.ID).Info("admin mfa setup started")
+	return dto.MFASetupDTO{Secret: key.Secret(), OTPAuthURL: key.URL(), QRCode: qr}, nil
+}
+
+// enableMFA confirms the pending secret with a first code and returns new recovery codes.
+func (s *adminService) enableMFA(adminID uint, code string) ([]string, error) {
+	mfa, err := s.mfa.Get(adminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFANotSetUp
+		}
+		logrus.WithError(err).WithField("id", adminID).Error("failed get admin mfa")
+		return nil, err
+	}
+	if mfa.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err := s.checkMFACode(&mfa, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	mfa.EnabledAt = time.Now().Unix()
+	if err := s.mfa.Enable(mfa, hashes); err != nil {
+		logrus.WithError(err).WithField("id", adminID).Error("failed enable admin mfa")
+		return nil, err
+	}
+	logrus.WithField("id", adminID).Info("admin mfa enabled")
+	return codes, nil
+}
+
+// checkMFACode accepts a TOTP code (each only once) or, with allowRecovery, an unused
+// recovery code. Wrong codes count towards the lockout; the attempt state is saved either way.
+// The check runs under the authenticator's row lock and reloads mfa there, so parallel
+// attempts cannot get past the lockout or reuse a TOTP step.
+func (s *adminService) checkMFACode(mfa *models.AdminMFA, code string, allowRecovery bool) error {
+	var ok bool
+	err := s.mfa.WithLock(mfa.AdminID, func(repo repository.AdminMFARepo) error {
+		current, err := repo.Get(mfa.AdminID)
+		if err != nil {
+			return err
+		}
+		*mfa = current
+
+		now := time.Now()
+		if mfa.LockedUntil > now.Unix() {
+			return ErrMFALocked
+		}
+		if ok, err = s.matchMFACode(repo, mfa, code, allowRecovery, now); err != nil {
+			return err
+		}
+		if ok {
+			mfa.FailedAttempts, mfa.LockedUntil = 0, 0
+		} else {
+			mfa.FailedAttempts++
+			if mfa.FailedAttempts >= maxMFAAttempts {
+				mfa.FailedAttempts, mfa.LockedUntil = 0, now.Add(mfaLockout).Unix()
+				logrus.WithField("id", mfa.AdminID).Warn("admin mfa locked after wrong codes")
+			}
+		}
+		if err := repo.Save(*mfa); err != nil {
+			logrus.WithError(err).WithField("id", mfa.AdminID).Error("failed save admin mfa")
+			return err
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// removed by a concurrent disable or reset
+		return ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *adminService) matchMFACode(repo repository.AdminMFARepo, mfa *models.AdminMFA, code string, allowRecovery bool, now time.Time) (bool, error) {
+	code = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	if len(code) == int(otp.DigitsSix) {
+		secret, err := openMFASecret(s.mfaKey, mfa.Secret)
+		if err != nil {
+			logrus.WithError(err).WithField("id", mfa.AdminID).Error("failed decrypt totp secret")
+			return false, err
+		}
+		step, ok := matchTOTP(secret, code, now, mfa.LastStep)
+		if ok {
+			mfa.LastStep = step
+		}
+		return ok, nil
+	}
+	if !allowRecovery || code == "" {
+		return false, nil
+	}
+
+	used, err := repo.UseRecoveryCode(mfa.AdminID, hashRecoveryCode(code), now.Unix())
+	if err != nil {
+		logrus.WithError(err).WithField("id", mfa.AdminID).Error("failed use recovery code")
+		return false, err
+	}
+	if used {
+		logrus.WithField("id", mfa.AdminID).Info("admin recovery code used")
+	}
+	return used, nil
+}
+
+// matchTOTP checks code against the previous, current and next time step (clock drift) and
+// returns the matching step. Steps up to lastStep were used already.
+func matchTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	for _, skew := range []int64{0, -1, 1} {
+		step := now.Unix()/totpPeriod + skew
+		if step <= lastStep {
+			continue
+		}
+		want, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err == nil && subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns codes like "7K3MQ-9TXAB" and their hashes. The codes are random
+// enough (about 50 bits) for a plain SHA-256 to protect them at rest.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = trackingAlphabet[int(b[j])%len(trackingAlphabet)]
+		}
+		codes[i] = string(b[:5]) + "-" + string(b[5:])
+		hashes[i] = hashRecoveryCode(string(b))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a normalized code (upper case, without the dash).
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// sealMFASecret encrypts a TOTP secret as base64(nonce || AES-GCM ciphertext).
+func sealMFASecret(key []byte, secret string) (string, error) {
+	gcm, err := mfaCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func openMFASecret(key []byte, sealed string) (string, error) {
+	gcm, err := mfaCipher(key)
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return "", errors.New("malformed totp secret")
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func mfaCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func qrDataURL(key *otp.Key) (string, error) {
+	png, err := qrcode.Encode(key.URL(), qrcode.Medium, 256)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type fakeAdminRepo struct {
+	repository.AdminRepository
+	admin models.Admin
+}
+
+func (f *fakeAdminRepo) GetAdminByID(id uint) (models.Admin, error) {
+	if id != f.admin.ID {
+		return models.Admin{}, gorm.ErrRecordNotFound
+	}
+	return f.admin, nil
+}
+
+func (f *fakeAdminRepo) GetAdminByEmail(email string) (models.Admin, error) {
+	if email != f.admin.Email {
+		return models.Admin{}, gorm.ErrRecordNotFound
+	}
+	return f.admin, nil
+}
+
+func (f *fakeAdminRepo) UpdateAdmin(admin models.Admin) error {
+	f.admin = admin
+	return nil
+}
+
+type fakeAdminMFARepo struct {
+	repository.AdminMFARepo
+	lock  sync.Mutex // held by WithLock, like the row lock
+	mu    sync.Mutex
+	mfa   *models.AdminMFA
+	codes map[string]bool // hash -> used
+}
+
+func (f *fakeAdminMFARepo) Get(adminID uint) (models.AdminMFA, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.mfa == nil {
+		return models.AdminMFA{}, gorm.ErrRecordNotFound
+	}
+	return *f.mfa, nil
+}
+
+func (f *fakeAdminMFARepo) Save(mfa models.AdminMFA) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mfa = &mfa
+	return nil
+}
+
+func (f *fakeAdminMFARepo) Enable(mfa models.AdminMFA, codeHashes []string) error {
+	f.Save(mfa)
+	return f.ReplaceRecoveryCodes(mfa.AdminID, codeHashes)
+}
+
+func (f *fakeAdminMFARepo) WithLock(adminID uint, fn func(repo repository.AdminMFARepo) error) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, err := f.Get(adminID); err != nil {
+		return err
+	}
+	return fn(f)
+}
+
+func (f *fakeAdminMFARepo) ReplaceRecoveryCodes(adminID uint, codeHashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.codes = map[string]bool{}
+	for _, h := range codeHashes {
+		f.codes[h] = false
+	}
+	return nil
+}
+
+func (f *fakeAdminMFARepo) UseRecoveryCode(adminID uint, codeHash string, usedAt int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	used, ok := f.codes[codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	f.codes[codeHash] = true
+	return true, nil
+}
+
+func TestMFASecretSealing(t *testing.T) {
+	key := make([]byte, 32)
+	sealed, err := sealMFASecret(key, "JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := openMFASecret(key, sealed); err != nil || got != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("openMFASecret() = %q, %v", got, err)
+	}
+	other := make([]byte, 32)
+	other[0] = 1
+	if _, err := openMFASecret(other, sealed); err == nil {
+		t.Error("openMFASecret() with another key should fail")
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	now := time.Unix(1734567890, 0)
+	step := now.Unix() / totpPeriod
+
+	prev, _ := totp.GenerateCodeCustom(secret, now.Add(-totpPeriod*time.Second), totpOpts)
+	if got, ok := matchTOTP(secret, prev, now, 0); !ok || got != step-1 {
+		t.Errorf("previous step code: step = %d, ok = %v", got, ok)
+	}
+	cur, _ := totp.GenerateCodeCustom(secret, now, totpOpts)
+	if _, ok := matchTOTP(secret, cur, now, step); ok {
+		t.Error("a code of an already used step must be refused")
+	}
+	old, _ := totp.GenerateCodeCustom(secret, now.Add(-5*time.Minute), totpOpts)
+	if _, ok := matchTOTP(secret, old, now, 0); ok {
+		t.Error("a code outside the drift window must be refused")
+	}
+}
+
+func TestMFALoginFlow(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	admins := &fakeAdminRepo{admin: models.Admin{ID: 7, Email: "a@darulabror.com", Password: string(hash), Role: models.Admins, IsActive: true}}
+	mfaRepo := &fakeAdminMFARepo{}
+	s := NewAdminService(admins, newFakeRoleRepo(), mfaRepo, "jwt-secret", MFAConfig{EncryptionKey: []byte("k")}).(*adminService)
+
+	out, err := s.AuthenticateAdmin("a@darulabror.com", "secret123")
+	if err != nil || out.Token == "" {
+		t.Fatalf("login without mfa = %+v, %v", out, err)
+	}
+
+	setup, err := s.SetupMFA(7)
+	if err != nil {
+		t.Fatalf("SetupMFA() error = %v", err)
+	}
+	code, _ := totp.GenerateCodeCustom(setup.Secret, time.Now(), totpOpts)
+	recovery, err := s.EnableMFA(7, code)
+	if err != nil || len(recovery) != recoveryCodeCount {
+		t.Fatalf("EnableMFA() = %d codes, %v", len(recovery), err)
+	}
+	if _, err := s.SetupMFA(7); !errors.Is(err, ErrMFAAlreadyEnabled) {
+		t.Errorf("second SetupMFA() error = %v, want %v", err, ErrMFAAlreadyEnabled)
+	}
+
+	challenge, err := s.AuthenticateAdmin("a@darulabror.com", "secret123")
+	if err != nil || challenge.Token != "" || !challenge.MFARequired || challenge.MFAToken == "" {
+		t.Fatalf("login with mfa = %+v, %v", challenge, err)
+	}
+	if _, err := s.SetupLoginMFA(challenge.MFAToken); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("verify token used for enrollment: error = %v, want %v", err, ErrInvalidMFAToken)
+	}
+
+	// recovery codes are case and dash insensitive and work once
+	lower := "  " + recovery[0][:5] + recovery[0][6:]
+	if out, err := s.VerifyLoginMFA(challenge.MFAToken, lower); err != nil || out.Token == "" {
+		t.Fatalf("VerifyLoginMFA(recovery) = %+v, %v", out, err)
+	}
+	if _, err := s.VerifyLoginMFA(challenge.MFAToken, recovery[0]); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("reused recovery code error = %v, want %v", err, ErrInvalidMFACode)
+	}
+
+	for i := 1; i < maxMFAAttempts; i++ {
+		s.VerifyLoginMFA(challenge.MFAToken, "000000")
+	}
+	if _, err := s.VerifyLoginMFA(challenge.MFAToken, recovery[1]); !errors.Is(err, ErrMFALocked) {
+		t.Errorf("after %d wrong codes error = %v, want %v", maxMFAAttempts, err, ErrMFALocked)
+	}
+}
+
+func TestMFAEnrollmentRequired(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	admins := &fakeAdminRepo{admin: models.Admin{ID: 3, Email: "b@darulabror.com", Password: string(hash), Role: models.Admins, IsActive: true, MFARequired: true}}
+	s := NewAdminService(admins, newFakeRoleRepo(), &fakeAdminMFARepo{}, "jwt-secret", MFAConfig{})
+
+	challenge, err := s.AuthenticateAdmin("b@darulabror.com", "secret123")
+	if err != nil || !challenge.MFAEnrollmentRequired || challenge.Token != "" {
+		t.Fatalf("login = %+v, %v", challenge, err)
+	}
+	setup, err := s.SetupLoginMFA(challenge.MFAToken)
+	if err != nil {
+		t.Fatalf("SetupLoginMFA() error = %v", err)
+	}
+	code, _ := totp.GenerateCodeCustom(setup.Secret, time.Now(), totpOpts)
+	out, err := s.EnrollLoginMFA(challenge.MFAToken, code)
+	if err != nil || out.Token == "" || len(out.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("EnrollLoginMFA() = %+v, %v", out, err)
+	}
+	if err := s.DisableMFA(3, "secret123", out.RecoveryCodes[0]); !errors.Is(err, ErrMFARequired) {
+		t.Errorf("DisableMFA() error = %v, want %v", err, ErrMFARequired)
+	}
+}
+
+func TestCheckMFACodeConcurrent(t *testing.T) {
+	newEnabled := func(t *testing.T) (*adminService, string) {
+		s := NewAdminService(&fakeAdminRepo{admin: models.Admin{ID: 5, Email: "c@darulabror.com", IsActive: true}}, newFakeRoleRepo(), &fakeAdminMFARepo{}, "jwt-secret", MFAConfig{EncryptionKey: []byte("k")}).(*adminService)
+		setup, err := s.SetupMFA(5)
+		if err != nil {
+			t.Fatalf("SetupMFA() error = %v", err)
+		}
+		code, _ := totp.GenerateCodeCustom(setup.Secret, time.Now(), totpOpts)
+		if _, err := s.EnableMFA(5, code); err != nil {
+			t.Fatalf("EnableMFA() error = %v", err)
+		}
+		return s, setup.Secret
+	}
+	// parallel runs code n times at once and counts the errors
+	parallel := func(s *adminService, code string, n int) map[error]int {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		got := map[error]int{}
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.RegenerateRecoveryCodes(5, code)
+				mu.Lock()
+				got[err]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return got
+	}
+
+	t.Run("wrong codes lock", func(t *testing.T) {
+		s, _ := newEnabled(t)
+		got := parallel(s, "000000", 4*maxMFAAttempts)
+		if got[ErrInvalidMFACode] != maxMFAAttempts || got[ErrMFALocked] != 3*maxMFAAttempts {
+			t.Errorf("errors = %v, want %d invalid then locked", got, maxMFAAttempts)
+		}
+	})
+
+	t.Run("code used once", func(t *testing.T) {
+		s, secret := newEnabled(t)
+		// the enabling code used the current step; the next step is still inside the drift window
+		next, _ := totp.GenerateCodeCustom(secret, time.Now().Add(totpPeriod*time.Second), totpOpts)
+		// replays count as wrong codes, so stay below the lockout
+		got := parallel(s, next, maxMFAAttempts)
+		if got[nil] != 1 || got[ErrInvalidMFACode] != maxMFAAttempts-1 {
+			t.Errorf("errors = %v, want one success", got)
+		}
+	})
+}
+
+func TestUpdateAdminMFARequired(t *testing.T) {
+	admins := &fakeAdminRepo{admin: models.Admin{ID: 4, Username: "d", Email: "d@darulabror.com", Role: models.Admins, IsActive: true, MFARequired: true}}
+	s := NewAdminService(admins, newFakeRoleRepo(), &fakeAdminMFARepo{}, "jwt-secret", MFAConfig{})
+	held := models.DefaultPermissions(models.Admins)
+	off, on := false, true
+	in := dto.AdminDTO{ID: 4, Username: "d", Email: "d@darulabror.com", Role: models.Admins, MFARequired: &off}
+
+	if err := s.UpdateAdmin(held, 4, in); !errors.Is(err, ErrMFARequiredSelf) {
+		t.Errorf("clearing own mfa_required: error = %v, want %v", err, ErrMFARequiredSelf)
+	}
+	if !admins.admin.MFARequired {
+		t.Fatal("own mfa_required was cleared")
+	}
+
+	// unchanged on the own account is fine, e.g. when editing the own username
+	in.MFARequired, in.Username = &on, "dd"
+	if err := s.UpdateAdmin(held, 4, in); err != nil || admins.admin.Username != "dd" {
+		t.Errorf("own update with unchanged mfa_required = %v, username %q", err, admins.admin.Username)
+	}
+
+	in.MFARequired = &off
+	if err := s.UpdateAdmin(held, 1, in); err != nil || admins.admin.MFARequired {
+		t.Errorf("another admin clearing mfa_required = %v, required %v", err, admins.admin.MFARequired)
+	}
+}
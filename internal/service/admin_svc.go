@@ -1,9 +1,11 @@
 package service
 
 import (
+	"crypto/sha256"
 	"darulabror/internal/dto"
 	"darulabror/internal/models"
 	"darulabror/internal/repository"
+	"darulabror/internal/utils"
 	"errors"
 	"time"
 
@@ -14,86 +16,163 @@ import (
 )
 
 type AdminService interface {
-	// Superadmin only
-	CreateAdmin(requesterRole models.Role, adminDTO dto.AdminDTO) error
-	GetAllAdmins(page, limit int) ([]dto.AdminDTO, int64, error)
-	UpdateAdmin(requesterRole models.Role, adminDTO dto.AdminDTO) error
-	DeleteAdmin(requesterRole models.Role, id uint) error
+	// admins.manage; held are the requester's permissions: admins can only be given, or
+	// taken out of, roles whose permissions the requester holds itself
+	CreateAdmin(held []models.Permission, adminDTO dto.AdminDTO) error
+	GetAllAdmins(q utils.ListQuery) ([]dto.AdminDTO, utils.PageInfo, error)
+	// UpdateAdmin is called by callerID, who cannot change MFARequired of their own account.
+	UpdateAdmin(held []models.Permission, callerID uint, adminDTO dto.AdminDTO) error
+	DeleteAdmin(held []models.Permission, id uint) error
 
 	// shared (admin/superadmin)
 	GetAdminByID(id uint) (dto.AdminDTO, error)
 	ChangePassword(adminID uint, currentPassword, newPassword string) error
 
-	// Public (login)
-	AuthenticateAdmin(email, password string) (string, dto.AdminDTO, error)
+	// Public (login). AuthenticateAdmin checks the password; admins with two-factor
+	// authentication (or required to enroll) get an mfa_token challenge instead of the JWT.
+	AuthenticateAdmin(email, password string) (dto.AdminLoginDTO, error)
+	VerifyLoginMFA(mfaToken, code string) (dto.AdminLoginDTO, error)
+	SetupLoginMFA(mfaToken string) (dto.MFASetupDTO, error)
+	EnrollLoginMFA(mfaToken, code string) (dto.AdminLoginDTO, error)
+
+	// own two-factor authentication (TOTP)
+	GetMFAStatus(adminID uint) (dto.MFAStatusDTO, error)
+	SetupMFA(adminID uint) (dto.MFASetupDTO, error)
+	EnableMFA(adminID uint, code string) ([]string, error)
+	RegenerateRecoveryCodes(adminID uint, code string) ([]string, error)
+	DisableMFA(adminID uint, password, code string) error
+	// ResetMFA removes the authenticator of an admin who lost it (admins.manage).
+	ResetMFA(held []models.Permission, id uint) error
 }
 
 type adminService struct {
 	repo      repository.AdminRepository
+	roles     repository.RoleRepo
+	mfa       repository.AdminMFARepo
 	jwtSecret []byte
 	jwtTTL    time.Duration
+	mfaCfg    MFAConfig
+	mfaKey    []byte
 }
 
-func NewAdminService(repo repository.AdminRepository, jwtSecret string) AdminService {
+func NewAdminService(
+	repo repository.AdminRepository,
+	roles repository.RoleRepo,
+	mfa repository.AdminMFARepo,
+	jwtSecret string,
+	mfaCfg MFAConfig,
+) AdminService {
+	if mfaCfg.Issuer == "" {
+		mfaCfg.Issuer = defaultMFAIssuer
+	}
+	if mfaCfg.ChallengeTTL <= 0 {
+		mfaCfg.ChallengeTTL = defaultMFAChallengeTTL
+	}
+	mfaKey := sha256.Sum256(mfaCfg.EncryptionKey)
 	return &adminService{
 		repo:      repo,
+		roles:     roles,
+		mfa:       mfa,
 		jwtSecret: []byte(jwtSecret),
 		jwtTTL:    24 * time.Hour,
+		mfaCfg:    mfaCfg,
+		mfaKey:    mfaKey[:],
+	}
+}
+
+// checkRoleGrant fails unless held covers every permission of role.
+func (s *adminService) checkRoleGrant(held []models.Permission, role models.Role) error {
+	perms, err := rolePermissions(s.roles, role)
+	if err != nil {
+		if errors.Is(err, ErrNotFoundRole) {
+			return ErrInvalidAdminRole
+		}
+		logrus.WithError(err).WithField("role", role).Error("failed get role permissions")
+		return err
 	}
+	if !holdsAll(held, perms) {
+		logrus.WithField("role", role).Warn("forbidden admin role grant")
+		return ErrPermissionNotHeld
+	}
+	return nil
 }
 
-func (s *adminService) AuthenticateAdmin(email, password string) (string, dto.AdminDTO, error) {
+func (s *adminService) AuthenticateAdmin(email, password string) (dto.AdminLoginDTO, error) {
 	if len(s.jwtSecret) == 0 {
-		return "", dto.AdminDTO{}, errors.New("JWT secret is not configured")
+		return dto.AdminLoginDTO{}, errors.New("JWT secret is not configured")
 	}
 
 	admin, err := s.repo.GetAdminByEmail(email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", dto.AdminDTO{}, ErrInvalidCredentials
+			return dto.AdminLoginDTO{}, ErrInvalidCredentials
 		}
 		logrus.WithError(err).WithField("email", email).Error("failed get admin by email")
-		return "", dto.AdminDTO{}, err
+		return dto.AdminLoginDTO{}, err
 	}
 
 	if !admin.IsActive {
-		return "", dto.AdminDTO{}, ErrAdminInactive
+		return dto.AdminLoginDTO{}, ErrAdminInactive
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
-		return "", dto.AdminDTO{}, ErrInvalidCredentials
+		return dto.AdminLoginDTO{}, ErrInvalidCredentials
+	}
+
+	mfa, err := s.mfa.Get(admin.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.WithError(err).WithField("id", admin.ID).Error("failed get admin mfa")
+		return dto.AdminLoginDTO{}, err
+	}
+	switch {
+	case mfa.Enabled():
+		return s.signMFAChallenge(admin, mfaPurposeVerify)
+	case admin.MFARequired:
+		return s.signMFAChallenge(admin, mfaPurposeEnroll)
+	}
+	return s.issueToken(admin)
+}
+
+// issueToken signs the admin JWT once every login step passed.
+func (s *adminService) issueToken(admin models.Admin) (dto.AdminLoginDTO, error) {
+	// permissions are embedded, so role changes apply from the next login
+	perms, err := rolePermissions(s.roles, admin.Role)
+	if err != nil {
+		logrus.WithError(err).WithField("role", admin.Role).Error("failed get role permissions")
+		return dto.AdminLoginDTO{}, err
 	}
 
 	now := time.Now()
 	claims := jwt.MapClaims{
-		"admin_id": admin.ID,
-		"role":     admin.Role,
-		"iat":      now.Unix(),
-		"exp":      now.Add(s.jwtTTL).Unix(),
+		"admin_id":    admin.ID,
+		"role":        admin.Role,
+		"permissions": perms,
+		"iat":         now.Unix(),
+		"exp":         now.Add(s.jwtTTL).Unix(),
 	}
 
 	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signed, err := tok.SignedString(s.jwtSecret)
 	if err != nil {
 		logrus.WithError(err).Error("failed sign jwt")
-		return "", dto.AdminDTO{}, err
+		return dto.AdminLoginDTO{}, err
 	}
 
 	out := dto.AdminModelToDTO(admin)
 	out.Password = "" // jangan expose hash
-	return signed, out, nil
+	out.Permissions = perms
+	return dto.AdminLoginDTO{Token: signed, Admin: &out}, nil
 }
 
-func (s *adminService) CreateAdmin(requesterRole models.Role, adminDTO dto.AdminDTO) error {
-	if requesterRole != models.Superadmin {
-		logrus.WithField("requester_role", requesterRole).Warn("forbidden create admin")
-		return errors.New("forbidden")
+func (s *adminService) CreateAdmin(held []models.Permission, adminDTO dto.AdminDTO) error {
+	if err := s.checkRoleGrant(held, adminDTO.Role); err != nil {
+		return err
 	}
 
 	// prevent duplicate email (simple check)
 	if existing, err := s.repo.GetAdminByEmail(adminDTO.Email); err == nil && existing.ID != 0 {
 		logrus.WithField("email", adminDTO.Email).Warn("admin email already exists")
-		return ErrInvalidAdmin
+		return ErrAdminEmailExists
 	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		logrus.WithError(err).Error("failed checking admin email")
 		return err
@@ -127,11 +206,11 @@ func (s *adminService) CreateAdmin(requesterRole models.Role, adminDTO dto.Admin
 	return nil
 }
 
-func (s *adminService) GetAllAdmins(page, limit int) ([]dto.AdminDTO, int64, error) {
-	admins, total, err := s.repo.GetAllAdmins(page, limit)
+func (s *adminService) GetAllAdmins(q utils.ListQuery) ([]dto.AdminDTO, utils.PageInfo, error) {
+	admins, page, err := s.repo.GetAllAdmins(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed to get all admins")
-		return nil, 0, err
+		return nil, utils.PageInfo{}, err
 	}
 
 	out := make([]dto.AdminDTO, 0, len(admins))
@@ -141,7 +220,7 @@ func (s *adminService) GetAllAdmins(page, limit int) ([]dto.AdminDTO, int64, err
 		out = append(out, d)
 	}
 
-	return out, total, nil
+	return out, page, nil
 }
 
 func (s *adminService) GetAdminByID(id uint) (dto.AdminDTO, error) {
@@ -156,14 +235,14 @@ func (s *adminService) GetAdminByID(id uint) (dto.AdminDTO, error) {
 
 	d := dto.AdminModelToDTO(admin)
 	d.Password = ""
+	if d.Permissions, err = rolePermissions(s.roles, admin.Role); err != nil {
+		logrus.WithError(err).WithField("role", admin.Role).Error("failed get role permissions")
+		return dto.AdminDTO{}, err
+	}
 	return d, nil
 }
 
-func (s *adminService) UpdateAdmin(requesterRole models.Role, adminDTO dto.AdminDTO) error {
-	if requesterRole != models.Superadmin {
-		logrus.WithField("requester_role", requesterRole).Warn("forbidden update admin")
-		return errors.New("forbidden")
-	}
+func (s *adminService) UpdateAdmin(held []models.Permission, callerID uint, adminDTO dto.AdminDTO) error {
 	if adminDTO.ID == 0 {
 		return ErrInvalidAdmin
 	}
@@ -175,6 +254,14 @@ func (s *adminService) UpdateAdmin(requesterRole models.Role, adminDTO dto.Admin
 		}
 		return err
 	}
+	if err := s.checkRoleGrant(held, admin.Role); err != nil {
+		return err
+	}
+	if adminDTO.Role != admin.Role {
+		if err := s.checkRoleGrant(held, adminDTO.Role); err != nil {
+			return err
+		}
+	}
 
 	// update mutable fields
 	admin.Username = adminDTO.Username
@@ -183,6 +270,13 @@ func (s *adminService) UpdateAdmin(requesterRole models.Role, adminDTO dto.Admin
 	if adminDTO.IsActive != nil {
 		admin.IsActive = *adminDTO.IsActive
 	}
+	if adminDTO.MFARequired != nil && *adminDTO.MFARequired != admin.MFARequired {
+		// otherwise an admin could lift their own requirement and then disable MFA
+		if admin.ID == callerID {
+			return ErrMFARequiredSelf
+		}
+		admin.MFARequired = *adminDTO.MFARequired
+	}
 
 	if adminDTO.Password != "" {
 		hash, err := bcrypt.GenerateFromPassword([]byte(adminDTO.Password), bcrypt.DefaultCost)
@@ -202,10 +296,17 @@ func (s *adminService) UpdateAdmin(requesterRole models.Role, adminDTO dto.Admin
 	return nil
 }
 
-func (s *adminService) DeleteAdmin(requesterRole models.Role, id uint) error {
-	if requesterRole != models.Superadmin {
-		logrus.WithField("requester_role", requesterRole).Warn("forbidden delete admin")
-		return errors.New("forbidden")
+func (s *adminService) DeleteAdmin(held []models.Permission, id uint) error {
+	admin, err := s.repo.GetAdminByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundAdmin
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed to get admin by id")
+		return err
+	}
+	if err := s.checkRoleGrant(held, admin.Role); err != nil {
+		return err
 	}
 
 	if err := s.repo.DeleteAdmin(id); err != nil {
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AdmissionPeriodService interface {
+	// Public
+	GetCurrent() (dto.AdmissionPeriodDTO, error)
+
+	// Superadmin
+	Create(periodDTO dto.AdmissionPeriodDTO) (dto.AdmissionPeriodDTO, error)
+	GetAll() ([]dto.AdmissionPeriodDTO, error)
+	GetByID(id uint) (dto.AdmissionPeriodDTO, error)
+	Update(id uint, periodDTO dto.AdmissionPeriodDTO) (dto.AdmissionPeriodDTO, error)
+	Delete(id uint) error
+
+	// Quotas per gender/program, with current usage
+	GetQuotas(id uint) ([]dto.AdmissionQuotaDTO, error)
+	// SetQuotas replaces all quotas of the period; waitlisted registrations are promoted into new seats.
+	SetQuotas(id uint, quotas []dto.AdmissionQuotaDTO) ([]dto.AdmissionQuotaDTO, error)
+}
+
+type admissionPeriodService struct {
+	repo      repository.AdmissionPeriodRepo
+	quotaRepo repository.AdmissionQuotaRepo
+}
+
+func NewAdmissionPeriodService(repo repository.AdmissionPeriodRepo, quotaRepo repository.AdmissionQuotaRepo) AdmissionPeriodService {
+	return &admissionPeriodService{repo: repo, quotaRepo: quotaRepo}
+}
+
+var academicYearPattern = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+
+func validAcademicYear(year string) bool {
+	m := academicYearPattern.FindStringSubmatch(year)
+	if m == nil {
+		return false
+	}
+	start, _ := strconv.Atoi(m[1])
+	end, _ := strconv.Atoi(m[2])
+	return end == start+1
+}
+
+func (s *admissionPeriodService) GetCurrent() (dto.AdmissionPeriodDTO, error) {
+	period, err := s.repo.GetOpen(time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.AdmissionPeriodDTO{}, ErrRegistrationClosed
+		}
+		logrus.WithError(err).Error("failed get open admission period")
+		return dto.AdmissionPeriodDTO{}, err
+	}
+	return s.withCount(period)
+}
+
+// checkPeriod enforces the academic year format, unique (year, wave) and non-overlapping dates,
+// so at most one period is open at any time.
+func (s *admissionPeriodService) checkPeriod(period models.AdmissionPeriod) error {
+	if !validAcademicYear(period.AcademicYear) {
+		return ErrInvalidAcademicYear
+	}
+
+	all, err := s.repo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, p := range all {
+		if p.ID != period.ID && p.AcademicYear == period.AcademicYear && p.Wave == period.Wave {
+			return ErrAdmissionPeriodExists
+		}
+	}
+
+	overlap, err := s.repo.ExistsOverlapping(period.OpensAt, period.ClosesAt, period.ID)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return ErrAdmissionPeriodOverlap
+	}
+	return nil
+}
+
+func (s *admissionPeriodService) Create(periodDTO dto.AdmissionPeriodDTO) (dto.AdmissionPeriodDTO, error) {
+	period := dto.AdmissionPeriodDTOToModel(periodDTO)
+	period.ID = 0
+
+	if err := s.checkPeriod(period); err != nil {
+		return dto.AdmissionPeriodDTO{}, err
+	}
+	if err := s.repo.Create(&period); err != nil {
+		logrus.WithError(err).WithField("academic_year", period.AcademicYear).Error("failed create admission period")
+		return dto.AdmissionPeriodDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":            period.ID,
+		"academic_year": period.AcademicYear,
+		"wave":          period.Wave,
+	}).Info("admission period created")
+	return dto.AdmissionPeriodModelToDTO(period), nil
+}
+
+func (s *admissionPeriodService) GetAll() ([]dto.AdmissionPeriodDTO, error) {
+	periods, err := s.repo.GetAll()
+	if err != nil {
+		logrus.WithError(err).Error("failed get admission periods")
+		return nil, err
+	}
+
+	out := make([]dto.AdmissionPeriodDTO, 0, len(periods))
+	for _, p := range periods {
+		d, err := s.withCount(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (s *admissionPeriodService) GetByID(id uint) (dto.AdmissionPeriodDTO, error) {
+	period, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.AdmissionPeriodDTO{}, ErrNotFoundAdmissionPeriod
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed get admission period")
+		return dto.AdmissionPeriodDTO{}, err
+	}
+	return s.withCount(period)
+}
+
+func (s *admissionPeriodService) Update(id uint, periodDTO dto.AdmissionPeriodDTO) (dto.AdmissionPeriodDTO, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.AdmissionPeriodDTO{}, ErrNotFoundAdmissionPeriod
+		}
+		return dto.AdmissionPeriodDTO{}, err
+	}
+
+	period := dto.AdmissionPeriodDTOToModel(periodDTO)
+	period.ID = existing.ID
+	period.CreatedAt = existing.CreatedAt
+
+	if err := s.checkPeriod(period); err != nil {
+		return dto.AdmissionPeriodDTO{}, err
+	}
+	// a raised period quota frees seats for the waitlist
+	promoted, err := s.quotaRepo.UpdatePeriod(period)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.AdmissionPeriodDTO{}, ErrNotFoundAdmissionPeriod
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed update admission period")
+		return dto.AdmissionPeriodDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{"id": id, "promoted": len(promoted)}).Info("admission period updated")
+	logPromoted(promoted)
+	return s.withCount(period)
+}
+
+func (s *admissionPeriodService) Delete(id uint) error {
+	count, err := s.repo.CountRegistrations(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAdmissionPeriodInUse
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundAdmissionPeriod
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed delete admission period")
+		return err
+	}
+	logrus.WithField("id", id).Info("admission period deleted")
+	return nil
+}
+
+func (s *admissionPeriodService) withCount(period models.AdmissionPeriod) (dto.AdmissionPeriodDTO, error) {
+	d := dto.AdmissionPeriodModelToDTO(period)
+	count, err := s.repo.CountRegistrations(period.ID)
+	if err != nil {
+		logrus.WithError(err).WithField("id", period.ID).Error("failed count period registrations")
+		return dto.AdmissionPeriodDTO{}, err
+	}
+	d.Registered = count
+	return d, nil
+}
+
+func (s *admissionPeriodService) GetQuotas(id uint) ([]dto.AdmissionQuotaDTO, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFoundAdmissionPeriod
+		}
+		return nil, err
+	}
+
+	quotas, err := s.quotaRepo.GetByPeriod(id)
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get admission quotas")
+		return nil, err
+	}
+	usage, err := s.quotaRepo.Usage(id)
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get admission quota usage")
+		return nil, err
+	}
+
+	out := make([]dto.AdmissionQuotaDTO, 0, len(quotas))
+	for _, q := range quotas {
+		d := dto.AdmissionQuotaModelToDTO(q)
+		for _, u := range usage {
+			if u.Gender == q.Gender && u.Program == q.Program {
+				d.Taken = u.Taken
+				d.Waitlisted = u.Waitlisted
+			}
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (s *admissionPeriodService) SetQuotas(id uint, quotas []dto.AdmissionQuotaDTO) ([]dto.AdmissionQuotaDTO, error) {
+	seen := map[string]bool{}
+	rows := make([]models.AdmissionQuota, 0, len(quotas))
+	for _, q := range quotas {
+		group := string(q.Gender) + "/" + string(q.Program)
+		if seen[group] {
+			return nil, ErrDuplicateQuota
+		}
+		seen[group] = true
+		rows = append(rows, dto.AdmissionQuotaDTOToModel(id, q))
+	}
+
+	promoted, err := s.quotaRepo.ReplaceForPeriod(id, rows)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFoundAdmissionPeriod
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed set admission quotas")
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"quotas":   len(quotas),
+		"promoted": len(promoted),
+	}).Info("admission quotas updated")
+	logPromoted(promoted)
+	return s.GetQuotas(id)
+}
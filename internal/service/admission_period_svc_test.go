@@ -0,0 +1,151 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fakePeriodRepo struct {
+	repository.AdmissionPeriodRepo
+	periods []models.AdmissionPeriod
+}
+
+func (f *fakePeriodRepo) Create(period *models.AdmissionPeriod) error {
+	period.ID = uint(len(f.periods) + 1)
+	f.periods = append(f.periods, *period)
+	return nil
+}
+
+func (f *fakePeriodRepo) GetByID(id uint) (models.AdmissionPeriod, error) {
+	for _, p := range f.periods {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return models.AdmissionPeriod{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakePeriodRepo) GetAll() ([]models.AdmissionPeriod, error) {
+	return f.periods, nil
+}
+
+func (f *fakePeriodRepo) GetOpen(now time.Time) (models.AdmissionPeriod, error) {
+	for _, p := range f.periods {
+		if p.IsOpen(now) {
+			return p, nil
+		}
+	}
+	return models.AdmissionPeriod{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakePeriodRepo) ExistsOverlapping(opensAt, closesAt time.Time, excludeID uint) (bool, error) {
+	for _, p := range f.periods {
+		if p.ID != excludeID && p.OpensAt.Before(closesAt) && p.ClosesAt.After(opensAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakePeriodRepo) CountRegistrations(id uint) (int64, error) {
+	return 0, nil
+}
+
+type fakeQuotaRepo struct {
+	repository.AdmissionQuotaRepo
+	updated  []models.AdmissionPeriod
+	promoted []models.Registration
+}
+
+func (f *fakeQuotaRepo) UpdatePeriod(period models.AdmissionPeriod) ([]models.Registration, error) {
+	f.updated = append(f.updated, period)
+	return f.promoted, nil
+}
+
+func TestValidAcademicYear(t *testing.T) {
+	tests := map[string]bool{
+		"2025/2026": true,
+		"2025/2027": false,
+		"2026/2025": false,
+		"2025-2026": false,
+		"25/26":     false,
+		"":          false,
+	}
+	for year, want := range tests {
+		if got := validAcademicYear(year); got != want {
+			t.Errorf("validAcademicYear(%q) = %v, want %v", year, got, want)
+		}
+	}
+}
+
+func TestAdmissionPeriodCreate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	svc := NewAdmissionPeriodService(&fakePeriodRepo{}, nil)
+
+	if _, err := svc.Create(dto.AdmissionPeriodDTO{AcademicYear: "2025/2026", Wave: 1, OpensAt: day(1), ClosesAt: day(10)}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		in   dto.AdmissionPeriodDTO
+		want error
+	}{
+		{"overlap", dto.AdmissionPeriodDTO{AcademicYear: "2025/2026", Wave: 2, OpensAt: day(9), ClosesAt: day(20)}, ErrAdmissionPeriodOverlap},
+		{"same wave", dto.AdmissionPeriodDTO{AcademicYear: "2025/2026", Wave: 1, OpensAt: day(20), ClosesAt: day(25)}, ErrAdmissionPeriodExists},
+		{"bad year", dto.AdmissionPeriodDTO{AcademicYear: "2025/2025", Wave: 2, OpensAt: day(20), ClosesAt: day(25)}, ErrInvalidAcademicYear},
+		{"adjacent", dto.AdmissionPeriodDTO{AcademicYear: "2025/2026", Wave: 2, OpensAt: day(10), ClosesAt: day(20)}, nil},
+	}
+	for _, tt := range tests {
+		if _, err := svc.Create(tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Create() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRegistrationClosedOutsidePeriod(t *testing.T) {
+	now := time.Now()
+	periods := &fakePeriodRepo{periods: []models.AdmissionPeriod{
+		{ID: 1, AcademicYear: "2024/2025", Wave: 1, OpensAt: now.AddDate(0, -3, 0), ClosesAt: now.AddDate(0, -1, 0)},
+		{ID: 2, AcademicYear: "2025/2026", Wave: 1, OpensAt: now.AddDate(0, 1, 0), ClosesAt: now.AddDate(0, 3, 0)},
+	}}
+
+	if _, err := NewAdmissionPeriodService(periods, nil).GetCurrent(); !errors.Is(err, ErrRegistrationClosed) {
+		t.Errorf("GetCurrent() error = %v, want %v", err, ErrRegistrationClosed)
+	}
+	regs := NewRegistrationService(nil, periods, nil, nil, RegistrationEditConfig{}, nil)
+	if _, err := regs.CreateRegistration(dto.RegistrationDTO{Email: "a@example.com"}); !errors.Is(err, ErrRegistrationClosed) {
+		t.Errorf("CreateRegistration() error = %v, want %v", err, ErrRegistrationClosed)
+	}
+}
+
+func TestAdmissionPeriodUpdatePromotes(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	periods := &fakePeriodRepo{periods: []models.AdmissionPeriod{
+		{ID: 1, AcademicYear: "2025/2026", Wave: 1, Quota: 10, OpensAt: day(1), ClosesAt: day(10)},
+	}}
+	quotas := &fakeQuotaRepo{promoted: []models.Registration{{ID: 5, Status: models.RegistrationStatusNew}}}
+	svc := NewAdmissionPeriodService(periods, quotas)
+
+	got, err := svc.Update(1, dto.AdmissionPeriodDTO{AcademicYear: "2025/2026", Wave: 1, Quota: 12, OpensAt: day(1), ClosesAt: day(10)})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got.Quota != 12 {
+		t.Errorf("Update() quota = %d, want 12", got.Quota)
+	}
+	// the quota repo saves the period, so the raised quota is applied to the waitlist
+	if len(quotas.updated) != 1 || quotas.updated[0].ID != 1 || quotas.updated[0].Quota != 12 {
+		t.Errorf("UpdatePeriod() calls = %+v, want period 1 with quota 12", quotas.updated)
+	}
+
+	if _, err := svc.Update(2, dto.AdmissionPeriodDTO{AcademicYear: "2025/2026", Wave: 2, OpensAt: day(20), ClosesAt: day(25)}); !errors.Is(err, ErrNotFoundAdmissionPeriod) {
+		t.Errorf("Update() of a missing period error = %v, want %v", err, ErrNotFoundAdmissionPeriod)
+	}
+}
@@ -3,26 +3,55 @@ package service
 import (
 	"context"
 	"darulabror/internal/dto"
+	"darulabror/internal/models"
 	"darulabror/internal/repository"
+	"darulabror/internal/utils"
 	"errors"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+// PublishedArticlePage is a page of published articles with its HTTP validators.
+type PublishedArticlePage struct {
+	Items        []dto.ArticleDTO
+	Page         utils.PageInfo
+	ETag         string
+	LastModified time.Time
+}
+
+// PublishedArticle is a published article with its HTTP validators.
+type PublishedArticle struct {
+	Article      dto.ArticleDTO
+	ETag         string
+	LastModified time.Time
+}
+
 type ArticleService interface {
-	// Public
-	GetPublishedArticles(page, limit int) ([]dto.ArticleDTO, int64, error)
-	GetPublishedArticleByID(id uint) (dto.ArticleDTO, error)
+	// Public (the article reads are cached by the ArticleRepo decorator); untranslated articles
+	// fall back to the default locale
+	GetPublishedArticles(q utils.ListQuery, locale models.Locale) (PublishedArticlePage, error)
+	GetPublishedArticleByID(id uint, locale models.Locale) (PublishedArticle, error)
+	// GetPublishedArticleBySlug finds the article of a slug of any locale; an empty locale
+	// returns the locale the slug belongs to.
+	GetPublishedArticleBySlug(slug string, locale models.Locale) (PublishedArticle, error)
 
 	// Admin
-	CreateArticle(articleDTO dto.ArticleDTO) error
-	GetAllArticles(page, limit int) ([]dto.ArticleDTO, int64, error)
-	UpdateArticle(id uint, articleDTO dto.ArticleDTO) error
+	// canPublish (articles.publish) is needed to create a published article or to move one
+	// in or out of published
+	CreateArticle(articleDTO dto.ArticleDTO, canPublish bool) error
+	GetAllArticles(q utils.ListQuery) ([]dto.ArticleDTO, utils.PageInfo, error)
+	UpdateArticle(id uint, articleDTO dto.ArticleDTO, canPublish bool) error
 	DeleteArticle(id uint) error
 
+	// Admin translations (locales other than the default)
+	GetTranslations(id uint) ([]dto.ArticleTranslationDTO, error)
+	SaveTranslation(id uint, locale string, in dto.ArticleTranslationInputDTO) (dto.ArticleTranslationDTO, error)
+	DeleteTranslation(id uint, locale string) error
+
 	// ======================
 	//  METHODS FOR GCS
 	// ======================
@@ -32,20 +61,42 @@ type ArticleService interface {
 
 type articleService struct {
 	repo         repository.ArticleRepo
+	translations repository.ArticleTranslationRepo
 	privateStore repository.GCPStorageRepo
+
+	// changedAt (unix seconds) is the last create/update/delete seen by this instance;
+	// lists are never older than it, so deletes and unpublishes invalidate If-Modified-Since.
+	changedAt atomic.Int64
 }
 
-func NewArticleService(repo repository.ArticleRepo, privateStore repository.GCPStorageRepo) ArticleService {
-	return &articleService{
+func NewArticleService(repo repository.ArticleRepo, translations repository.ArticleTranslationRepo, privateStore repository.GCPStorageRepo) ArticleService {
+	s := &articleService{
 		repo:         repo,
+		translations: translations,
 		privateStore: privateStore,
 	}
+	s.changedAt.Store(time.Now().Unix())
+	return s
 }
 
-func (s *articleService) CreateArticle(articleDTO dto.ArticleDTO) error {
+// invalidate moves the Last-Modified of public lists forward after a write; the cached
+// reads are dropped by the ArticleRepo decorator.
+func (s *articleService) invalidate() {
+	s.changedAt.Store(time.Now().Unix())
+}
+
+func (s *articleService) CreateArticle(articleDTO dto.ArticleDTO, canPublish bool) error {
 	if articleDTO.Status == "" {
 		articleDTO.Status = "draft"
 	}
+	if articleDTO.Status == "published" && !canPublish {
+		return ErrArticlePublishForbidden
+	}
+	slug, err := s.resolveSlug(articleDTO.Slug, articleDTO.Title, 0, models.DefaultLocale)
+	if err != nil {
+		return err
+	}
+	articleDTO.Slug = slug
 
 	article, err := dto.ArticleDTOToModel(articleDTO)
 	if err != nil {
@@ -58,55 +109,119 @@ func (s *articleService) CreateArticle(articleDTO dto.ArticleDTO) error {
 		return ErrCreateArticle
 	}
 
+	s.invalidate()
 	logrus.WithField("title", article.Title).Info("article created")
 	return nil
 }
 
-func (s *articleService) GetAllArticles(page, limit int) ([]dto.ArticleDTO, int64, error) {
-	articles, total, err := s.repo.GetAll(page, limit)
+func (s *articleService) GetAllArticles(q utils.ListQuery) ([]dto.ArticleDTO, utils.PageInfo, error) {
+	articles, page, err := s.repo.GetAll(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed get all articles")
-		return nil, 0, err
+		return nil, utils.PageInfo{}, err
 	}
 
+	ts, err := s.translations.GetByArticleIDs(articleIDs(articles))
+	if err != nil {
+		logrus.WithError(err).Error("failed get article translations")
+		return nil, utils.PageInfo{}, err
+	}
+	byArticle := groupTranslations(ts)
+
 	out := make([]dto.ArticleDTO, 0, len(articles))
 	for _, a := range articles {
-		out = append(out, dto.ArticleModelToDTO(a))
+		d := dto.ArticleModelToDTO(a)
+		d.Translations = translationStatuses(a, byArticle[a.ID])
+		out = append(out, d)
+	}
+	return out, page, nil
+}
+
+func articleIDs(articles []models.Article) []uint {
+	ids := make([]uint, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
 	}
-	return out, total, nil
+	return ids
 }
 
-func (s *articleService) GetPublishedArticles(page, limit int) ([]dto.ArticleDTO, int64, error) {
-	articles, total, err := s.repo.GetPublished(page, limit)
+func (s *articleService) GetPublishedArticles(q utils.ListQuery, locale models.Locale) (PublishedArticlePage, error) {
+	articles, page, err := s.repo.GetPublished(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed get published articles")
-		return nil, 0, err
+		return PublishedArticlePage{}, err
 	}
 
-	out := make([]dto.ArticleDTO, 0, len(articles))
+	ts, err := s.translations.GetByArticleIDs(articleIDs(articles))
+	if err != nil {
+		logrus.WithError(err).Error("failed get article translations")
+		return PublishedArticlePage{}, err
+	}
+	byArticle := groupTranslations(ts)
+
+	out := PublishedArticlePage{Items: make([]dto.ArticleDTO, 0, len(articles)), Page: page}
+	lastModified := s.changedAt.Load()
 	for _, a := range articles {
-		out = append(out, dto.ArticleModelToDTO(a))
+		d, updatedAt := localizeArticle(a, byArticle[a.ID], locale)
+		out.Items = append(out.Items, d)
+		lastModified = max(lastModified, updatedAt)
+	}
+	out.LastModified = time.Unix(lastModified, 0)
+	if out.ETag, err = utils.ETag(struct {
+		Items []dto.ArticleDTO
+		Page  utils.PageInfo
+	}{out.Items, out.Page}); err != nil {
+		return PublishedArticlePage{}, err
+	}
+
+	return out, nil
+}
+
+func (s *articleService) GetPublishedArticleBySlug(slug string, locale models.Locale) (PublishedArticle, error) {
+	id, slugLocale, err := s.translations.SlugOwner(slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return PublishedArticle{}, ErrNotFoundArticle
+		}
+		logrus.WithError(err).WithField("slug", slug).Error("failed find article by slug")
+		return PublishedArticle{}, err
+	}
+	if locale == "" {
+		locale = slugLocale
 	}
-	return out, total, nil
+	return s.GetPublishedArticleByID(id, locale)
 }
 
-func (s *articleService) GetPublishedArticleByID(id uint) (dto.ArticleDTO, error) {
+func (s *articleService) GetPublishedArticleByID(id uint, locale models.Locale) (PublishedArticle, error) {
 	article, err := s.repo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.ArticleDTO{}, ErrNotFoundArticle
+			return PublishedArticle{}, ErrNotFoundArticle
 		}
 		logrus.WithError(err).WithField("id", id).Error("failed get article by id")
-		return dto.ArticleDTO{}, err
+		return PublishedArticle{}, err
 	}
 
 	if article.Status != "published" {
-		return dto.ArticleDTO{}, ErrNotFoundArticle
+		return PublishedArticle{}, ErrNotFoundArticle
 	}
-	return dto.ArticleModelToDTO(article), nil
+
+	ts, err := s.translations.GetByArticleIDs([]uint{id})
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get article translations")
+		return PublishedArticle{}, err
+	}
+
+	d, updatedAt := localizeArticle(article, ts, locale)
+	out := PublishedArticle{Article: d, LastModified: time.Unix(updatedAt, 0)}
+	if out.ETag, err = utils.ETag(out.Article); err != nil {
+		return PublishedArticle{}, err
+	}
+
+	return out, nil
 }
 
-func (s *articleService) UpdateArticle(id uint, articleDTO dto.ArticleDTO) error {
+func (s *articleService) UpdateArticle(id uint, articleDTO dto.ArticleDTO, canPublish bool) error {
 	article, err := s.repo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -114,7 +229,21 @@ func (s *articleService) UpdateArticle(id uint, articleDTO dto.ArticleDTO) error
 		}
 		return err
 	}
+	if articleDTO.Status != "" && articleDTO.Status != article.Status &&
+		(articleDTO.Status == "published" || article.Status == "published") && !canPublish {
+		return ErrArticlePublishForbidden
+	}
 
+	if articleDTO.Slug != "" {
+		// keep the current slug (and links to it) unless a new one is given
+		if article.Slug, err = s.resolveSlug(articleDTO.Slug, articleDTO.Title, id, models.DefaultLocale); err != nil {
+			return err
+		}
+	} else if article.Slug == "" {
+		if article.Slug, err = s.resolveSlug("", articleDTO.Title, id, models.DefaultLocale); err != nil {
+			return err
+		}
+	}
 	article.Title = articleDTO.Title
 	article.PhotoHeader = articleDTO.PhotoHeader
 	article.Content = articleDTO.Content
@@ -128,6 +257,7 @@ func (s *articleService) UpdateArticle(id uint, articleDTO dto.ArticleDTO) error
 		return ErrUpdateArticle
 	}
 
+	s.invalidate()
 	logrus.WithField("id", id).Info("article updated")
 	return nil
 }
@@ -137,6 +267,7 @@ func (s *articleService) DeleteArticle(id uint) error {
 		logrus.WithError(err).WithField("id", id).Error("failed delete article")
 		return err
 	}
+	s.invalidate()
 	logrus.WithField("id", id).Info("article deleted")
 	return nil
 }
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"crypto/sha256"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const maxSlugRunes = 100
+
+// slugify lower-cases s and joins its letters and digits with hyphens.
+// Non-Latin letters are kept, so Arabic titles give Arabic slugs.
+func slugify(s string) string {
+	var b strings.Builder
+	n, gap := 0, false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining marks (e.g. Arabic harakat) are dropped
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if n == maxSlugRunes {
+				return b.String()
+			}
+			if gap && n > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			n++
+			gap = false
+		default:
+			gap = true
+		}
+	}
+	return b.String()
+}
+
+// articleSourceHash fingerprints the default-locale text a translation is made from.
+func articleSourceHash(title string, content []byte) string {
+	h := sha256.New()
+	h.Write([]byte(title))
+	h.Write([]byte{0})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func translationStatus(t models.ArticleTranslation, article models.Article) string {
+	if t.SourceHash != articleSourceHash(article.Title, article.Content) {
+		return models.TranslationOutdated
+	}
+	return models.TranslationUpToDate
+}
+
+// groupTranslations indexes translations by article id.
+func groupTranslations(ts []models.ArticleTranslation) map[uint][]models.ArticleTranslation {
+	out := make(map[uint][]models.ArticleTranslation)
+	for _, t := range ts {
+		out[t.ArticleID] = append(out[t.ArticleID], t)
+	}
+	return out
+}
+
+// localizeArticle converts article in locale, falling back to the default locale when it is not translated.
+// It returns the DTO and the last change of the returned text (unix seconds).
+func localizeArticle(article models.Article, ts []models.ArticleTranslation, locale models.Locale) (dto.ArticleDTO, int64) {
+	out := dto.ArticleModelToDTO(article)
+	out.Locale = string(models.DefaultLocale)
+	updatedAt := article.UpdatedAt
+
+	available := map[models.Locale]bool{models.DefaultLocale: true}
+	for _, t := range ts {
+		available[t.Locale] = true
+		if t.Locale == locale {
+			out.Title, out.Slug, out.Content = t.Title, t.Slug, t.Content
+			out.Locale = string(t.Locale)
+			updatedAt = max(updatedAt, t.UpdatedAt)
+		}
+	}
+	for _, l := range models.Locales {
+		if available[l] {
+			out.AvailableLocales = append(out.AvailableLocales, string(l))
+		}
+	}
+	out.Dir = models.Locale(out.Locale).Dir()
+	return out, updatedAt
+}
+
+// translationStatuses is the admin overview of the non-default locales of article.
+func translationStatuses(article models.Article, ts []models.ArticleTranslation) map[string]string {
+	out := make(map[string]string, len(models.Locales)-1)
+	for _, l := range models.Locales[1:] {
+		out[string(l)] = models.TranslationMissing
+	}
+	for _, t := range ts {
+		out[string(t.Locale)] = translationStatus(t, article)
+	}
+	return out
+}
+
+// resolveSlug normalizes the requested slug (or the title when empty) and makes sure no other
+// article or translation uses it. Generated slugs get a -2, -3... suffix instead of failing.
+func (s *articleService) resolveSlug(requested, title string, articleID uint, locale models.Locale) (string, error) {
+	base := slugify(requested)
+	generated := requested == ""
+	if generated {
+		base = slugify(title)
+		if base == "" {
+			base = "article"
+		}
+	} else if base == "" {
+		return "", ErrInvalidArticleSlug
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		ownerID, ownerLocale, err := s.translations.SlugOwner(slug)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && ownerID == articleID && ownerLocale == locale) {
+			return slug, nil
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("slug", slug).Error("failed check article slug")
+			return "", err
+		}
+		if !generated || i > 50 {
+			return "", ErrArticleSlugExists
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// translationLocale validates the locale of a translation endpoint.
+func translationLocale(locale string) (models.Locale, error) {
+	l := models.Locale(locale)
+	if !l.Valid() || l == models.DefaultLocale {
+		return "", ErrInvalidTranslationLocale
+	}
+	return l, nil
+}
+
+func (s *articleService) getArticle(id uint) (models.Article, error) {
+	article, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Article{}, ErrNotFoundArticle
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed get article by id")
+		return models.Article{}, err
+	}
+	return article, nil
+}
+
+func (s *articleService) GetTranslations(id uint) ([]dto.ArticleTranslationDTO, error) {
+	article, err := s.getArticle(id)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := s.translations.GetByArticleIDs([]uint{id})
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get article translations")
+		return nil, err
+	}
+
+	byLocale := make(map[models.Locale]models.ArticleTranslation, len(ts))
+	for _, t := range ts {
+		byLocale[t.Locale] = t
+	}
+	out := make([]dto.ArticleTranslationDTO, 0, len(models.Locales)-1)
+	for _, l := range models.Locales[1:] {
+		t, ok := byLocale[l]
+		if !ok {
+			out = append(out, dto.ArticleTranslationDTO{Locale: string(l), Dir: l.Dir(), Status: models.TranslationMissing})
+			continue
+		}
+		out = append(out, dto.ArticleTranslationModelToDTO(t, translationStatus(t, article)))
+	}
+	return out, nil
+}
+
+func (s *articleService) SaveTranslation(id uint, locale string, in dto.ArticleTranslationInputDTO) (dto.ArticleTranslationDTO, error) {
+	l, err := translationLocale(locale)
+	if err != nil {
+		return dto.ArticleTranslationDTO{}, err
+	}
+	article, err := s.getArticle(id)
+	if err != nil {
+		return dto.ArticleTranslationDTO{}, err
+	}
+
+	slug, err := s.resolveSlug(in.Slug, in.Title, id, l)
+	if err != nil {
+		return dto.ArticleTranslationDTO{}, err
+	}
+	t := models.ArticleTranslation{
+		ArticleID:  id,
+		Locale:     l,
+		Title:      in.Title,
+		Slug:       slug,
+		Content:    in.Content,
+		SourceHash: articleSourceHash(article.Title, article.Content),
+	}
+	if err := s.translations.Save(&t); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{"id": id, "locale": l}).Error("failed save article translation")
+		return dto.ArticleTranslationDTO{}, err
+	}
+
+	s.invalidate()
+	logrus.WithFields(logrus.Fields{"id": id, "locale": l}).Info("article translation saved")
+	return dto.ArticleTranslationModelToDTO(t, models.TranslationUpToDate), nil
+}
+
+func (s *articleService) DeleteTranslation(id uint, locale string) error {
+	l, err := translationLocale(locale)
+	if err != nil {
+		return err
+	}
+	if err := s.translations.Delete(id, l); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundTranslation
+		}
+		logrus.WithError(err).WithFields(logrus.Fields{"id": id, "locale": l}).Error("failed delete article translation")
+		return err
+	}
+
+	s.invalidate()
+	logrus.WithFields(logrus.Fields{"id": id, "locale": l}).Info("article translation deleted")
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"darulabror/internal/models"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	for in, want := range map[string]string{
+		"Penerimaan Santri Baru 2025/2026": "penerimaan-santri-baru-2025-2026",
+		"  --Hello,   World!-- ":           "hello-world",
+		"القُرْآن الكريم":                  "القرآن-الكريم",
+		"!!!":                              "",
+	} {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocalizeArticle(t *testing.T) {
+	article := models.Article{ID: 1, Title: "Judul", Slug: "judul", Content: []byte(`{"a":1}`), UpdatedAt: 100}
+	ts := []models.ArticleTranslation{{ArticleID: 1, Locale: models.LocaleArabic, Title: "عنوان", Slug: "عنوان", Content: []byte(`{"a":2}`), UpdatedAt: 200}}
+
+	ar, updatedAt := localizeArticle(article, ts, models.LocaleArabic)
+	if ar.Title != "عنوان" || ar.Locale != "ar" || ar.Dir != "rtl" || updatedAt != 200 {
+		t.Errorf("ar = %+v, updatedAt %d", ar, updatedAt)
+	}
+	if want := []string{"id", "ar"}; !reflect.DeepEqual(ar.AvailableLocales, want) {
+		t.Errorf("available_locales = %v, want %v", ar.AvailableLocales, want)
+	}
+
+	// English is not translated: Indonesian is returned and says so
+	en, updatedAt := localizeArticle(article, ts, models.LocaleEnglish)
+	if en.Title != "Judul" || en.Locale != "id" || en.Dir != "ltr" || updatedAt != 100 {
+		t.Errorf("en fallback = %+v, updatedAt %d", en, updatedAt)
+	}
+}
+
+func TestTranslationStatuses(t *testing.T) {
+	article := models.Article{Title: "Judul", Content: []byte(`{"a":1}`)}
+	ts := []models.ArticleTranslation{{Locale: models.LocaleEnglish, SourceHash: articleSourceHash("Judul", []byte(`{"a":1}`))}}
+
+	got := translationStatuses(article, ts)
+	if got["en"] != models.TranslationUpToDate || got["ar"] != models.TranslationMissing {
+		t.Errorf("statuses = %v", got)
+	}
+
+	article.Title = "Judul baru"
+	if got := translationStatuses(article, ts); got["en"] != models.TranslationOutdated {
+		t.Errorf("after editing the article: en = %q, want outdated", got["en"])
+	}
+}
+
+func TestResolveSlug(t *testing.T) {
+	s := &articleService{translations: &fakeTranslationRepo{translations: []models.ArticleTranslation{
+		{ArticleID: 1, Locale: models.LocaleIndonesian, Slug: "info-psb"},
+		{ArticleID: 1, Locale: models.LocaleEnglish, Slug: "info-psb-2"},
+	}}}
+
+	if got, _ := s.resolveSlug("", "Info PSB", 2, models.DefaultLocale); got != "info-psb-3" {
+		t.Errorf("generated slug = %q, want info-psb-3", got)
+	}
+	if _, err := s.resolveSlug("Info PSB", "x", 2, models.DefaultLocale); !errors.Is(err, ErrArticleSlugExists) {
+		t.Errorf("taken slug error = %v, want %v", err, ErrArticleSlugExists)
+	}
+	if got, err := s.resolveSlug("info-psb-2", "x", 1, models.LocaleEnglish); err != nil || got != "info-psb-2" {
+		t.Errorf("own slug = %q, %v", got, err)
+	}
+	if _, err := s.resolveSlug("!!!", "x", 1, models.DefaultLocale); !errors.Is(err, ErrInvalidArticleSlug) {
+		t.Errorf("empty slug error = %v, want %v", err, ErrInvalidArticleSlug)
+	}
+}
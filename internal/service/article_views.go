@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"darulabror/internal/dto"
+	"darulabror/internal/repository"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultViewFlushInterval = 30 * time.Second
+	defaultViewDedupWindow   = 30 * time.Minute
+	defaultViewBufferSize    = 1024
+
+	defaultPopularWindowDays = 7
+	maxPopularWindowDays     = 90
+	defaultPopularLimit      = 10
+	maxPopularLimit          = 50
+)
+
+// ArticleViewConfig tunes the view recorder; zero values use the defaults.
+type ArticleViewConfig struct {
+	FlushInterval time.Duration // how often buffered views are written (default 30s)
+	DedupWindow   time.Duration // repeat views of one visitor within it count once (default 30m)
+	BufferSize    int           // views waiting for the writer; more are dropped (default 1024)
+}
+
+type ArticleViewService interface {
+	// Record counts a view of a published article without blocking the request.
+	// Bots, repeat views within the dedup window and views while the buffer is full are ignored.
+	Record(articleID uint, ip, userAgent string)
+	// Popular ranks published articles by views in the last window days ("7d"; empty = 7d, max 90d).
+	Popular(window string, limit int) ([]dto.PopularArticleDTO, error)
+	// Analytics returns the views of an article per day of [from, to] (YYYY-MM-DD; default last 30 days).
+	Analytics(articleID uint, from, to string) (dto.ArticleAnalyticsDTO, error)
+	// Close writes the buffered views and stops the recorder.
+	Close(ctx context.Context) error
+}
+
+type articleView struct {
+	articleID uint
+	visitor   string
+	at        time.Time
+}
+
+type viewerKey struct {
+	articleID uint
+	visitor   string
+}
+
+type articleDay struct {
+	articleID uint
+	day       string
+}
+
+type articleViewService struct {
+	repo        repository.ArticleViewRepo
+	articleRepo repository.ArticleRepo
+	cfg         ArticleViewConfig
+
+	views     chan articleView
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	// owned by the writer goroutine
+	seen    map[viewerKey]time.Time
+	pending map[articleDay]int64
+}
+
+// NewArticleViewService starts the background writer; call Close on shutdown to keep buffered views.
+func NewArticleViewService(repo repository.ArticleViewRepo, articleRepo repository.ArticleRepo, cfg ArticleViewConfig) ArticleViewService {
+	s := newArticleViewService(repo, articleRepo, cfg)
+	go s.run()
+	return s
+}
+
+func newArticleViewService(repo repository.ArticleViewRepo, articleRepo repository.ArticleRepo, cfg ArticleViewConfig) *articleViewService {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultViewFlushInterval
+	}
+	if cfg.DedupWindow <= 0 {
+		cfg.DedupWindow = defaultViewDedupWindow
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultViewBufferSize
+	}
+	return &articleViewService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		cfg:         cfg,
+		views:       make(chan articleView, cfg.BufferSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		seen:        make(map[viewerKey]time.Time),
+		pending:     make(map[articleDay]int64),
+	}
+}
+
+// botUserAgentMarkers are lower-case fragments of crawler, link preview and HTTP client user agents.
+var botUserAgentMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "facebookexternalhit", "embedly", "preview",
+	"headless", "lighthouse", "curl/", "wget/", "python-", "go-http-client", "java/",
+	"okhttp", "axios/", "node-fetch", "postman",
+}
+
+func isBotUserAgent(ua string) bool {
+	ua = strings.ToLower(strings.TrimSpace(ua))
+	if ua == "" {
+		return true
+	}
+	for _, m := range botUserAgentMarkers {
+		if strings.Contains(ua, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// visitorKey identifies a visitor for deduplication without keeping the IP address around.
+func visitorKey(ip, userAgent string) string {
+	sum := sha256.Sum256([]byte(ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (s *articleViewService) Record(articleID uint, ip, userAgent string) {
+	if isBotUserAgent(userAgent) {
+		return
+	}
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+
+	v := articleView{articleID: articleID, visitor: visitorKey(ip, userAgent), at: time.Now()}
+	select {
+	case s.views <- v:
+	default:
+		logrus.WithField("article_id", articleID).Warn("article view buffer full, view dropped")
+	}
+}
+
+func (s *articleViewService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case v := <-s.views:
+			s.add(v)
+		case now := <-ticker.C:
+			s.flush()
+			s.prune(now)
+		case <-s.stop:
+			for {
+				select {
+				case v := <-s.views:
+					s.add(v)
+				default:
+					s.closeErr = s.flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// add counts v unless the same visitor viewed the article within the dedup window.
+func (s *articleViewService) add(v articleView) {
+	key := viewerKey{articleID: v.articleID, visitor: v.visitor}
+	if last, ok := s.seen[key]; ok && v.at.Sub(last) < s.cfg.DedupWindow {
+		return
+	}
+	s.seen[key] = v.at
+	s.pending[articleDay{articleID: v.articleID, day: v.at.In(exportLocation).Format(dateLayoutISO)}]++
+}
+
+// prune forgets visitors whose dedup window has passed.
+func (s *articleViewService) prune(now time.Time) {
+	for k, at := range s.seen {
+		if now.Sub(at) >= s.cfg.DedupWindow {
+			delete(s.seen, k)
+		}
+	}
+}
+
+// flush writes the pending counts; on failure they are kept for the next flush.
+func (s *articleViewService) flush() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	counts := make([]repository.ArticleViewCount, 0, len(s.pending))
+	for k, n := range s.pending {
+		counts = append(counts, repository.ArticleViewCount{ArticleID: k.articleID, Day: k.day, Views: n})
+	}
+	if err := s.repo.AddViews(counts); err != nil {
+		logrus.WithError(err).WithField("rows", len(counts)).Error("failed write article views")
+		return err
+	}
+	s.pending = make(map[articleDay]int64)
+	return nil
+}
+
+func (s *articleViewService) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return s.closeErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseViewWindow parses a popular window like "7d" into a number of days.
+func parseViewWindow(window string) (int, error) {
+	if window == "" {
+		return defaultPopularWindowDays, nil
+	}
+	if !strings.HasSuffix(window, "d") {
+		return 0, ErrInvalidViewWindow
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(window, "d"))
+	if err != nil || days < 1 || days > maxPopularWindowDays {
+		return 0, ErrInvalidViewWindow
+	}
+	return days, nil
+}
+
+func (s *articleViewService) Popular(window string, limit int) ([]dto.PopularArticleDTO, error) {
+	days, err := parseViewWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultPopularLimit
+	}
+	if limit > maxPopularLimit {
+		limit = maxPopularLimit
+	}
+
+	// the window includes today, so 1d means today only
+	since := time.Now().In(exportLocation).AddDate(0, 0, -(days - 1)).Format(dateLayoutISO)
+	rows, err := s.repo.Popular(since, limit)
+	if err != nil {
+		logrus.WithError(err).WithField("window", window).Error("failed get popular articles")
+		return nil, err
+	}
+
+	out := make([]dto.PopularArticleDTO, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.PopularArticleDTO{
+			ID:          r.ID,
+			Title:       r.Title,
+			Author:      r.Author,
+			PhotoHeader: r.PhotoHeader,
+			CreatedAt:   r.CreatedAt,
+			Views:       r.Views,
+		})
+	}
+	return out, nil
+}
+
+func (s *articleViewService) Analytics(articleID uint, fromStr, toStr string) (dto.ArticleAnalyticsDTO, error) {
+	from, to, err := dashboardRange(fromStr, toStr, time.Now())
+	if err != nil {
+		return dto.ArticleAnalyticsDTO{}, err
+	}
+
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ArticleAnalyticsDTO{}, ErrNotFoundArticle
+		}
+		logrus.WithError(err).WithField("id", articleID).Error("failed get article by id")
+		return dto.ArticleAnalyticsDTO{}, err
+	}
+
+	total, err := s.repo.TotalViews(articleID)
+	if err != nil {
+		logrus.WithError(err).WithField("id", articleID).Error("failed count article views")
+		return dto.ArticleAnalyticsDTO{}, err
+	}
+	daily, err := s.repo.DailyViews(articleID, from.Format(dateLayoutISO), to.Format(dateLayoutISO))
+	if err != nil {
+		logrus.WithError(err).WithField("id", articleID).Error("failed get daily article views")
+		return dto.ArticleAnalyticsDTO{}, err
+	}
+
+	out := dto.ArticleAnalyticsDTO{
+		ArticleID:  article.ID,
+		Title:      article.Title,
+		Status:     article.Status,
+		TotalViews: total,
+		From:       from.Format(dateLayoutISO),
+		To:         to.Format(dateLayoutISO),
+		Daily:      fillDaily(daily, from, to),
+	}
+	for _, d := range daily {
+		out.RangeViews += d.Count
+	}
+	return out, nil
+}
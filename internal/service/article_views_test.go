@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/repository"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeArticleViewRepo struct {
+	repository.ArticleViewRepo
+	mu     sync.Mutex
+	counts []repository.ArticleViewCount
+}
+
+func (r *fakeArticleViewRepo) AddViews(counts []repository.ArticleViewCount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts = append(r.counts, counts...)
+	return nil
+}
+
+func TestIsBotUserAgent(t *testing.T) {
+	for ua, want := range map[string]bool{
+		"": true,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": true,
+		"facebookexternalhit/1.1": true,
+		"curl/8.5.0":              true,
+		"WhatsApp/2.23.20.0 A":    false,
+		"Mozilla/5.0 (Linux; Android 14; SM-A155F) AppleWebKit/537.36 Chrome/124.0 Mobile Safari": false,
+	} {
+		if got := isBotUserAgent(ua); got != want {
+			t.Errorf("isBotUserAgent(%q) = %v, want %v", ua, got, want)
+		}
+	}
+}
+
+func TestParseViewWindow(t *testing.T) {
+	for window, want := range map[string]int{"": 7, "1d": 1, "30d": 30, "90d": 90} {
+		if got, err := parseViewWindow(window); err != nil || got != want {
+			t.Errorf("parseViewWindow(%q) = %d, %v, want %d", window, got, err, want)
+		}
+	}
+	for _, window := range []string{"0d", "91d", "7", "1w", "-3d"} {
+		if _, err := parseViewWindow(window); !errors.Is(err, ErrInvalidViewWindow) {
+			t.Errorf("parseViewWindow(%q) error = %v, want %v", window, err, ErrInvalidViewWindow)
+		}
+	}
+}
+
+func TestArticleViewDedup(t *testing.T) {
+	s := newArticleViewService(&fakeArticleViewRepo{}, nil, ArticleViewConfig{DedupWindow: 30 * time.Minute})
+	// 2025-01-30 23:50 WIB
+	at := time.Date(2025, 1, 30, 16, 50, 0, 0, time.UTC)
+
+	s.add(articleView{articleID: 1, visitor: "a", at: at})
+	s.add(articleView{articleID: 1, visitor: "a", at: at.Add(10 * time.Minute)}) // repeat, next day in WIB
+	s.add(articleView{articleID: 1, visitor: "b", at: at.Add(10 * time.Minute)})
+	s.add(articleView{articleID: 2, visitor: "a", at: at})
+	s.add(articleView{articleID: 1, visitor: "a", at: at.Add(40 * time.Minute)})
+
+	want := map[articleDay]int64{
+		{articleID: 1, day: "2025-01-30"}: 1,
+		{articleID: 1, day: "2025-01-31"}: 2,
+		{articleID: 2, day: "2025-01-30"}: 1,
+	}
+	if len(s.pending) != len(want) {
+		t.Fatalf("pending = %v, want %v", s.pending, want)
+	}
+	for k, n := range want {
+		if s.pending[k] != n {
+			t.Errorf("pending[%v] = %d, want %d", k, s.pending[k], n)
+		}
+	}
+
+	s.prune(at.Add(45 * time.Minute))
+	if len(s.seen) != 1 {
+		t.Errorf("seen after prune = %v, want only the view at +40m", s.seen)
+	}
+}
+
+func TestArticleViewCloseFlushes(t *testing.T) {
+	repo := &fakeArticleViewRepo{}
+	s := NewArticleViewService(repo, nil, ArticleViewConfig{FlushInterval: time.Hour})
+
+	s.Record(7, "10.0.0.1", "Mozilla/5.0 (iPhone) Safari")
+	s.Record(7, "10.0.0.1", "Mozilla/5.0 (iPhone) Safari")
+	s.Record(7, "10.0.0.2", "Googlebot/2.1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(repo.counts) != 1 || repo.counts[0].ArticleID != 7 || repo.counts[0].Views != 1 {
+		t.Errorf("written counts = %+v, want one view of article 7", repo.counts)
+	}
+	s.Record(7, "10.0.0.3", "Mozilla/5.0") // after Close: ignored, must not block or panic
+}
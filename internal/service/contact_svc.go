@@ -3,6 +3,7 @@ package service
 import (
 	"darulabror/internal/models"
 	"darulabror/internal/repository"
+	"darulabror/internal/utils"
 	"errors"
 
 	"github.com/sirupsen/logrus"
@@ -14,7 +15,7 @@ type ContactService interface {
 	CreateContact(email, subject, message string) error
 
 	// Admin
-	GetAllContacts(page, limit int, status string) ([]models.Contact, int64, error)
+	GetAllContacts(q utils.ListQuery) ([]models.Contact, utils.PageInfo, error)
 	GetContactByID(id uint) (*models.Contact, error)
 	UpdateContact(id uint, email, subject, message string) error
 	UpdateContactStatus(id uint, status models.ContactStatus) error
@@ -45,20 +46,20 @@ func (s *contactService) CreateContact(email, subject, message string) error {
 	return nil
 }
 
-func (s *contactService) GetAllContacts(page, limit int, status string) ([]models.Contact, int64, error) {
-	contacts, total, err := s.repo.GetAllContacts(page, limit, status)
+func (s *contactService) GetAllContacts(q utils.ListQuery) ([]models.Contact, utils.PageInfo, error) {
+	contacts, page, err := s.repo.GetAllContacts(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed get all contacts")
-		return nil, 0, err
+		return nil, utils.PageInfo{}, err
 	}
-	return contacts, total, nil
+	return contacts, page, nil
 }
 
 func (s *contactService) GetContactByID(id uint) (*models.Contact, error) {
 	contact, err := s.repo.GetContactByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("contact not found")
+			return nil, ErrNotFoundContact
 		}
 		logrus.WithError(err).WithField("id", id).Error("failed get contact by id")
 		return nil, err
@@ -87,12 +88,12 @@ func (s *contactService) DeleteContact(id uint) error {
 func (s *contactService) UpdateContactStatus(id uint, status models.ContactStatus) error {
 	// Validate status value
 	if status != models.ContactStatusNew && status != models.ContactStatusInProgress && status != models.ContactStatusDone {
-		return errors.New("invalid status value")
+		return ErrInvalidContactStatus
 	}
 	
 	if err := s.repo.UpdateContactStatus(id, status); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("contact not found")
+			return ErrNotFoundContact
 		}
 		logrus.WithError(err).WithField("id", id).Error("failed update contact status")
 		return err
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"darulabror/internal/cache"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	dashboardCacheTTL       = time.Minute
+	dashboardCacheEntries   = 100
+	dashboardDefaultDays    = 30
+	dashboardMaxDays        = 366
+	dashboardTopSchools     = 10
+	dashboardLatestArticles = 5
+)
+
+// DashboardQuery: From/To are YYYY-MM-DD (WIB, inclusive); empty means the last 30 days.
+// AdmissionPeriodID (0 = all) narrows the registration figures.
+type DashboardQuery struct {
+	From              string
+	To                string
+	AdmissionPeriodID uint
+}
+
+type DashboardService interface {
+	// GetStats returns the admin overview; results are cached for a minute per query.
+	GetStats(q DashboardQuery) (dto.DashboardStatsDTO, error)
+}
+
+type dashboardService struct {
+	regRepo     repository.RegistrationRepo
+	contactRepo repository.ContactRepository
+	articleRepo repository.ArticleRepo
+	cache       cache.Cache
+}
+
+// NewDashboardService caches stats in c; a nil c (CACHE_DRIVER=none) still gets a small
+// in-process cache, since every uncached call runs the full set of aggregate queries.
+func NewDashboardService(regRepo repository.RegistrationRepo, contactRepo repository.ContactRepository, articleRepo repository.ArticleRepo, c cache.Cache) DashboardService {
+	if c == nil {
+		c = cache.NewMemory(dashboardCacheEntries)
+	}
+	return &dashboardService{
+		regRepo:     regRepo,
+		contactRepo: contactRepo,
+		articleRepo: articleRepo,
+		cache:       c,
+	}
+}
+
+func dashboardCacheKey(q DashboardQuery) string {
+	return fmt.Sprintf("dashboard:stats:%s:%s:%d", q.From, q.To, q.AdmissionPeriodID)
+}
+
+// dashboardRange resolves the inclusive day range of q; the result covers [from, to+1 day).
+func dashboardRange(fromStr, toStr string, now time.Time) (time.Time, time.Time, error) {
+	today := now.In(exportLocation)
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, exportLocation)
+	if toStr != "" {
+		t, err := time.ParseInLocation(dateLayoutISO, toStr, exportLocation)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDashboardRange
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(dashboardDefaultDays - 1))
+	if fromStr != "" {
+		t, err := time.ParseInLocation(dateLayoutISO, fromStr, exportLocation)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDashboardRange
+		}
+		from = t
+	}
+
+	if from.After(to) || to.Sub(from) >= dashboardMaxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidDashboardRange
+	}
+	return from, to, nil
+}
+
+// fillDaily returns one entry per day from..to (inclusive), 0 for days without rows.
+func fillDaily(rows []repository.DailyCount, from, to time.Time) []dto.DailyCountDTO {
+	counts := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		counts[r.Day] = r.Count
+	}
+
+	var out []dto.DailyCountDTO
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := d.Format(dateLayoutISO)
+		out = append(out, dto.DailyCountDTO{Day: day, Count: counts[day]})
+	}
+	return out
+}
+
+func keyCountMap(rows []repository.KeyCount) map[string]int64 {
+	out := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		out[r.Key] = r.Count
+	}
+	return out
+}
+
+func (s *dashboardService) GetStats(q DashboardQuery) (dto.DashboardStatsDTO, error) {
+	now := time.Now()
+	from, to, err := dashboardRange(q.From, q.To, now)
+	if err != nil {
+		return dto.DashboardStatsDTO{}, err
+	}
+	// cache on the resolved range, so "last 30 days" and explicit dates share entries
+	key := DashboardQuery{From: from.Format(dateLayoutISO), To: to.Format(dateLayoutISO), AdmissionPeriodID: q.AdmissionPeriodID}
+
+	return cache.Load(s.cache, dashboardCacheKey(key), dashboardCacheTTL, cache.Tagged[dto.DashboardStatsDTO](), func() (dto.DashboardStatsDTO, error) {
+		return s.compute(key, from, to, now)
+	})
+}
+
+func (s *dashboardService) compute(key DashboardQuery, from, to, now time.Time) (dto.DashboardStatsDTO, error) {
+	filter := repository.RegistrationFilter{AdmissionPeriodID: key.AdmissionPeriodID}
+	out := dto.DashboardStatsDTO{
+		From:              key.From,
+		To:                key.To,
+		AdmissionPeriodID: key.AdmissionPeriodID,
+		GeneratedAt:       now.Unix(),
+	}
+
+	breakdown, err := s.regRepo.Breakdown(filter)
+	if err != nil {
+		logrus.WithError(err).Error("failed get registration breakdown")
+		return dto.DashboardStatsDTO{}, err
+	}
+	schools, err := s.regRepo.TopOriginSchools(filter, dashboardTopSchools)
+	if err != nil {
+		logrus.WithError(err).Error("failed get top origin schools")
+		return dto.DashboardStatsDTO{}, err
+	}
+	daily, err := s.regRepo.DailyCounts(filter, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		logrus.WithError(err).Error("failed get daily registrations")
+		return dto.DashboardStatsDTO{}, err
+	}
+	out.Registrations = dto.RegistrationStatsDTO{
+		Total:            breakdown.Total,
+		ByStatus:         keyCountMap(breakdown.ByStatus),
+		ByGender:         keyCountMap(breakdown.ByGender),
+		ByStudentType:    keyCountMap(breakdown.ByStudentType),
+		TopOriginSchools: make([]dto.KeyCountDTO, 0, len(schools)),
+		Daily:            fillDaily(daily, from, to),
+	}
+	for _, sc := range schools {
+		out.Registrations.TopOriginSchools = append(out.Registrations.TopOriginSchools, dto.KeyCountDTO{Key: sc.Key, Count: sc.Count})
+	}
+
+	backlog, err := s.contactRepo.Backlog(now)
+	if err != nil {
+		logrus.WithError(err).Error("failed get contact backlog")
+		return dto.DashboardStatsDTO{}, err
+	}
+	out.Contacts = contactStats(backlog)
+
+	articles, err := s.articleRepo.CountByStatus()
+	if err != nil {
+		logrus.WithError(err).Error("failed count articles")
+		return dto.DashboardStatsDTO{}, err
+	}
+	latest, err := s.articleRepo.LatestPublished(dashboardLatestArticles)
+	if err != nil {
+		logrus.WithError(err).Error("failed get latest published articles")
+		return dto.DashboardStatsDTO{}, err
+	}
+	out.Articles = dto.ArticleStatsDTO{
+		ByStatus:        keyCountMap(articles),
+		LatestPublished: make([]dto.ArticleSummaryDTO, 0, len(latest)),
+	}
+	for _, a := range articles {
+		out.Articles.Total += a.Count
+	}
+	for _, a := range latest {
+		out.Articles.LatestPublished = append(out.Articles.LatestPublished, dto.ArticleSummaryDTO{
+			ID:          a.ID,
+			Title:       a.Title,
+			Author:      a.Author,
+			PhotoHeader: a.PhotoHeader,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// contactStats groups backlog rows per status in the order new, in_progress, done.
+func contactStats(rows []repository.ContactBacklog) dto.ContactStatsDTO {
+	statuses := []models.ContactStatus{models.ContactStatusNew, models.ContactStatusInProgress, models.ContactStatusDone}
+	byStatus := make(map[string]*dto.ContactBacklogDTO, len(statuses))
+	out := dto.ContactStatsDTO{Backlog: make([]dto.ContactBacklogDTO, len(statuses))}
+	for i, st := range statuses {
+		out.Backlog[i] = dto.ContactBacklogDTO{Status: string(st), ByAge: make(map[string]int64, len(repository.ContactAgeBuckets))}
+		for _, b := range repository.ContactAgeBuckets {
+			out.Backlog[i].ByAge[b] = 0
+		}
+		byStatus[string(st)] = &out.Backlog[i]
+	}
+
+	for _, r := range rows {
+		b, ok := byStatus[r.Status]
+		if !ok {
+			continue
+		}
+		b.ByAge[r.Bucket] += r.Count
+		b.Total += r.Count
+		if r.Status != string(models.ContactStatusDone) {
+			out.Open += r.Count
+		}
+	}
+	return out
+}
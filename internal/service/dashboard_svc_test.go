@@ -0,0 +1,129 @@
+package service
+
+import (
+	"darulabror/internal/cache"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDashboardRange(t *testing.T) {
+	// 2025-01-31 01:00 WIB is still 2025-01-30 in UTC
+	now := time.Date(2025, 1, 30, 18, 0, 0, 0, time.UTC)
+
+	from, to, err := dashboardRange("", "", now)
+	if err != nil {
+		t.Fatalf("dashboardRange() error = %v", err)
+	}
+	if got := from.Format(dateLayoutISO) + ".." + to.Format(dateLayoutISO); got != "2025-01-02..2025-01-31" {
+		t.Errorf("default range = %s, want the last 30 days in WIB", got)
+	}
+
+	for _, tc := range [][2]string{
+		{"2025-02-01", "2025-01-31"},
+		{"2024-01-01", "2025-01-31"},
+		{"31/01/2025", ""},
+	} {
+		if _, _, err := dashboardRange(tc[0], tc[1], now); !errors.Is(err, ErrInvalidDashboardRange) {
+			t.Errorf("dashboardRange(%q, %q) error = %v, want %v", tc[0], tc[1], err, ErrInvalidDashboardRange)
+		}
+	}
+}
+
+func TestFillDaily(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, exportLocation)
+	to := time.Date(2025, 1, 3, 0, 0, 0, 0, exportLocation)
+
+	got := fillDaily([]repository.DailyCount{{Day: "2025-01-02", Count: 4}}, from, to)
+	if len(got) != 3 || got[0].Count != 0 || got[1].Day != "2025-01-02" || got[1].Count != 4 || got[2].Count != 0 {
+		t.Errorf("fillDaily() = %+v", got)
+	}
+}
+
+func TestContactStats(t *testing.T) {
+	got := contactStats([]repository.ContactBacklog{
+		{Status: "new", Bucket: "lt_1d", Count: 2},
+		{Status: "new", Bucket: "gt_7d", Count: 1},
+		{Status: "in_progress", Bucket: "3d_7d", Count: 3},
+		{Status: "done", Bucket: "gt_7d", Count: 10},
+	})
+
+	if got.Open != 6 {
+		t.Errorf("Open = %d, want 6", got.Open)
+	}
+	if len(got.Backlog) != 3 || got.Backlog[0].Status != "new" || got.Backlog[0].Total != 3 || got.Backlog[0].ByAge["gt_7d"] != 1 {
+		t.Errorf("Backlog = %+v", got.Backlog)
+	}
+	if _, ok := got.Backlog[1].ByAge["lt_1d"]; !ok {
+		t.Error("empty age buckets should be present with 0")
+	}
+}
+
+type fakeDashboardRegRepo struct {
+	repository.RegistrationRepo
+	breakdowns int
+}
+
+func (r *fakeDashboardRegRepo) Breakdown(filter repository.RegistrationFilter) (repository.RegistrationBreakdown, error) {
+	r.breakdowns++
+	return repository.RegistrationBreakdown{Total: int64(filter.AdmissionPeriodID)}, nil
+}
+
+func (r *fakeDashboardRegRepo) TopOriginSchools(filter repository.RegistrationFilter, limit int) ([]repository.KeyCount, error) {
+	return nil, nil
+}
+
+func (r *fakeDashboardRegRepo) DailyCounts(filter repository.RegistrationFilter, from, to time.Time) ([]repository.DailyCount, error) {
+	return nil, nil
+}
+
+type fakeDashboardContactRepo struct {
+	repository.ContactRepository
+}
+
+func (r *fakeDashboardContactRepo) Backlog(now time.Time) ([]repository.ContactBacklog, error) {
+	return nil, nil
+}
+
+type fakeDashboardArticleRepo struct {
+	repository.ArticleRepo
+}
+
+func (r *fakeDashboardArticleRepo) CountByStatus() ([]repository.KeyCount, error) {
+	return nil, nil
+}
+
+func (r *fakeDashboardArticleRepo) LatestPublished(limit int) ([]models.Article, error) {
+	return nil, nil
+}
+
+func TestDashboardGetStatsCached(t *testing.T) {
+	for name, c := range map[string]cache.Cache{"shared": cache.NewMemory(10), "none": nil} {
+		t.Run(name, func(t *testing.T) {
+			regRepo := &fakeDashboardRegRepo{}
+			svc := NewDashboardService(regRepo, &fakeDashboardContactRepo{}, &fakeDashboardArticleRepo{}, c)
+
+			for i := 0; i < 2; i++ {
+				got, err := svc.GetStats(DashboardQuery{AdmissionPeriodID: 3})
+				if err != nil {
+					t.Fatalf("GetStats() error = %v", err)
+				}
+				if got.Registrations.Total != 3 {
+					t.Errorf("Registrations.Total = %d, want 3", got.Registrations.Total)
+				}
+			}
+			if regRepo.breakdowns != 1 {
+				t.Errorf("Breakdown called %d times, want 1 (second call cached)", regRepo.breakdowns)
+			}
+
+			if _, err := svc.GetStats(DashboardQuery{AdmissionPeriodID: 4}); err != nil {
+				t.Fatalf("GetStats() error = %v", err)
+			}
+			if regRepo.breakdowns != 2 {
+				t.Errorf("Breakdown called %d times, want 2 (period is part of the key)", regRepo.breakdowns)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultDocumentLinkTTL = 30 * 24 * time.Hour
+
+// DocumentConfig is loaded by config.LoadDocumentConfig.
+type DocumentConfig struct {
+	Letterhead Letterhead
+	SigningKey []byte        // HMAC key of download links
+	LinkTTL    time.Duration // lifetime of download links (default 30 days)
+	BaseURL    string        // public API base URL the links point to, e.g. https://api.darulabror.id
+}
+
+type DocumentService interface {
+	// Links returns fresh download links of a registration.
+	Links(reg models.Registration) dto.DocumentLinksDTO
+	GetRegistrationDocuments(id uint) (dto.DocumentLinksDTO, error)
+	// Track lets an applicant look up a registration; both code and NISN must match.
+	Track(code, nisn string) (dto.RegistrationTrackDTO, error)
+	// RenderByToken returns the file name and PDF of a download link.
+	RenderByToken(token string) (string, []byte, error)
+	// StartBulkDocuments zips the cards or letters of a period in the background (an export job).
+	StartBulkDocuments(adminID, periodID uint, kind string) (dto.ExportJobDTO, error)
+	// Close waits for running bulk document jobs, like ExportService.Close.
+	Close(ctx context.Context) error
+
+	GetLetterTemplates() ([]dto.LetterTemplateDTO, error)
+	UpdateLetterTemplate(kind models.LetterKind, in dto.LetterTemplateDTO, adminID uint) (dto.LetterTemplateDTO, error)
+}
+
+type documentService struct {
+	cfg          DocumentConfig
+	regRepo      repository.RegistrationRepo
+	periodRepo   repository.AdmissionPeriodRepo
+	letterRepo   repository.LetterTemplateRepo
+	jobRepo      repository.ExportJobRepo
+	privateStore repository.ObjectStore
+	jobs         *jobRunner
+}
+
+func NewDocumentService(
+	cfg DocumentConfig,
+	regRepo repository.RegistrationRepo,
+	periodRepo repository.AdmissionPeriodRepo,
+	letterRepo repository.LetterTemplateRepo,
+	jobRepo repository.ExportJobRepo,
+	privateStore repository.ObjectStore,
+) DocumentService {
+	if cfg.LinkTTL <= 0 {
+		cfg.LinkTTL = defaultDocumentLinkTTL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &documentService{
+		cfg:          cfg,
+		regRepo:      regRepo,
+		periodRepo:   periodRepo,
+		letterRepo:   letterRepo,
+		jobRepo:      jobRepo,
+		privateStore: privateStore,
+		jobs:         newJobRunner(),
+	}
+}
+
+func (s *documentService) Close(ctx context.Context) error {
+	return s.jobs.Close(ctx)
+}
+
+func (s *documentService) Links(reg models.Registration) dto.DocumentLinksDTO {
+	exp := time.Now().Add(s.cfg.LinkTTL)
+	link := func(kind string) string {
+		token := signDocumentToken(s.cfg.SigningKey, documentToken{RegistrationID: reg.ID, Kind: kind, ExpiresAt: exp})
+		return s.cfg.BaseURL + "/v1/documents/" + token
+	}
+
+	out := dto.DocumentLinksDTO{
+		TrackingCode: reg.TrackingCode,
+		CardURL:      link(DocumentCard),
+		ExpiresAt:    exp.Unix(),
+	}
+	if kind, ok := letterKindFor(reg.Status); ok {
+		out.LetterURL = link(DocumentLetter)
+		out.LetterKind = kind
+	}
+	return out
+}
+
+func (s *documentService) GetRegistrationDocuments(id uint) (dto.DocumentLinksDTO, error) {
+	reg, err := s.regRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.DocumentLinksDTO{}, ErrNotFoundRegistration
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed get registration for documents")
+		return dto.DocumentLinksDTO{}, err
+	}
+	return s.Links(reg), nil
+}
+
+// lookupRegistration finds the registration of an applicant by tracking code and NISN.
+func lookupRegistration(repo repository.RegistrationRepo, code, nisn string) (models.Registration, error) {
+	reg, err := repo.GetByTrackingCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.WithError(err).Error("failed get registration by tracking code")
+		return models.Registration{}, err
+	}
+	// same answer for an unknown code and a wrong NISN
+	if err != nil || reg.NISN != strings.TrimSpace(nisn) {
+		return models.Registration{}, ErrNotFoundRegistration
+	}
+	return reg, nil
+}
+
+func (s *documentService) Track(code, nisn string) (dto.RegistrationTrackDTO, error) {
+	reg, err := lookupRegistration(s.regRepo, code, nisn)
+	if err != nil {
+		return dto.RegistrationTrackDTO{}, err
+	}
+
+	out := dto.RegistrationTrackDTO{
+		TrackingCode: reg.TrackingCode,
+		FullName:     reg.FullName,
+		Status:       reg.Status,
+		CreatedAt:    reg.CreatedAt.Format(time.RFC3339),
+		Documents:    s.Links(reg),
+	}
+	if period, err := s.period(reg); err == nil && period.ID != 0 {
+		out.AdmissionPeriod = period.Name + " " + period.AcademicYear
+	}
+	return out, nil
+}
+
+func (s *documentService) RenderByToken(token string) (string, []byte, error) {
+	t, err := verifyDocumentToken(s.cfg.SigningKey, token, time.Now())
+	if err != nil {
+		return "", nil, err
+	}
+
+	reg, err := s.regRepo.GetByID(t.RegistrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, ErrInvalidDocumentToken
+		}
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	name, err := s.render(&buf, reg, t.Kind)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, buf.Bytes(), nil
+}
+
+// render writes one document and returns its file name.
+func (s *documentService) render(w io.Writer, reg models.Registration, kind string) (string, error) {
+	period, err := s.period(reg)
+	if err != nil {
+		return "", err
+	}
+
+	switch kind {
+	case DocumentCard:
+		if err := renderRegistrationCard(w, s.cfg.Letterhead, reg, period); err != nil {
+			logrus.WithError(err).WithField("id", reg.ID).Error("failed render registration card")
+			return "", err
+		}
+		return "kartu_pendaftaran_" + reg.TrackingCode + ".pdf", nil
+
+	case DocumentLetter:
+		letterKind, ok := letterKindFor(reg.Status)
+		if !ok {
+			return "", ErrDocumentNotAvailable
+		}
+		tpl, _, err := s.letterTemplate(letterKind)
+		if err != nil {
+			return "", err
+		}
+		if err := renderLetter(w, s.cfg.Letterhead, tpl, reg, period, time.Now()); err != nil {
+			logrus.WithError(err).WithField("id", reg.ID).Error("failed render decision letter")
+			return "", err
+		}
+		return "surat_keputusan_" + reg.TrackingCode + ".pdf", nil
+	}
+	return "", ErrInvalidDocumentKind
+}
+
+// period returns the registration's admission period, or a zero period when it has none.
+func (s *documentService) period(reg models.Registration) (models.AdmissionPeriod, error) {
+	if reg.AdmissionPeriodID == nil {
+		return models.AdmissionPeriod{}, nil
+	}
+	period, err := s.periodRepo.GetByID(*reg.AdmissionPeriodID)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", *reg.AdmissionPeriodID).Error("failed get admission period for document")
+	}
+	return period, err
+}
+
+func (s *documentService) StartBulkDocuments(adminID, periodID uint, kind string) (dto.ExportJobDTO, error) {
+	if kind != DocumentCard && kind != DocumentLetter {
+		return dto.ExportJobDTO{}, ErrInvalidDocumentKind
+	}
+	if _, err := s.periodRepo.GetByID(periodID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ExportJobDTO{}, ErrNotFoundAdmissionPeriod
+		}
+		return dto.ExportJobDTO{}, err
+	}
+
+	params, err := json.Marshal(map[string]interface{}{"admission_period_id": periodID, "kind": kind})
+	if err != nil {
+		return dto.ExportJobDTO{}, err
+	}
+	job := models.ExportJob{
+		Kind:        "documents_" + kind,
+		Format:      "zip",
+		Params:      params,
+		RequestedBy: adminID,
+	}
+	if err := s.jobRepo.Create(&job); err != nil {
+		logrus.WithError(err).Error("failed create document export job")
+		return dto.ExportJobDTO{}, err
+	}
+
+	s.jobs.Go(func(ctx context.Context) { s.runBulkDocuments(ctx, job, periodID, kind) })
+
+	logrus.WithFields(logrus.Fields{
+		"job_id":    job.ID,
+		"kind":      kind,
+		"period_id": periodID,
+		"admin_id":  adminID,
+	}).Info("document export job started")
+	return dto.ExportJobModelToDTO(job), nil
+}
+
+// runBulkDocuments streams a zip of PDFs into the private bucket, like runRegistrationExport.
+// Letters are only generated for registrations with a decision (done or rejected).
+func (s *documentService) runBulkDocuments(ctx context.Context, job models.ExportJob, periodID uint, kind string) {
+	job.Status = models.ExportJobRunning
+	if err := s.jobRepo.Update(job); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("failed mark export job running")
+	}
+
+	// the job ID keeps two bulk jobs of the same kind and period apart
+	job.ObjectName = fmt.Sprintf("exports/documents_%s_%d_%d_%s.zip", kind, periodID, job.ID, time.Now().In(exportLocation).Format("20060102_150405"))
+
+	pr, pw := io.Pipe()
+	rowsCh := make(chan int64, 1)
+	go func() {
+		rows, err := s.writeDocumentsZip(pw, periodID, kind)
+		rowsCh <- rows
+		_ = pw.CloseWithError(err)
+	}()
+
+	_, err := s.privateStore.UploadFile(ctx, pr, job.ObjectName)
+	_ = pr.CloseWithError(err)
+	job.RowCount = <-rowsCh
+	job.CompletedAt = time.Now().Unix()
+
+	if err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("document export job failed")
+		job.Status = models.ExportJobFailed
+		job.Error = "export failed"
+		if errors.Is(err, repository.ErrStorageNotConfigured) {
+			job.Error = "private storage is not configured"
+		}
+	} else {
+		job.Status = models.ExportJobDone
+		logrus.WithFields(logrus.Fields{
+			"job_id": job.ID,
+			"files":  job.RowCount,
+			"object": job.ObjectName,
+		}).Info("document export job done")
+	}
+
+	if err := s.jobRepo.Update(job); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("failed update export job")
+	}
+}
+
+func (s *documentService) writeDocumentsZip(w io.Writer, periodID uint, kind string) (int64, error) {
+	zw := zip.NewWriter(w)
+	var files int64
+	err := s.regRepo.FindInBatches(repository.RegistrationFilter{AdmissionPeriodID: periodID}, exportBatchSize, func(batch []models.Registration) error {
+		for _, reg := range batch {
+			if kind == DocumentLetter {
+				if _, ok := letterKindFor(reg.Status); !ok {
+					continue
+				}
+			}
+
+			var buf bytes.Buffer
+			name, err := s.render(&buf, reg, kind)
+			if err != nil {
+				return err
+			}
+			f, err := zw.Create(name)
+			if err != nil {
+				return err
+			}
+			if _, err := f.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			files++
+		}
+		return nil
+	})
+	if err != nil {
+		return files, err
+	}
+	return files, zw.Close()
+}
+
+// letterTemplate returns the saved template of a kind, or the built-in default (custom=false).
+func (s *documentService) letterTemplate(kind models.LetterKind) (models.LetterTemplate, bool, error) {
+	tpl, err := s.letterRepo.Get(kind)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultLetterTemplates[kind], false, nil
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("kind", kind).Error("failed get letter template")
+		return models.LetterTemplate{}, false, err
+	}
+	return tpl, true, nil
+}
+
+func (s *documentService) GetLetterTemplates() ([]dto.LetterTemplateDTO, error) {
+	out := make([]dto.LetterTemplateDTO, 0, 2)
+	for _, kind := range []models.LetterKind{models.LetterAcceptance, models.LetterRejection} {
+		tpl, custom, err := s.letterTemplate(kind)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dto.LetterTemplateModelToDTO(tpl, custom))
+	}
+	return out, nil
+}
+
+func (s *documentService) UpdateLetterTemplate(kind models.LetterKind, in dto.LetterTemplateDTO, adminID uint) (dto.LetterTemplateDTO, error) {
+	if _, ok := defaultLetterTemplates[kind]; !ok {
+		return dto.LetterTemplateDTO{}, ErrInvalidLetterKind
+	}
+
+	tpl := models.LetterTemplate{
+		Kind:      kind,
+		Subject:   in.Subject,
+		Body:      in.Body,
+		UpdatedBy: adminID,
+	}
+	// a broken template would only fail when a letter is downloaded, so try it now
+	if _, _, err := executeLetterTemplate(tpl, sampleLetterData); err != nil {
+		return dto.LetterTemplateDTO{}, fmt.Errorf("%w: %v", ErrInvalidLetterTemplate, err)
+	}
+
+	if err := s.letterRepo.Save(tpl); err != nil {
+		logrus.WithError(err).WithField("kind", kind).Error("failed save letter template")
+		return dto.LetterTemplateDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"kind":     kind,
+		"admin_id": adminID,
+	}).Info("letter template updated")
+	saved, _, err := s.letterTemplate(kind)
+	if err != nil {
+		return dto.LetterTemplateDTO{}, err
+	}
+	return dto.LetterTemplateModelToDTO(saved, true), nil
+}
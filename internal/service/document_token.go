@@ -0,0 +1,82 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DocumentCard   = "card"
+	DocumentLetter = "letter"
+)
+
+// documentToken is the content of a download link: which document of which registration, until when.
+type documentToken struct {
+	RegistrationID uint
+	Kind           string
+	ExpiresAt      time.Time
+}
+
+// signDocumentToken returns base64url("id.kind.exp") + "." + base64url(hmac[:16]).
+func signDocumentToken(key []byte, t documentToken) string {
+	payload := fmt.Sprintf("%d.%s.%d", t.RegistrationID, t.Kind, t.ExpiresAt.Unix())
+	enc := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return enc + "." + documentMAC(key, enc)
+}
+
+func verifyDocumentToken(key []byte, token string, now time.Time) (documentToken, error) {
+	enc, mac, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(documentMAC(key, enc))) {
+		return documentToken{}, ErrInvalidDocumentToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return documentToken{}, ErrInvalidDocumentToken
+	}
+	parts := strings.Split(string(raw), ".")
+	if len(parts) != 3 {
+		return documentToken{}, ErrInvalidDocumentToken
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return documentToken{}, ErrInvalidDocumentToken
+	}
+	exp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return documentToken{}, ErrInvalidDocumentToken
+	}
+
+	t := documentToken{RegistrationID: uint(id), Kind: parts[1], ExpiresAt: time.Unix(exp, 0)}
+	if !now.Before(t.ExpiresAt) {
+		return documentToken{}, ErrInvalidDocumentToken
+	}
+	return t, nil
+}
+
+func documentMAC(key []byte, payload string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil)[:16])
+}
+
+// trackingAlphabet leaves out 0/O, 1/I/L so codes can be read over the phone.
+const trackingAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// newTrackingCode returns a code like "DA-7K3M-Q9TX".
+func newTrackingCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = trackingAlphabet[int(b[i])%len(trackingAlphabet)]
+	}
+	return "DA-" + string(b[:4]) + "-" + string(b[4:]), nil
+}
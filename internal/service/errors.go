@@ -1,22 +1,122 @@
 package service
 
-import "errors"
+import "darulabror/internal/utils"
 
+// Service errors are domain errors: each carries the code that decides its HTTP status, and
+// errors.Is still matches them after Wrap.
 var (
 	// Admin service errors
-	ErrNotFoundAdmin = errors.New("admin not found")
-	ErrInvalidAdmin  = errors.New("invalid admin")
-	ErrCreateAdmin   = errors.New("failed to create admin")
+	ErrNotFoundAdmin    = utils.NewDomainError(utils.CodeNotFound, "admin not found")
+	ErrInvalidAdmin     = utils.NewDomainError(utils.CodeValidation, "invalid admin")
+	ErrCreateAdmin      = utils.NewDomainError(utils.CodeInternal, "failed to create admin")
+	ErrAdminEmailExists = utils.NewDomainError(utils.CodeConflict, "admin email already used")
+	ErrInvalidAdminRole = utils.NewDomainError(utils.CodeValidation, "role does not exist")
+	// Two-factor authentication errors
+	ErrInvalidMFAToken   = utils.NewDomainError(utils.CodeUnauthorized, "invalid or expired mfa token")
+	ErrInvalidMFACode    = utils.NewDomainError(utils.CodeUnauthorized, "invalid two-factor code")
+	ErrMFALocked         = utils.NewDomainError(utils.CodeTooMany, "too many wrong two-factor codes; try again later")
+	ErrMFAAlreadyEnabled = utils.NewDomainError(utils.CodeConflict, "two-factor authentication is already enabled")
+	ErrMFANotSetUp       = utils.NewDomainError(utils.CodeConflict, "set up an authenticator first")
+	ErrMFANotEnabled     = utils.NewDomainError(utils.CodeConflict, "two-factor authentication is not enabled")
+	ErrMFARequired       = utils.NewDomainError(utils.CodeForbidden, "two-factor authentication is required for this account")
+	ErrMFARequiredSelf   = utils.NewDomainError(utils.CodeForbidden, "cannot change mfa_required of your own account")
 	// Article service errors
-	ErrNotFoundArticle = errors.New("article not found")
-	ErrCreateArticle   = errors.New("failed to create article")
-	ErrUpdateArticle   = errors.New("failed to update article")
+	ErrNotFoundArticle         = utils.NewDomainError(utils.CodeNotFound, "article not found")
+	ErrCreateArticle           = utils.NewDomainError(utils.CodeInternal, "failed to create article")
+	ErrUpdateArticle           = utils.NewDomainError(utils.CodeInternal, "failed to update article")
+	ErrArticleSlugExists       = utils.NewDomainError(utils.CodeConflict, "slug already used by another article")
+	ErrInvalidArticleSlug      = utils.NewDomainError(utils.CodeValidation, "slug must contain letters or digits")
+	ErrArticlePublishForbidden = utils.NewDomainError(utils.CodeForbidden, "publishing or unpublishing articles needs the articles.publish permission")
+	// Article translation errors
+	ErrInvalidTranslationLocale = utils.NewDomainError(utils.CodeValidation, "translation locale must be en or ar (id is the article itself)")
+	ErrNotFoundTranslation      = utils.NewDomainError(utils.CodeNotFound, "article translation not found")
+	// Article view errors
+	ErrInvalidViewWindow = utils.NewDomainError(utils.CodeBadRequest, "invalid window (expected days like 7d, at most 90d)")
 	// Registration service errors public
-	ErrCreateRegistration = errors.New("failed to create registration")
+	ErrCreateRegistration = utils.NewDomainError(utils.CodeInternal, "failed to create registration")
 	// Registration service errors admin
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrAdminInactive      = errors.New("admin is inactive")
+	ErrInvalidCredentials = utils.NewDomainError(utils.CodeUnauthorized, "invalid credentials")
+	ErrAdminInactive      = utils.NewDomainError(utils.CodeForbidden, "admin is inactive")
 	// Registration service errors additional
-	ErrRegistrationEmailExists = errors.New("registration email already used")
-	ErrRegistrationNISNExists  = errors.New("registration nisn already used")
+	ErrRegistrationEmailExists = utils.NewDomainError(utils.CodeConflict, "registration email already used")
+	ErrRegistrationNISNExists  = utils.NewDomainError(utils.CodeConflict, "registration nisn already used")
+	// Upload service errors
+	ErrNotFoundUpload       = utils.NewDomainError(utils.CodeNotFound, "upload not found")
+	ErrUploadTypeNotAllowed = utils.NewDomainError(utils.CodeValidation, "file type not allowed")
+	ErrUploadTooLarge       = utils.NewDomainError(utils.CodeTooLarge, "file too large")
+	ErrUploadIncomplete     = utils.NewDomainError(utils.CodeConflict, "object has not been uploaded yet")
+	ErrUploadMismatch       = utils.NewDomainError(utils.CodeValidation, "uploaded object does not match declared size or type")
+	// Export service errors
+	ErrNotFoundExportJob   = utils.NewDomainError(utils.CodeNotFound, "export job not found")
+	ErrInvalidExportFormat = utils.NewDomainError(utils.CodeValidation, "invalid export format (expected csv or xlsx)")
+	ErrInvalidExportColumn = utils.NewDomainError(utils.CodeValidation, "invalid export column")
+	// Admission period errors
+	ErrNotFoundAdmissionPeriod = utils.NewDomainError(utils.CodeNotFound, "admission period not found")
+	ErrInvalidAcademicYear     = utils.NewDomainError(utils.CodeValidation, "academic_year must look like 2025/2026")
+	ErrAdmissionPeriodOverlap  = utils.NewDomainError(utils.CodeConflict, "admission period overlaps another period")
+	ErrAdmissionPeriodExists   = utils.NewDomainError(utils.CodeConflict, "admission period for this academic year and wave already exists")
+	ErrAdmissionPeriodInUse    = utils.NewDomainError(utils.CodeConflict, "admission period has registrations")
+	ErrRegistrationClosed      = utils.NewDomainError(utils.CodeForbidden, "registration is closed (no open admission period)")
+	// Import service errors
+	ErrImportTooManyRows   = utils.NewDomainError(utils.CodeValidation, "too many rows in import file")
+	ErrImportEmptyFile     = utils.NewDomainError(utils.CodeValidation, "import file has no data rows")
+	ErrImportMissingColumn = utils.NewDomainError(utils.CodeValidation, "import file is missing a required column")
+	ErrImportRegistrations = utils.NewDomainError(utils.CodeInternal, "failed to import registrations")
+	// Quota errors
+	ErrNotFoundRegistration      = utils.NewDomainError(utils.CodeNotFound, "registration not found")
+	ErrInvalidRegistrationStatus = utils.NewDomainError(utils.CodeValidation, "invalid status value")
+	ErrQuotaFull                 = utils.NewDomainError(utils.CodeConflict, "no seat available in this gender/program quota")
+	ErrDuplicateQuota            = utils.NewDomainError(utils.CodeValidation, "duplicate quota for the same gender and program")
+	// Selection errors
+	ErrNotFoundSelectionSession    = utils.NewDomainError(utils.CodeNotFound, "selection session not found")
+	ErrNotFoundSelectionAssignment = utils.NewDomainError(utils.CodeNotFound, "registration is not assigned to this session")
+	ErrSelectionWeights            = utils.NewDomainError(utils.CodeValidation, "selection component weights must add up to 100")
+	ErrDuplicateSelectionComponent = utils.NewDomainError(utils.CodeValidation, "duplicate selection component key")
+	ErrSelectionComponentInUse     = utils.NewDomainError(utils.CodeConflict, "selection component already has scores")
+	ErrSelectionSessionFull        = utils.NewDomainError(utils.CodeConflict, "selection session is full")
+	ErrSelectionSessionCapacity    = utils.NewDomainError(utils.CodeConflict, "capacity is below the number of assigned applicants")
+	ErrSelectionNotEligible        = utils.NewDomainError(utils.CodeConflict, "registration is not in selection (status must be validate, process or done)")
+	ErrSelectionWrongPeriod        = utils.NewDomainError(utils.CodeValidation, "registration belongs to another admission period")
+	ErrInvalidSelectionComponent   = utils.NewDomainError(utils.CodeValidation, "selection component does not belong to the registration's admission period")
+	ErrInvalidSelectionScore       = utils.NewDomainError(utils.CodeValidation, "score must be between 0 and the component max_score")
+	ErrNoSelectionComponents       = utils.NewDomainError(utils.CodeConflict, "admission period has no selection components")
+	// Document errors
+	ErrInvalidDocumentToken  = utils.NewDomainError(utils.CodeNotFound, "document link is invalid or expired")
+	ErrDocumentNotAvailable  = utils.NewDomainError(utils.CodeConflict, "no decision letter yet (status must be done or rejected)")
+	ErrInvalidDocumentKind   = utils.NewDomainError(utils.CodeBadRequest, "invalid document kind (expected card or letter)")
+	ErrInvalidLetterKind     = utils.NewDomainError(utils.CodeBadRequest, "invalid letter kind (expected acceptance or rejection)")
+	ErrInvalidLetterTemplate = utils.NewDomainError(utils.CodeValidation, "invalid letter template")
+	// Payment errors
+	ErrNotFoundInvoice        = utils.NewDomainError(utils.CodeNotFound, "invoice not found")
+	ErrPaymentNotRequired     = utils.NewDomainError(utils.CodeConflict, "no registration fee for this admission period")
+	ErrPaymentNotAllowed      = utils.NewDomainError(utils.CodeConflict, "registration fee can only be paid while status is validate")
+	ErrPaymentNotConfigured   = utils.NewDomainError(utils.CodeUnavailable, "online payment is not configured")
+	ErrInvoiceInProgress      = utils.NewDomainError(utils.CodeConflict, "the invoice is still being opened, try again in a moment")
+	ErrUnknownPaymentProvider = utils.NewDomainError(utils.CodeNotFound, "unknown payment provider")
+	ErrInvalidPaymentWebhook  = utils.NewDomainError(utils.CodeUnauthorized, "invalid payment notification")
+	ErrPaymentAmountMismatch  = utils.NewDomainError(utils.CodeValidation, "paid amount does not match the invoice")
+	ErrNotFoundPaymentProof   = utils.NewDomainError(utils.CodeNotFound, "payment proof not found")
+	ErrPaymentProofPending    = utils.NewDomainError(utils.CodeConflict, "a transfer proof is already waiting for verification")
+	ErrPaymentProofReviewed   = utils.NewDomainError(utils.CodeConflict, "payment proof was already reviewed")
+	ErrPaymentAlreadyPaid     = utils.NewDomainError(utils.CodeConflict, "registration fee is already paid")
+	// Registration edit errors
+	ErrInvalidEditToken            = utils.NewDomainError(utils.CodeUnauthorized, "edit link is invalid or expired")
+	ErrRegistrationNotEditable     = utils.NewDomainError(utils.CodeConflict, "registration can only be edited while its status is new")
+	ErrRegistrationVersionConflict = utils.NewDomainError(utils.CodeConflict, "registration was changed by someone else; reload and try again")
+	// Note and assignment errors
+	ErrNotFoundContact      = utils.NewDomainError(utils.CodeNotFound, "contact not found")
+	ErrInvalidContactStatus = utils.NewDomainError(utils.CodeValidation, "invalid status value")
+	ErrNotFoundNote         = utils.NewDomainError(utils.CodeNotFound, "note not found")
+	ErrNoteForbidden        = utils.NewDomainError(utils.CodeForbidden, "only the author or an admin with notes.delete can delete a note")
+	ErrInvalidAssignee      = utils.NewDomainError(utils.CodeValidation, "assignee must be an active admin")
+	// Role errors
+	ErrNotFoundRole      = utils.NewDomainError(utils.CodeNotFound, "role not found")
+	ErrRoleExists        = utils.NewDomainError(utils.CodeConflict, "role already exists")
+	ErrRoleInUse         = utils.NewDomainError(utils.CodeConflict, "role is assigned to admins")
+	ErrSystemRole        = utils.NewDomainError(utils.CodeConflict, "built-in role cannot be changed this way")
+	ErrInvalidRoleName   = utils.NewDomainError(utils.CodeValidation, "role name must be 2-50 lowercase letters, digits, - or _, starting with a letter")
+	ErrInvalidPermission = utils.NewDomainError(utils.CodeValidation, "unknown permission")
+	ErrPermissionNotHeld = utils.NewDomainError(utils.CodeForbidden, "cannot grant or revoke permissions you do not have")
+	// Dashboard errors
+	ErrInvalidDashboardRange = utils.NewDomainError(utils.CodeBadRequest, "invalid date range (from/to as YYYY-MM-DD, at most 366 days)")
 )
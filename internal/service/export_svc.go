@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// ExportSyncMaxRows: bigger exports are moved to a background job.
+	ExportSyncMaxRows = 5000
+
+	exportBatchSize   = 500
+	exportJobTimeout  = 30 * time.Minute
+	exportDownloadTTL = 15 * time.Minute
+)
+
+type RegistrationExportOptions struct {
+	Format  string                        `json:"format"`
+	Columns []string                      `json:"columns,omitempty"`
+	Filter  repository.RegistrationFilter `json:"filter"`
+}
+
+type ExportService interface {
+	CountRegistrations(filter repository.RegistrationFilter) (int64, error)
+	// ExportRegistrations streams the file into w and returns the number of data rows.
+	ExportRegistrations(w io.Writer, opts RegistrationExportOptions) (int64, error)
+	StartRegistrationExport(adminID uint, opts RegistrationExportOptions) (dto.ExportJobDTO, error)
+	GetExportJob(ctx context.Context, id uint) (dto.ExportJobDTO, error)
+	// Close waits for running jobs; when ctx ends first they are cancelled and recorded as failed.
+	Close(ctx context.Context) error
+}
+
+// jobRunner runs background jobs and lets shutdown wait for them.
+type jobRunner struct {
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newJobRunner() *jobRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jobRunner{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a tracked goroutine with a context limited to exportJobTimeout.
+func (r *jobRunner) Go(fn func(ctx context.Context)) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ctx, cancel := context.WithTimeout(r.ctx, exportJobTimeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+func (r *jobRunner) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// let the jobs record their failure before the process exits
+		r.cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+		return ctx.Err()
+	}
+}
+
+type exportService struct {
+	regRepo      repository.RegistrationRepo
+	jobRepo      repository.ExportJobRepo
+	privateStore repository.ObjectStore
+	jobs         *jobRunner
+}
+
+func NewExportService(regRepo repository.RegistrationRepo, jobRepo repository.ExportJobRepo, privateStore repository.ObjectStore) ExportService {
+	return &exportService{
+		regRepo:      regRepo,
+		jobRepo:      jobRepo,
+		privateStore: privateStore,
+		jobs:         newJobRunner(),
+	}
+}
+
+func (s *exportService) Close(ctx context.Context) error {
+	return s.jobs.Close(ctx)
+}
+
+func (s *exportService) CountRegistrations(filter repository.RegistrationFilter) (int64, error) {
+	count, err := s.regRepo.Count(filter)
+	if err != nil {
+		logrus.WithError(err).Error("failed count registrations for export")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *exportService) ExportRegistrations(w io.Writer, opts RegistrationExportOptions) (int64, error) {
+	cols, err := selectExportColumns(opts.Columns)
+	if err != nil {
+		return 0, err
+	}
+	rw, err := newRowWriter(opts.Format, w, cols)
+	if err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err = s.regRepo.FindInBatches(opts.Filter, exportBatchSize, func(batch []models.Registration) error {
+		for _, reg := range batch {
+			values := make([]interface{}, len(cols))
+			for i, c := range cols {
+				values[i] = c.Value(reg)
+			}
+			if err := rw.WriteRow(values); err != nil {
+				return err
+			}
+			rows++
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).Error("failed export registrations")
+		return rows, err
+	}
+
+	return rows, rw.Close()
+}
+
+// ValidateRegistrationExportOptions lets handlers reject bad input before the response starts streaming.
+func ValidateRegistrationExportOptions(opts RegistrationExportOptions) error {
+	if opts.Format != ExportFormatCSV && opts.Format != ExportFormatXLSX {
+		return ErrInvalidExportFormat
+	}
+	_, err := selectExportColumns(opts.Columns)
+	return err
+}
+
+func (s *exportService) StartRegistrationExport(adminID uint, opts RegistrationExportOptions) (dto.ExportJobDTO, error) {
+	if err := ValidateRegistrationExportOptions(opts); err != nil {
+		return dto.ExportJobDTO{}, err
+	}
+
+	params, err := json.Marshal(opts)
+	if err != nil {
+		return dto.ExportJobDTO{}, err
+	}
+
+	job := models.ExportJob{
+		Kind:        "registrations",
+		Format:      opts.Format,
+		Params:      params,
+		RequestedBy: adminID,
+	}
+	if err := s.jobRepo.Create(&job); err != nil {
+		logrus.WithError(err).Error("failed create export job")
+		return dto.ExportJobDTO{}, err
+	}
+
+	s.jobs.Go(func(ctx context.Context) { s.runRegistrationExport(ctx, job, opts) })
+
+	logrus.WithFields(logrus.Fields{
+		"job_id":   job.ID,
+		"format":   job.Format,
+		"admin_id": adminID,
+	}).Info("registration export job started")
+	return dto.ExportJobModelToDTO(job), nil
+}
+
+// runRegistrationExport streams the export straight into the private bucket via a pipe.
+func (s *exportService) runRegistrationExport(ctx context.Context, job models.ExportJob, opts RegistrationExportOptions) {
+	job.Status = models.ExportJobRunning
+	if err := s.jobRepo.Update(job); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("failed mark export job running")
+	}
+
+	// the job ID keeps two exports started in the same second apart
+	job.ObjectName = fmt.Sprintf("exports/registrations_%d_%s.%s", job.ID, time.Now().In(exportLocation).Format("20060102_150405"), opts.Format)
+
+	pr, pw := io.Pipe()
+	rowsCh := make(chan int64, 1)
+	go func() {
+		rows, err := s.ExportRegistrations(pw, opts)
+		rowsCh <- rows
+		_ = pw.CloseWithError(err)
+	}()
+
+	_, err := s.privateStore.UploadFile(ctx, pr, job.ObjectName)
+	_ = pr.CloseWithError(err) // unblock the writer if the upload stopped early
+	job.RowCount = <-rowsCh
+	job.CompletedAt = time.Now().Unix()
+
+	if err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("registration export job failed")
+		job.Status = models.ExportJobFailed
+		job.Error = "export failed"
+		if errors.Is(err, repository.ErrStorageNotConfigured) {
+			job.Error = "private storage is not configured"
+		}
+	} else {
+		job.Status = models.ExportJobDone
+		logrus.WithFields(logrus.Fields{
+			"job_id": job.ID,
+			"rows":   job.RowCount,
+			"object": job.ObjectName,
+		}).Info("registration export job done")
+	}
+
+	if err := s.jobRepo.Update(job); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("failed update export job")
+	}
+}
+
+func (s *exportService) GetExportJob(ctx context.Context, id uint) (dto.ExportJobDTO, error) {
+	job, err := s.jobRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ExportJobDTO{}, ErrNotFoundExportJob
+		}
+		return dto.ExportJobDTO{}, err
+	}
+
+	// a job (registrations or bulk documents) still unfinished after exportJobTimeout died with its instance
+	if (job.Status == models.ExportJobPending || job.Status == models.ExportJobRunning) &&
+		time.Since(time.Unix(job.CreatedAt, 0)) > exportJobTimeout {
+		job.Status = models.ExportJobFailed
+		job.Error = "export timed out"
+		job.CompletedAt = time.Now().Unix()
+		if err := s.jobRepo.Update(job); err != nil {
+			logrus.WithError(err).WithField("job_id", job.ID).Error("failed mark stale export job failed")
+		}
+	}
+
+	out := dto.ExportJobModelToDTO(job)
+	if job.Status == models.ExportJobDone {
+		url, err := s.privateStore.GenerateSignedURL(ctx, job.ObjectName, exportDownloadTTL)
+		if err != nil {
+			logrus.WithError(err).WithField("job_id", job.ID).Error("failed sign export download url")
+			return dto.ExportJobDTO{}, err
+		}
+		out.DownloadURL = url
+	}
+	return out, nil
+}
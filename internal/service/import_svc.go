@@ -0,0 +1,204 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"darulabror/internal/utils"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ImportMaxRows caps one import file; bigger batches should be split.
+const ImportMaxRows = 2000
+
+type ImportService interface {
+	// ImportRegistrations validates every row and, unless dryRun, inserts the valid rows
+	// in a single transaction. Invalid rows are reported and skipped.
+	// periodID 0 imports into the currently open admission period. Row validation messages
+	// are in lang (see utils.ValidationLanguage).
+	ImportRegistrations(r io.Reader, format, lang string, dryRun bool, periodID uint) (dto.ImportReportDTO, error)
+}
+
+type importService struct {
+	regRepo    repository.RegistrationRepo
+	periodRepo repository.AdmissionPeriodRepo
+	quotaRepo  repository.AdmissionQuotaRepo
+	validate   *validator.Validate
+}
+
+// NewImportService takes the same validator as the HTTP layer so rows follow RegistrationDTO rules.
+func NewImportService(regRepo repository.RegistrationRepo, periodRepo repository.AdmissionPeriodRepo, quotaRepo repository.AdmissionQuotaRepo, validate *validator.Validate) ImportService {
+	return &importService{regRepo: regRepo, periodRepo: periodRepo, quotaRepo: quotaRepo, validate: validate}
+}
+
+// importPeriod resolves the target period. Unlike the public form, staff may import into a
+// closed period (e.g. paper forms collected on the last day).
+func (s *importService) importPeriod(periodID uint) (models.AdmissionPeriod, error) {
+	var (
+		period models.AdmissionPeriod
+		err    error
+	)
+	if periodID == 0 {
+		period, err = s.periodRepo.GetOpen(time.Now())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return period, ErrRegistrationClosed
+		}
+	} else {
+		period, err = s.periodRepo.GetByID(periodID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return period, ErrNotFoundAdmissionPeriod
+		}
+	}
+	return period, err
+}
+
+func (s *importService) ImportRegistrations(r io.Reader, format, lang string, dryRun bool, periodID uint) (dto.ImportReportDTO, error) {
+	period, err := s.importPeriod(periodID)
+	if err != nil {
+		return dto.ImportReportDTO{}, err
+	}
+
+	rows, err := readImportRows(format, r, ImportMaxRows)
+	if err != nil {
+		return dto.ImportReportDTO{}, err
+	}
+
+	report := dto.ImportReportDTO{
+		AdmissionPeriodID: period.ID,
+		DryRun:            dryRun,
+		Total:             len(rows),
+		Rows:              make([]dto.ImportRowDTO, 0, len(rows)),
+	}
+
+	var (
+		valid      []models.Registration
+		validIdx   []int
+		seenEmails = map[string]int{}
+		seenNISN   = map[string]int{}
+	)
+	for _, row := range rows {
+		result := dto.ImportRowDTO{
+			Row:      row.Line,
+			FullName: row.Reg.FullName,
+			Email:    row.Reg.Email,
+			NISN:     row.Reg.NISN,
+		}
+
+		errs, err := s.checkImportRow(period.ID, row, lang, seenEmails, seenNISN)
+		if err != nil {
+			return dto.ImportReportDTO{}, err
+		}
+
+		var reg models.Registration
+		if len(errs) == 0 {
+			if reg, err = dto.RegistrationDTOToModel(row.Reg); err != nil {
+				errs = append(errs, err.Error())
+			}
+			reg.AdmissionPeriodID = &period.ID
+		}
+
+		if len(errs) > 0 {
+			result.Status = dto.ImportRowInvalid
+			result.Errors = errs
+			report.Invalid++
+		} else {
+			result.Status = dto.ImportRowValid
+			report.Valid++
+			valid = append(valid, reg)
+			validIdx = append(validIdx, len(report.Rows))
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if dryRun || len(valid) == 0 {
+		return report, nil
+	}
+
+	for i := range valid {
+		if valid[i].TrackingCode, err = newTrackingCode(); err != nil {
+			return dto.ImportReportDTO{}, err
+		}
+	}
+
+	// seats are assigned in file order; rows past a full quota are waitlisted
+	if err := s.quotaRepo.CreateRegistrations(valid); err != nil {
+		logrus.WithError(err).WithField("rows", len(valid)).Error("failed import registrations")
+		return dto.ImportReportDTO{}, ErrImportRegistrations
+	}
+	for n, i := range validIdx {
+		report.Rows[i].Status = dto.ImportRowImported
+		report.Rows[i].RegistrationStatus = valid[n].Status
+		report.Rows[i].TrackingCode = valid[n].TrackingCode
+		if valid[n].Status == models.RegistrationStatusWaitlisted {
+			report.Waitlisted++
+		}
+	}
+	report.Imported = len(valid)
+
+	logrus.WithFields(logrus.Fields{
+		"imported":   report.Imported,
+		"waitlisted": report.Waitlisted,
+		"invalid":    report.Invalid,
+		"period_id":  period.ID,
+	}).Info("registrations imported")
+	return report, nil
+}
+
+// checkImportRow returns the row's validation and duplicate errors; validation messages are
+// translated to lang like the API's. The error result is reserved for database failures.
+func (s *importService) checkImportRow(periodID uint, row importRow, lang string, seenEmails, seenNISN map[string]int) ([]string, error) {
+	var errs []string
+
+	invalid := map[string]bool{}
+	if err := s.validate.Struct(row.Reg); err != nil {
+		fieldErrs := utils.ValidationErrors(err, lang)
+		if fieldErrs == nil {
+			return []string{err.Error()}, nil
+		}
+		for _, fe := range fieldErrs {
+			invalid[fe.Field] = true
+			errs = append(errs, fe.Field+": "+fe.Message)
+		}
+	}
+
+	if !invalid["email"] {
+		if line, ok := seenEmails[row.Reg.Email]; ok {
+			errs = append(errs, fmt.Sprintf("email: duplicate of row %d", line))
+		} else {
+			seenEmails[row.Reg.Email] = row.Line
+			exists, err := s.regRepo.ExistsByEmail(periodID, row.Reg.Email)
+			if err != nil {
+				logrus.WithError(err).WithField("email", row.Reg.Email).Error("failed check registration email")
+				return nil, err
+			}
+			if exists {
+				errs = append(errs, "email: "+ErrRegistrationEmailExists.Error())
+			}
+		}
+	}
+
+	if !invalid["nisn"] {
+		if line, ok := seenNISN[row.Reg.NISN]; ok {
+			errs = append(errs, fmt.Sprintf("nisn: duplicate of row %d", line))
+		} else {
+			seenNISN[row.Reg.NISN] = row.Line
+			exists, err := s.regRepo.ExistsByNISN(periodID, row.Reg.NISN)
+			if err != nil {
+				logrus.WithError(err).WithField("nisn", row.Reg.NISN).Error("failed check registration nisn")
+				return nil, err
+			}
+			if exists {
+				errs = append(errs, "nisn: "+ErrRegistrationNISNExists.Error())
+			}
+		}
+	}
+
+	return errs, nil
+}
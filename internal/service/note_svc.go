@@ -0,0 +1,167 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// NoteService handles internal notes and the assigned admin of registrations and contacts.
+type NoteService interface {
+	GetNotes(subject models.NoteSubject, subjectID uint) ([]dto.AdminNoteDTO, error)
+	AddNote(subject models.NoteSubject, subjectID, authorID uint, body string) (dto.AdminNoteDTO, error)
+	// DeleteNote is allowed for the author, and for anyone when canDeleteAny (notes.delete).
+	DeleteNote(id, adminID uint, canDeleteAny bool) error
+
+	// Assign sets the admin handling a record; nil unassigns.
+	Assign(subject models.NoteSubject, subjectID uint, assignee *uint, adminID uint) error
+	GetAssignees() ([]dto.AssigneeOptionDTO, error)
+}
+
+type noteService struct {
+	repo      repository.AdminNoteRepo
+	adminRepo repository.AdminRepository
+}
+
+func NewNoteService(repo repository.AdminNoteRepo, adminRepo repository.AdminRepository) NoteService {
+	return &noteService{repo: repo, adminRepo: adminRepo}
+}
+
+func notFoundSubject(subject models.NoteSubject) error {
+	if subject == models.NoteSubjectContact {
+		return ErrNotFoundContact
+	}
+	return ErrNotFoundRegistration
+}
+
+func (s *noteService) checkSubject(subject models.NoteSubject, subjectID uint) error {
+	ok, err := s.repo.SubjectExists(subject, subjectID)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"subject":    subject,
+			"subject_id": subjectID,
+		}).Error("failed check note subject")
+		return err
+	}
+	if !ok {
+		return notFoundSubject(subject)
+	}
+	return nil
+}
+
+func (s *noteService) GetNotes(subject models.NoteSubject, subjectID uint) ([]dto.AdminNoteDTO, error) {
+	if err := s.checkSubject(subject, subjectID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.repo.GetBySubject(subject, subjectID)
+	if err != nil {
+		logrus.WithError(err).WithField("subject_id", subjectID).Error("failed get notes")
+		return nil, err
+	}
+
+	out := make([]dto.AdminNoteDTO, 0, len(notes))
+	for _, n := range notes {
+		out = append(out, dto.AdminNoteModelToDTO(n.AdminNote, n.AuthorName))
+	}
+	return out, nil
+}
+
+func (s *noteService) AddNote(subject models.NoteSubject, subjectID, authorID uint, body string) (dto.AdminNoteDTO, error) {
+	if err := s.checkSubject(subject, subjectID); err != nil {
+		return dto.AdminNoteDTO{}, err
+	}
+
+	note := models.AdminNote{SubjectType: subject, SubjectID: subjectID, AuthorID: authorID, Body: body}
+	if err := s.repo.Create(&note); err != nil {
+		logrus.WithError(err).WithField("subject_id", subjectID).Error("failed create note")
+		return dto.AdminNoteDTO{}, err
+	}
+
+	var authorName string
+	if author, err := s.adminRepo.GetAdminByID(authorID); err == nil {
+		authorName = author.Username
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":         note.ID,
+		"subject":    subject,
+		"subject_id": subjectID,
+		"author_id":  authorID,
+	}).Info("note created")
+	return dto.AdminNoteModelToDTO(note, authorName), nil
+}
+
+func (s *noteService) DeleteNote(id, adminID uint, canDeleteAny bool) error {
+	note, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundNote
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed get note")
+		return err
+	}
+	if note.AuthorID != adminID && !canDeleteAny {
+		return ErrNoteForbidden
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed delete note")
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"admin_id": adminID,
+	}).Info("note deleted")
+	return nil
+}
+
+func (s *noteService) Assign(subject models.NoteSubject, subjectID uint, assignee *uint, adminID uint) error {
+	if assignee != nil {
+		a, err := s.adminRepo.GetAdminByID(*assignee)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !a.IsActive) {
+			return ErrInvalidAssignee
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("admin_id", *assignee).Error("failed get assignee")
+			return err
+		}
+	}
+
+	if err := s.repo.Assign(subject, subjectID, assignee); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFoundSubject(subject)
+		}
+		logrus.WithError(err).WithField("subject_id", subjectID).Error("failed assign")
+		return err
+	}
+	var assignedTo uint // 0 = unassigned
+	if assignee != nil {
+		assignedTo = *assignee
+	}
+	logrus.WithFields(logrus.Fields{
+		"subject":     subject,
+		"subject_id":  subjectID,
+		"assigned_to": assignedTo,
+		"admin_id":    adminID,
+	}).Info("assignee updated")
+	return nil
+}
+
+func (s *noteService) GetAssignees() ([]dto.AssigneeOptionDTO, error) {
+	admins, err := s.adminRepo.GetActiveAdmins()
+	if err != nil {
+		logrus.WithError(err).Error("failed get active admins")
+		return nil, err
+	}
+
+	out := make([]dto.AssigneeOptionDTO, 0, len(admins))
+	for _, a := range admins {
+		out = append(out, dto.AssigneeOptionDTO{ID: a.ID, Username: a.Username})
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type fakeAdminNoteRepo struct {
+	repository.AdminNoteRepo
+	notes map[uint]models.AdminNote
+}
+
+func (f *fakeAdminNoteRepo) GetByID(id uint) (models.AdminNote, error) {
+	n, ok := f.notes[id]
+	if !ok {
+		return models.AdminNote{}, gorm.ErrRecordNotFound
+	}
+	return n, nil
+}
+
+func (f *fakeAdminNoteRepo) Delete(id uint) error {
+	delete(f.notes, id)
+	return nil
+}
+
+func TestDeleteNote(t *testing.T) {
+	tests := []struct {
+		name         string
+		adminID      uint
+		canDeleteAny bool
+		want         error
+	}{
+		{name: "author", adminID: 3},
+		{name: "other admin without notes.delete", adminID: 4, want: ErrNoteForbidden},
+		{name: "other admin with notes.delete", adminID: 4, canDeleteAny: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAdminNoteRepo{notes: map[uint]models.AdminNote{7: {ID: 7, AuthorID: 3, Body: "call back"}}}
+			s := NewNoteService(repo, nil)
+
+			if err := s.DeleteNote(7, tt.adminID, tt.canDeleteAny); !errors.Is(err, tt.want) {
+				t.Fatalf("DeleteNote() error = %v, want %v", err, tt.want)
+			}
+			if _, kept := repo.notes[7]; kept != (tt.want != nil) {
+				t.Errorf("note kept = %v, want %v", kept, tt.want != nil)
+			}
+		})
+	}
+
+	s := NewNoteService(&fakeAdminNoteRepo{notes: map[uint]models.AdminNote{}}, nil)
+	if err := s.DeleteNote(9, 3, true); !errors.Is(err, ErrNotFoundNote) {
+		t.Errorf("DeleteNote(missing) error = %v, want %v", err, ErrNotFoundNote)
+	}
+}
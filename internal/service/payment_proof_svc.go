@@ -0,0 +1,235 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"darulabror/internal/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// PaymentProofMaxSize is the largest accepted transfer receipt.
+	PaymentProofMaxSize = 5 << 20
+
+	paymentProofURLTTL = 15 * time.Minute
+	mailTimeout        = 30 * time.Second
+
+	// PaymentProviderManual marks invoices created by approving a transfer proof.
+	PaymentProviderManual = "manual"
+)
+
+// proofTypes maps accepted receipt types to the object name extension.
+var proofTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+func (s *paymentService) UploadProof(ctx context.Context, code, nisn string, file io.Reader, size int64) (dto.PaymentProofDTO, error) {
+	reg, err := lookupRegistration(s.regRepo, code, nisn)
+	if err != nil {
+		return dto.PaymentProofDTO{}, err
+	}
+	if reg.Status != models.RegistrationStatusValidate {
+		return dto.PaymentProofDTO{}, ErrPaymentNotAllowed
+	}
+	fee, err := s.registrationFee(reg)
+	if err != nil {
+		return dto.PaymentProofDTO{}, err
+	}
+
+	if inv, err := s.repo.GetLatestInvoice(reg.ID); err == nil && inv.Status == models.InvoicePaid {
+		return dto.PaymentProofDTO{}, ErrPaymentAlreadyPaid
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.PaymentProofDTO{}, err
+	}
+	if proof, err := s.repo.GetLatestProof(reg.ID); err == nil && proof.Status == models.PaymentProofPending {
+		return dto.PaymentProofDTO{}, ErrPaymentProofPending
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.PaymentProofDTO{}, err
+	}
+
+	if size > PaymentProofMaxSize {
+		return dto.PaymentProofDTO{}, ErrUploadTooLarge
+	}
+	br := bufio.NewReaderSize(file, 512)
+	head, _ := br.Peek(512)
+	contentType := http.DetectContentType(head)
+	ext, ok := proofTypes[contentType]
+	if !ok {
+		return dto.PaymentProofDTO{}, ErrUploadTypeNotAllowed
+	}
+
+	proof := models.PaymentProof{
+		RegistrationID: reg.ID,
+		ObjectName:     fmt.Sprintf("payment-proofs/%d/%d%s", reg.ID, time.Now().UnixNano(), ext),
+		ContentType:    contentType,
+		Size:           size,
+		Amount:         fee,
+		Status:         models.PaymentProofPending,
+	}
+	if _, err := s.privateStore.UploadFile(ctx, br, proof.ObjectName); err != nil {
+		logrus.WithError(err).WithField("registration_id", reg.ID).Error("failed upload payment proof")
+		return dto.PaymentProofDTO{}, err
+	}
+	if err := s.repo.CreateProof(&proof); err != nil {
+		logrus.WithError(err).WithField("registration_id", reg.ID).Error("failed create payment proof")
+		return dto.PaymentProofDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"proof_id":        proof.ID,
+		"registration_id": reg.ID,
+		"size":            size,
+	}).Info("payment proof uploaded")
+	return dto.PaymentProofModelToDTO(proof), nil
+}
+
+func (s *paymentService) GetLatestProof(code, nisn string) (dto.PaymentProofDTO, error) {
+	reg, err := lookupRegistration(s.regRepo, code, nisn)
+	if err != nil {
+		return dto.PaymentProofDTO{}, err
+	}
+
+	proof, err := s.repo.GetLatestProof(reg.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.PaymentProofDTO{}, ErrNotFoundPaymentProof
+		}
+		return dto.PaymentProofDTO{}, err
+	}
+	return dto.PaymentProofModelToDTO(proof), nil
+}
+
+func (s *paymentService) GetProofQueue(ctx context.Context, q utils.ListQuery) ([]dto.PaymentProofDTO, utils.PageInfo, error) {
+	items, page, err := s.repo.GetProofs(q)
+	if err != nil {
+		logrus.WithError(err).Error("failed get payment proofs")
+		return nil, utils.PageInfo{}, err
+	}
+
+	out := make([]dto.PaymentProofDTO, 0, len(items))
+	for _, it := range items {
+		d := dto.PaymentProofModelToDTO(it.Proof)
+		d.FullName = it.Registration.FullName
+		d.TrackingCode = it.Registration.TrackingCode
+		d.ContentType = it.Proof.ContentType
+		if d.FileURL, err = s.privateStore.GenerateSignedURL(ctx, it.Proof.ObjectName, paymentProofURLTTL); err != nil {
+			logrus.WithError(err).WithField("proof_id", it.Proof.ID).Error("failed sign payment proof url")
+			return nil, utils.PageInfo{}, err
+		}
+		out = append(out, d)
+	}
+	return out, page, nil
+}
+
+func (s *paymentService) ApproveProof(id, adminID uint) (dto.PaymentProofDTO, error) {
+	proof, err := s.repo.GetProof(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.PaymentProofDTO{}, ErrNotFoundPaymentProof
+		}
+		return dto.PaymentProofDTO{}, err
+	}
+
+	var res repository.ProofDecision
+	err = s.repo.WithRegistrationLock(proof.RegistrationID, func(repo repository.PaymentRepo) error {
+		number, err := nextInvoiceNumber(repo, proof.RegistrationID)
+		if err != nil {
+			return err
+		}
+		res, err = repo.ApproveProof(id, adminID, models.Invoice{
+			Number:     number,
+			Amount:     proof.Amount,
+			Provider:   PaymentProviderManual,
+			ExternalID: fmt.Sprintf("proof-%d", id),
+			ExpiresAt:  time.Now().Unix(),
+		})
+		return err
+	})
+	switch {
+	case errors.Is(err, repository.ErrProofReviewed):
+		return dto.PaymentProofDTO{}, ErrPaymentProofReviewed
+	case errors.Is(err, repository.ErrAlreadyPaid):
+		return dto.PaymentProofDTO{}, ErrPaymentAlreadyPaid
+	case err != nil:
+		logrus.WithError(err).WithField("proof_id", id).Error("failed approve payment proof")
+		return dto.PaymentProofDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"proof_id":        id,
+		"invoice_id":      res.Invoice.ID,
+		"registration_id": proof.RegistrationID,
+		"admin_id":        adminID,
+		"advanced":        res.Advanced,
+	}).Info("payment proof approved")
+
+	s.notifyApplicant(proof.RegistrationID, "Pembayaran pendaftaran diterima", func(reg models.Registration) string {
+		return "Assalamu'alaikum,\n\n" +
+			"Bukti transfer biaya pendaftaran ananda " + reg.FullName + " (kode pendaftaran " + reg.TrackingCode + ") " +
+			"telah kami verifikasi dan DITERIMA. Pendaftaran dilanjutkan ke tahap seleksi.\n\n" +
+			"Wassalamu'alaikum."
+	})
+	return dto.PaymentProofModelToDTO(res.Proof), nil
+}
+
+func (s *paymentService) RejectProof(id, adminID uint, reason string) (dto.PaymentProofDTO, error) {
+	proof, err := s.repo.RejectProof(id, adminID, reason)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return dto.PaymentProofDTO{}, ErrNotFoundPaymentProof
+	case errors.Is(err, repository.ErrProofReviewed):
+		return dto.PaymentProofDTO{}, ErrPaymentProofReviewed
+	case err != nil:
+		logrus.WithError(err).WithField("proof_id", id).Error("failed reject payment proof")
+		return dto.PaymentProofDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"proof_id":        id,
+		"registration_id": proof.RegistrationID,
+		"admin_id":        adminID,
+	}).Info("payment proof rejected")
+
+	s.notifyApplicant(proof.RegistrationID, "Bukti pembayaran pendaftaran ditolak", func(reg models.Registration) string {
+		return "Assalamu'alaikum,\n\n" +
+			"Bukti transfer biaya pendaftaran ananda " + reg.FullName + " (kode pendaftaran " + reg.TrackingCode + ") " +
+			"belum dapat kami terima.\nAlasan: " + reason + "\n\n" +
+			"Silakan unggah ulang bukti transfer yang benar melalui halaman cek pendaftaran.\n\n" +
+			"Wassalamu'alaikum."
+	})
+	return dto.PaymentProofModelToDTO(proof), nil
+}
+
+// notifyApplicant emails the applicant in the background; shutdown waits for it (Close).
+// A failed email is logged and does not undo the decision.
+func (s *paymentService) notifyApplicant(registrationID uint, subject string, body func(models.Registration) string) {
+	s.jobs.Go(func(ctx context.Context) {
+		reg, err := s.regRepo.GetByID(registrationID)
+		if err != nil {
+			logrus.WithError(err).WithField("registration_id", registrationID).Error("failed get registration for email")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+		defer cancel()
+		if err := s.mailer.Send(ctx, repository.MailMessage{To: reg.Email, Subject: subject, Body: body(reg)}); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"registration_id": registrationID,
+				"subject":         subject,
+			}).Error("failed send email")
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeObjectStore struct {
+	repository.ObjectStore
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStore) UploadFile(ctx context.Context, file io.Reader, objectName string) (string, error) {
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[objectName] = b
+	return objectName, nil
+}
+
+type fakeMailer struct {
+	sent chan repository.MailMessage
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg repository.MailMessage) error {
+	f.sent <- msg
+	return nil
+}
+
+func (f *fakeMailer) wait(t *testing.T) repository.MailMessage {
+	t.Helper()
+	select {
+	case msg := <-f.sent:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no email sent")
+		return repository.MailMessage{}
+	}
+}
+
+var pngReceipt = "\x89PNG\r\n\x1a\n" + strings.Repeat("0", 64)
+
+func newProofTestService() (*paymentService, *fakePaymentRepo, *fakeRegistrationRepo, *fakeObjectStore, *fakeMailer) {
+	svc, repo, regs := newPaymentTestService(&fakeGateway{})
+	store := &fakeObjectStore{objects: map[string][]byte{}}
+	mailer := &fakeMailer{sent: make(chan repository.MailMessage, 4)}
+	svc.privateStore, svc.mailer = store, mailer
+	return svc, repo, regs, store, mailer
+}
+
+func uploadReceipt(svc *paymentService, body string) error {
+	_, err := svc.UploadProof(context.Background(), "PSB-ABC", "0012345678", strings.NewReader(body), int64(len(body)))
+	return err
+}
+
+func TestUploadProof(t *testing.T) {
+	svc, _, _, store, _ := newProofTestService()
+
+	for name, body := range map[string]string{
+		"html":       "<html><body>receipt</body></html>",
+		"executable": "MZ\x90\x00\x03\x00\x00\x00",
+		"zip":        "PK\x03\x04" + strings.Repeat("\x00", 32),
+	} {
+		if err := uploadReceipt(svc, body); !errors.Is(err, ErrUploadTypeNotAllowed) {
+			t.Errorf("%s: UploadProof() error = %v, want %v", name, err, ErrUploadTypeNotAllowed)
+		}
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("rejected files were stored: %d objects", len(store.objects))
+	}
+
+	if err := uploadReceipt(svc, pngReceipt); err != nil {
+		t.Fatalf("UploadProof(png) error = %v", err)
+	}
+	for name, b := range store.objects {
+		if !strings.HasSuffix(name, ".png") || !bytes.Equal(b, []byte(pngReceipt)) {
+			t.Errorf("stored %s (%d bytes), want the whole png receipt", name, len(b))
+		}
+	}
+	if err := uploadReceipt(svc, "%PDF-1.7\n"+strings.Repeat("0", 32)); !errors.Is(err, ErrPaymentProofPending) {
+		t.Errorf("second upload error = %v, want %v", err, ErrPaymentProofPending)
+	}
+}
+
+func TestApproveProof(t *testing.T) {
+	svc, repo, regs, _, mailer := newProofTestService()
+
+	// the applicant opened a gateway invoice first, then paid by transfer
+	if _, _, err := svc.GetOrCreateInvoice(context.Background(), "PSB-ABC", "0012345678"); err != nil {
+		t.Fatal(err)
+	}
+	if err := uploadReceipt(svc, pngReceipt); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.ApproveProof(1, 9)
+	if err != nil || got.Status != models.PaymentProofApproved {
+		t.Fatalf("ApproveProof() = %+v, %v", got, err)
+	}
+	if reg, _ := regs.GetByID(42); reg.Status != models.RegistrationStatusProcess {
+		t.Errorf("registration status = %s, want process", reg.Status)
+	}
+	if repo.invoices[0].Status != models.InvoiceExpired {
+		t.Errorf("gateway invoice status = %s, want expired", repo.invoices[0].Status)
+	}
+	if paid := repo.invoices[1]; paid.Status != models.InvoicePaid || paid.Number != "PSB-000042-02" || paid.Provider != PaymentProviderManual {
+		t.Errorf("proof invoice = %+v", paid)
+	}
+	// Close waits for the applicant email, so it is sent before shutdown completes
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Errorf("emails sent before Close returned = %d, want 1", len(mailer.sent))
+	}
+
+	if _, err := svc.ApproveProof(1, 9); !errors.Is(err, ErrPaymentProofReviewed) {
+		t.Errorf("second approve error = %v, want %v", err, ErrPaymentProofReviewed)
+	}
+	if _, err := svc.RejectProof(1, 9, "blurry"); !errors.Is(err, ErrPaymentProofReviewed) {
+		t.Errorf("reject after approve error = %v, want %v", err, ErrPaymentProofReviewed)
+	}
+}
+
+func TestApproveProofAlreadyPaid(t *testing.T) {
+	svc, repo, _, _, mailer := newProofTestService()
+	if err := uploadReceipt(svc, pngReceipt); err != nil {
+		t.Fatal(err)
+	}
+	// paid at the gateway while the proof waited for review
+	repo.invoices = append(repo.invoices, models.Invoice{ID: 1, RegistrationID: 42, Number: "PSB-000042-01", Status: models.InvoicePaid})
+
+	if _, err := svc.ApproveProof(1, 9); !errors.Is(err, ErrPaymentAlreadyPaid) {
+		t.Errorf("ApproveProof() error = %v, want %v", err, ErrPaymentAlreadyPaid)
+	}
+
+	if _, err := svc.RejectProof(1, 9, "already paid online"); err != nil {
+		t.Fatalf("RejectProof() error = %v", err)
+	}
+	if msg := mailer.wait(t); !strings.Contains(msg.Body, "already paid online") {
+		t.Errorf("rejection email = %q, want the reason", msg.Body)
+	}
+	if _, err := svc.RejectProof(1, 9, "again"); !errors.Is(err, ErrPaymentProofReviewed) {
+		t.Errorf("second reject error = %v, want %v", err, ErrPaymentProofReviewed)
+	}
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"darulabror/internal/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultInvoiceTTL = 24 * time.Hour
+	// chargeTimeout is how long an invoice may wait for its gateway charge; after it the
+	// request that opened it is assumed dead and a new invoice is opened.
+	chargeTimeout = 2 * time.Minute
+)
+
+// PaymentConfig is loaded by config.LoadPaymentConfig; a nil Gateway disables online payment.
+type PaymentConfig struct {
+	Gateway    repository.PaymentGateway
+	InvoiceTTL time.Duration // how long a payment page stays valid (default 24h)
+}
+
+type PaymentService interface {
+	// GetOrCreateInvoice returns the open (or paid) invoice of the applicant, or opens a new
+	// one at the gateway; created is true for a new invoice.
+	GetOrCreateInvoice(ctx context.Context, code, nisn string) (dto.InvoiceDTO, bool, error)
+	GetLatestInvoice(code, nisn string) (dto.InvoiceDTO, error)
+	GetInvoices(registrationID uint) ([]dto.InvoiceDTO, error)
+
+	// HandleWebhook verifies and applies a gateway notification; repeated deliveries are no-ops.
+	HandleWebhook(provider string, header http.Header, body []byte) error
+	// SimulateFakePayment pays an invoice of the fake provider (local development).
+	SimulateFakePayment(number string) error
+
+	// Bank transfer proofs (payment_proof_svc.go)
+	UploadProof(ctx context.Context, code, nisn string, file io.Reader, size int64) (dto.PaymentProofDTO, error)
+	GetLatestProof(code, nisn string) (dto.PaymentProofDTO, error)
+	GetProofQueue(ctx context.Context, q utils.ListQuery) ([]dto.PaymentProofDTO, utils.PageInfo, error)
+	ApproveProof(id, adminID uint) (dto.PaymentProofDTO, error)
+	RejectProof(id, adminID uint, reason string) (dto.PaymentProofDTO, error)
+
+	// Close waits for the applicant emails still being sent, like ExportService.Close.
+	Close(ctx context.Context) error
+}
+
+type paymentService struct {
+	cfg          PaymentConfig
+	repo         repository.PaymentRepo
+	regRepo      repository.RegistrationRepo
+	periodRepo   repository.AdmissionPeriodRepo
+	privateStore repository.ObjectStore
+	mailer       repository.Mailer
+	jobs         *jobRunner
+}
+
+func NewPaymentService(
+	cfg PaymentConfig,
+	repo repository.PaymentRepo,
+	regRepo repository.RegistrationRepo,
+	periodRepo repository.AdmissionPeriodRepo,
+	privateStore repository.ObjectStore,
+	mailer repository.Mailer,
+) PaymentService {
+	if cfg.InvoiceTTL <= 0 {
+		cfg.InvoiceTTL = defaultInvoiceTTL
+	}
+	return &paymentService{
+		cfg:          cfg,
+		repo:         repo,
+		regRepo:      regRepo,
+		periodRepo:   periodRepo,
+		privateStore: privateStore,
+		mailer:       mailer,
+		jobs:         newJobRunner(),
+	}
+}
+
+func (s *paymentService) Close(ctx context.Context) error {
+	return s.jobs.Close(ctx)
+}
+
+func (s *paymentService) GetOrCreateInvoice(ctx context.Context, code, nisn string) (dto.InvoiceDTO, bool, error) {
+	reg, err := lookupRegistration(s.regRepo, code, nisn)
+	if err != nil {
+		return dto.InvoiceDTO{}, false, err
+	}
+
+	// the invoice is inserted under the registration lock before the gateway is called, so
+	// concurrent requests never open two charges or race for the next invoice number
+	var (
+		inv    models.Invoice
+		opened bool
+	)
+	err = s.repo.WithRegistrationLock(reg.ID, func(repo repository.PaymentRepo) error {
+		latest, err := repo.GetLatestInvoice(reg.ID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+		case err != nil:
+			logrus.WithError(err).WithField("registration_id", reg.ID).Error("failed get latest invoice")
+			return err
+		case latest.Status == models.InvoicePaid:
+			inv = latest
+			return nil
+		case latest.Status == models.InvoicePending && latest.PaymentURL == "" && time.Since(time.Unix(latest.CreatedAt, 0)) < chargeTimeout:
+			// another request is calling the gateway for it
+			return ErrInvoiceInProgress
+		case latest.Status == models.InvoicePending && latest.PaymentURL != "" && time.Now().Unix() < latest.ExpiresAt:
+			inv = latest
+			return nil
+		case latest.Status == models.InvoicePending:
+			// the gateway's expire webhook may not have arrived yet, or opening it failed midway
+			if err := repo.ExpireInvoice(latest.ID); err != nil {
+				logrus.WithError(err).WithField("invoice_id", latest.ID).Error("failed expire invoice")
+				return err
+			}
+		}
+
+		if reg.Status != models.RegistrationStatusValidate {
+			return ErrPaymentNotAllowed
+		}
+		fee, err := s.registrationFee(reg)
+		if err != nil {
+			return err
+		}
+		if s.cfg.Gateway == nil {
+			return ErrPaymentNotConfigured
+		}
+
+		number, err := nextInvoiceNumber(repo, reg.ID)
+		if err != nil {
+			return err
+		}
+		inv = models.Invoice{
+			RegistrationID: reg.ID,
+			Number:         number,
+			Amount:         fee,
+			Status:         models.InvoicePending,
+			Provider:       s.cfg.Gateway.Name(),
+			ExpiresAt:      time.Now().Add(s.cfg.InvoiceTTL).Unix(),
+		}
+		if err := repo.CreateInvoice(&inv); err != nil {
+			logrus.WithError(err).WithField("number", inv.Number).Error("failed create invoice")
+			return err
+		}
+		opened = true
+		return nil
+	})
+	if err != nil {
+		return dto.InvoiceDTO{}, false, err
+	}
+	if !opened {
+		return dto.InvoiceModelToDTO(inv), false, nil
+	}
+
+	charge, err := s.cfg.Gateway.CreateCharge(ctx, repository.ChargeRequest{
+		InvoiceNumber: inv.Number,
+		Amount:        inv.Amount,
+		Description:   "Biaya pendaftaran santri baru " + reg.TrackingCode,
+		CustomerName:  reg.FullName,
+		CustomerEmail: reg.Email,
+		CustomerPhone: reg.Phone,
+		ExpiresAt:     time.Unix(inv.ExpiresAt, 0),
+	})
+	if err != nil {
+		// the next request opens a new invoice
+		if err := s.repo.ExpireInvoice(inv.ID); err != nil {
+			logrus.WithError(err).WithField("invoice_id", inv.ID).Error("failed expire invoice")
+		}
+		if errors.Is(err, repository.ErrPaymentNotConfigured) {
+			return dto.InvoiceDTO{}, false, ErrPaymentNotConfigured
+		}
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"registration_id": reg.ID,
+			"provider":        inv.Provider,
+		}).Error("failed create payment charge")
+		return dto.InvoiceDTO{}, false, err
+	}
+	inv.ExternalID = charge.ExternalID
+	inv.PaymentURL = charge.PaymentURL
+
+	if err := s.repo.AttachCharge(inv.ID, inv.ExternalID, inv.PaymentURL); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"invoice_id":  inv.ID,
+			"external_id": inv.ExternalID,
+		}).Error("failed store payment charge")
+		return dto.InvoiceDTO{}, false, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"invoice_id":      inv.ID,
+		"number":          inv.Number,
+		"registration_id": reg.ID,
+		"amount":          inv.Amount,
+		"provider":        inv.Provider,
+	}).Info("invoice created")
+	return dto.InvoiceModelToDTO(inv), true, nil
+}
+
+// nextInvoiceNumber returns e.g. "PSB-000042-02" for the second invoice of registration 42.
+// repo must hold the registration lock.
+func nextInvoiceNumber(repo repository.PaymentRepo, registrationID uint) (string, error) {
+	n, err := repo.CountInvoices(registrationID)
+	if err != nil {
+		logrus.WithError(err).WithField("registration_id", registrationID).Error("failed count invoices")
+		return "", err
+	}
+	return fmt.Sprintf("PSB-%06d-%02d", registrationID, n+1), nil
+}
+
+// registrationFee returns the fee of the registration's period, or ErrPaymentNotRequired.
+func (s *paymentService) registrationFee(reg models.Registration) (int64, error) {
+	if reg.AdmissionPeriodID == nil {
+		return 0, ErrPaymentNotRequired
+	}
+	period, err := s.periodRepo.GetByID(*reg.AdmissionPeriodID)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", *reg.AdmissionPeriodID).Error("failed get admission period for invoice")
+		return 0, err
+	}
+	if period.RegistrationFee <= 0 {
+		return 0, ErrPaymentNotRequired
+	}
+	return period.RegistrationFee, nil
+}
+
+func (s *paymentService) GetLatestInvoice(code, nisn string) (dto.InvoiceDTO, error) {
+	reg, err := lookupRegistration(s.regRepo, code, nisn)
+	if err != nil {
+		return dto.InvoiceDTO{}, err
+	}
+
+	inv, err := s.repo.GetLatestInvoice(reg.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.InvoiceDTO{}, ErrNotFoundInvoice
+		}
+		logrus.WithError(err).WithField("registration_id", reg.ID).Error("failed get latest invoice")
+		return dto.InvoiceDTO{}, err
+	}
+	return dto.InvoiceModelToDTO(inv), nil
+}
+
+func (s *paymentService) GetInvoices(registrationID uint) ([]dto.InvoiceDTO, error) {
+	if _, err := s.regRepo.GetByID(registrationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFoundRegistration
+		}
+		return nil, err
+	}
+
+	invs, err := s.repo.GetInvoices(registrationID)
+	if err != nil {
+		logrus.WithError(err).WithField("registration_id", registrationID).Error("failed get invoices")
+		return nil, err
+	}
+
+	out := make([]dto.InvoiceDTO, 0, len(invs))
+	for _, inv := range invs {
+		out = append(out, dto.InvoiceModelToDTO(inv))
+	}
+	return out, nil
+}
+
+func (s *paymentService) HandleWebhook(provider string, header http.Header, body []byte) error {
+	if s.cfg.Gateway == nil || provider != s.cfg.Gateway.Name() {
+		return ErrUnknownPaymentProvider
+	}
+
+	n, err := s.cfg.Gateway.ParseWebhook(header, body)
+	if err != nil {
+		logrus.WithError(err).WithField("provider", provider).Warn("rejected payment webhook")
+		return ErrInvalidPaymentWebhook
+	}
+	if n.Status == "" {
+		// e.g. "pending": nothing to apply
+		return nil
+	}
+
+	inv, err := s.repo.GetInvoiceByNumber(n.InvoiceNumber)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundInvoice
+		}
+		return err
+	}
+	if inv.Provider != provider {
+		return ErrNotFoundInvoice
+	}
+	if n.Status == models.InvoicePaid && n.Amount != inv.Amount {
+		logrus.WithFields(logrus.Fields{
+			"invoice_id": inv.ID,
+			"amount":     inv.Amount,
+			"paid":       n.Amount,
+		}).Warn("payment amount mismatch")
+		return ErrPaymentAmountMismatch
+	}
+
+	res, err := s.repo.Reconcile(models.PaymentEvent{
+		Provider:  provider,
+		EventID:   n.EventID,
+		InvoiceID: inv.ID,
+		Status:    n.Status,
+		Amount:    n.Amount,
+		Payload:   n.Payload,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("invoice_id", inv.ID).Error("failed reconcile payment")
+		return err
+	}
+	if res.DoublePaid {
+		logrus.WithFields(logrus.Fields{
+			"invoice_id":      inv.ID,
+			"registration_id": inv.RegistrationID,
+			"amount":          n.Amount,
+		}).Warn("payment received for an already paid registration: refund it at the gateway")
+		return nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"invoice_id":      inv.ID,
+		"registration_id": inv.RegistrationID,
+		"event_id":        n.EventID,
+		"status":          res.Invoice.Status,
+		"duplicate":       res.Duplicate,
+		"advanced":        res.Advanced,
+	}).Info("payment notification applied")
+	return nil
+}
+
+func (s *paymentService) SimulateFakePayment(number string) error {
+	fake, ok := s.cfg.Gateway.(repository.FakeGateway)
+	if !ok {
+		return ErrUnknownPaymentProvider
+	}
+
+	inv, err := s.repo.GetInvoiceByNumber(number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundInvoice
+		}
+		return err
+	}
+
+	header, body, err := fake.Notify(inv.Number, models.InvoicePaid, inv.Amount)
+	if err != nil {
+		return err
+	}
+	return s.HandleWebhook(fake.Name(), header, body)
+}
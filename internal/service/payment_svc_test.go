@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fakeRegistrationRepo struct {
+	repository.RegistrationRepo
+	mu        sync.Mutex
+	regs      map[uint]models.Registration
+	updateErr error
+}
+
+func (f *fakeRegistrationRepo) ExistsByEmail(periodID uint, email string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeRegistrationRepo) ExistsByNISN(periodID uint, nisn string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeRegistrationRepo) Update(reg models.Registration, changes []models.RegistrationChange) error {
+	return f.updateErr
+}
+
+func (f *fakeRegistrationRepo) GetByID(id uint) (models.Registration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	reg, ok := f.regs[id]
+	if !ok {
+		return models.Registration{}, gorm.ErrRecordNotFound
+	}
+	return reg, nil
+}
+
+func (f *fakeRegistrationRepo) GetByTrackingCode(code string) (models.Registration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, reg := range f.regs {
+		if reg.TrackingCode == code {
+			return reg, nil
+		}
+	}
+	return models.Registration{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRegistrationRepo) setStatus(id uint, status models.RegistrationStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	reg := f.regs[id]
+	reg.Status = status
+	f.regs[id] = reg
+}
+
+// fakePaymentRepo keeps invoices and proofs in memory; lock plays the registration row lock.
+type fakePaymentRepo struct {
+	repository.PaymentRepo
+	lock     sync.Mutex
+	mu       sync.Mutex
+	regs     *fakeRegistrationRepo
+	invoices []models.Invoice
+	proofs   map[uint]models.PaymentProof
+}
+
+func (f *fakePaymentRepo) WithRegistrationLock(registrationID uint, fn func(repo repository.PaymentRepo) error) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return fn(f)
+}
+
+func (f *fakePaymentRepo) CreateInvoice(inv *models.Invoice) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, other := range f.invoices {
+		if other.Number == inv.Number {
+			return fmt.Errorf("duplicate invoice number %s", inv.Number)
+		}
+	}
+	inv.ID = uint(len(f.invoices) + 1)
+	inv.CreatedAt = time.Now().Unix()
+	f.invoices = append(f.invoices, *inv)
+	return nil
+}
+
+func (f *fakePaymentRepo) GetLatestInvoice(registrationID uint) (models.Invoice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.invoices) - 1; i >= 0; i-- {
+		if f.invoices[i].RegistrationID == registrationID {
+			return f.invoices[i], nil
+		}
+	}
+	return models.Invoice{}, gorm.ErrRecordNotFound
+}
+
+func (f *fakePaymentRepo) CountInvoices(registrationID uint) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int64
+	for _, inv := range f.invoices {
+		if inv.RegistrationID == registrationID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakePaymentRepo) ExpireInvoice(id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if inv := &f.invoices[id-1]; inv.Status == models.InvoicePending {
+		inv.Status = models.InvoiceExpired
+	}
+	return nil
+}
+
+func (f *fakePaymentRepo) AttachCharge(id uint, externalID, paymentURL string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.invoices[id-1].ExternalID = externalID
+	f.invoices[id-1].PaymentURL = paymentURL
+	return nil
+}
+
+type fakeGateway struct {
+	repository.PaymentGateway
+	charges atomic.Int32
+}
+
+func (g *fakeGateway) Name() string { return "fake" }
+
+func (g *fakeGateway) CreateCharge(ctx context.Context, req repository.ChargeRequest) (repository.Charge, error) {
+	g.charges.Add(1)
+	time.Sleep(20 * time.Millisecond)
+	return repository.Charge{ExternalID: "ext-" + req.InvoiceNumber, PaymentURL: "https://pay.example/" + req.InvoiceNumber}, nil
+}
+
+func newPaymentTestService(gateway repository.PaymentGateway) (*paymentService, *fakePaymentRepo, *fakeRegistrationRepo) {
+	periodID := uint(1)
+	regs := &fakeRegistrationRepo{regs: map[uint]models.Registration{
+		42: {ID: 42, TrackingCode: "PSB-ABC", NISN: "0012345678", Status: models.RegistrationStatusValidate, AdmissionPeriodID: &periodID},
+	}}
+	periods := &fakePeriodRepo{periods: []models.AdmissionPeriod{{ID: 1, RegistrationFee: 250000}}}
+	repo := &fakePaymentRepo{regs: regs, proofs: map[uint]models.PaymentProof{}}
+	svc := NewPaymentService(PaymentConfig{Gateway: gateway}, repo, regs, periods, nil, nil).(*paymentService)
+	return svc, repo, regs
+}
+
+func TestGetOrCreateInvoiceConcurrent(t *testing.T) {
+	gateway := &fakeGateway{}
+	svc, repo, _ := newPaymentTestService(gateway)
+
+	var (
+		wg      sync.WaitGroup
+		created atomic.Int32
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, isNew, err := svc.GetOrCreateInvoice(context.Background(), "PSB-ABC", "0012345678")
+			if err != nil && !errors.Is(err, ErrInvoiceInProgress) {
+				t.Errorf("GetOrCreateInvoice() error = %v", err)
+			}
+			if isNew {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := gateway.charges.Load(); got != 1 {
+		t.Errorf("gateway charges = %d, want 1", got)
+	}
+	if created.Load() != 1 || len(repo.invoices) != 1 {
+		t.Errorf("created = %d, invoices = %d; want 1 and 1", created.Load(), len(repo.invoices))
+	}
+
+	inv, isNew, err := svc.GetOrCreateInvoice(context.Background(), "PSB-ABC", "0012345678")
+	if err != nil || isNew || inv.PaymentURL == "" {
+		t.Errorf("later call = %+v, %v, %v; want the open invoice", inv, isNew, err)
+	}
+}
+
+func (f *fakePaymentRepo) CreateProof(proof *models.PaymentProof) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	proof.ID = uint(len(f.proofs) + 1)
+	f.proofs[proof.ID] = *proof
+	return nil
+}
+
+func (f *fakePaymentRepo) GetProof(id uint) (models.PaymentProof, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	proof, ok := f.proofs[id]
+	if !ok {
+		return models.PaymentProof{}, gorm.ErrRecordNotFound
+	}
+	return proof, nil
+}
+
+func (f *fakePaymentRepo) GetLatestProof(registrationID uint) (models.PaymentProof, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var latest models.PaymentProof
+	for _, p := range f.proofs {
+		if p.RegistrationID == registrationID && p.ID > latest.ID {
+			latest = p
+		}
+	}
+	if latest.ID == 0 {
+		return latest, gorm.ErrRecordNotFound
+	}
+	return latest, nil
+}
+
+func (f *fakePaymentRepo) ApproveProof(id, adminID uint, inv models.Invoice) (repository.ProofDecision, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var res repository.ProofDecision
+	res.Proof = f.proofs[id]
+	if res.Proof.Status != models.PaymentProofPending {
+		return res, repository.ErrProofReviewed
+	}
+	for i := range f.invoices {
+		if f.invoices[i].RegistrationID != res.Proof.RegistrationID {
+			continue
+		}
+		switch f.invoices[i].Status {
+		case models.InvoicePaid:
+			return res, repository.ErrAlreadyPaid
+		case models.InvoicePending:
+			f.invoices[i].Status = models.InvoiceExpired
+		}
+	}
+
+	inv.ID = uint(len(f.invoices) + 1)
+	inv.RegistrationID = res.Proof.RegistrationID
+	inv.Status = models.InvoicePaid
+	f.invoices = append(f.invoices, inv)
+	res.Invoice = inv
+	res.Proof.Status = models.PaymentProofApproved
+	res.Proof.InvoiceID = &inv.ID
+	f.proofs[id] = res.Proof
+
+	if reg, _ := f.regs.GetByID(res.Proof.RegistrationID); reg.Status == models.RegistrationStatusValidate {
+		f.regs.setStatus(reg.ID, models.RegistrationStatusProcess)
+		res.Advanced = true
+	}
+	return res, nil
+}
+
+func (f *fakePaymentRepo) RejectProof(id, adminID uint, reason string) (models.PaymentProof, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	proof, ok := f.proofs[id]
+	if !ok {
+		return proof, gorm.ErrRecordNotFound
+	}
+	if proof.Status != models.PaymentProofPending {
+		return proof, repository.ErrProofReviewed
+	}
+	proof.Status = models.PaymentProofRejected
+	proof.RejectReason = reason
+	f.proofs[id] = proof
+	return proof, nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"darulabror/internal/models"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Letterhead is printed at the top of every document (see config.LoadDocumentConfig).
+type Letterhead struct {
+	SchoolName     string
+	Address        string
+	Contact        string // phone, email, website
+	City           string // place printed next to the letter date
+	SignatoryName  string
+	SignatoryTitle string
+	LogoPath       string // optional PNG/JPEG
+}
+
+// defaultLetterTemplates are used until a superadmin saves a template of that kind.
+var defaultLetterTemplates = map[models.LetterKind]models.LetterTemplate{
+	models.LetterAcceptance: {
+		Kind:    models.LetterAcceptance,
+		Subject: "Pengumuman Hasil Seleksi Penerimaan Santri Baru",
+		Body: "Assalamu'alaikum warahmatullahi wabarakatuh,\n\n" +
+			"Berdasarkan hasil seleksi penerimaan santri baru {{.PeriodName}} tahun ajaran {{.AcademicYear}}, " +
+			"dengan ini kami sampaikan bahwa ananda:\n\n" +
+			"Nama: {{.FullName}}\nNISN: {{.NISN}}\nKode Pendaftaran: {{.TrackingCode}}\nProgram: {{.Program}}\n\n" +
+			"dinyatakan DITERIMA sebagai santri baru {{.SchoolName}}. " +
+			"Informasi daftar ulang akan kami kirimkan melalui email {{.Email}}.\n\n" +
+			"Wassalamu'alaikum warahmatullahi wabarakatuh.",
+	},
+	models.LetterRejection: {
+		Kind:    models.LetterRejection,
+		Subject: "Pengumuman Hasil Seleksi Penerimaan Santri Baru",
+		Body: "Assalamu'alaikum warahmatullahi wabarakatuh,\n\n" +
+			"Terima kasih atas kepercayaan Bapak/Ibu kepada {{.SchoolName}}. " +
+			"Berdasarkan hasil seleksi penerimaan santri baru {{.PeriodName}} tahun ajaran {{.AcademicYear}}, " +
+			"dengan berat hati kami sampaikan bahwa ananda:\n\n" +
+			"Nama: {{.FullName}}\nNISN: {{.NISN}}\nKode Pendaftaran: {{.TrackingCode}}\n\n" +
+			"BELUM DAPAT DITERIMA pada periode ini. Semoga ananda mendapatkan tempat belajar terbaik.\n\n" +
+			"Wassalamu'alaikum warahmatullahi wabarakatuh.",
+	},
+}
+
+// letterKindFor returns the decision letter of a status; ok is false while no decision was made.
+func letterKindFor(status models.RegistrationStatus) (models.LetterKind, bool) {
+	switch status {
+	case models.RegistrationStatusDone:
+		return models.LetterAcceptance, true
+	case models.RegistrationStatusRejected:
+		return models.LetterRejection, true
+	}
+	return "", false
+}
+
+// letterData is the data available in letter templates, e.g. {{.FullName}}.
+type letterData struct {
+	FullName     string
+	NISN         string
+	Email        string
+	TrackingCode string
+	Gender       string
+	Program      string
+	OriginSchool string
+	PeriodName   string
+	AcademicYear string
+	SchoolName   string
+	Date         string
+}
+
+func newLetterData(lh Letterhead, reg models.Registration, period models.AdmissionPeriod, now time.Time) letterData {
+	return letterData{
+		FullName:     reg.FullName,
+		NISN:         reg.NISN,
+		Email:        reg.Email,
+		TrackingCode: reg.TrackingCode,
+		Gender:       labelOr(genderLabels, reg.Gender),
+		Program:      labelOr(programLabels, reg.Program),
+		OriginSchool: reg.OriginSchool,
+		PeriodName:   period.Name,
+		AcademicYear: period.AcademicYear,
+		SchoolName:   lh.SchoolName,
+		Date:         formatIndonesianDate(now),
+	}
+}
+
+// sampleLetterData is used to check an edited template before it is saved.
+var sampleLetterData = letterData{
+	FullName:     "Ahmad Fauzi",
+	NISN:         "0012345678",
+	Email:        "ahmad@example.com",
+	TrackingCode: "DA-7K3M-Q9TX",
+	Gender:       "Laki-laki",
+	Program:      "Asrama",
+	OriginSchool: "SDN 1 Contoh",
+	PeriodName:   "Gelombang 1",
+	AcademicYear: "2025/2026",
+	SchoolName:   "Pondok Pesantren Darul Abror",
+	Date:         "2 Januari 2025",
+}
+
+// executeLetterTemplate renders subject and body; unknown fields are an error.
+func executeLetterTemplate(tpl models.LetterTemplate, data letterData) (subject, body string, err error) {
+	render := func(name, text string) (string, error) {
+		t, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	if subject, err = render("subject", tpl.Subject); err != nil {
+		return "", "", err
+	}
+	if body, err = render("body", tpl.Body); err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// formatIndonesianDate formats e.g. "2 Januari 2025" (WIB).
+func formatIndonesianDate(t time.Time) string {
+	t = t.In(exportLocation)
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+const (
+	pdfMargin = 20.0
+	pdfFont   = "Helvetica"
+)
+
+// newDocumentPDF starts an A4 document; tr converts UTF-8 text for the core fonts.
+func newDocumentPDF() (*fpdf.Fpdf, func(string) string) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, 15, pdfMargin)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+	return pdf, pdf.UnicodeTranslatorFromDescriptor("")
+}
+
+func drawLetterhead(pdf *fpdf.Fpdf, tr func(string) string, lh Letterhead) {
+	pageW, _ := pdf.GetPageSize()
+	_, top, _, _ := pdf.GetMargins()
+
+	textX := pdfMargin
+	if lh.LogoPath != "" {
+		pdf.ImageOptions(lh.LogoPath, pdfMargin, top, 22, 0, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+		textX += 26
+	}
+	width := pageW - pdfMargin - textX
+
+	pdf.SetXY(textX, top)
+	pdf.SetFont(pdfFont, "B", 16)
+	pdf.CellFormat(width, 8, tr(strings.ToUpper(lh.SchoolName)), "", 2, "C", false, 0, "")
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.CellFormat(width, 5, tr(lh.Address), "", 2, "C", false, 0, "")
+	pdf.CellFormat(width, 5, tr(lh.Contact), "", 2, "C", false, 0, "")
+
+	y := top + 26
+	pdf.SetLineWidth(0.8)
+	pdf.Line(pdfMargin, y, pageW-pdfMargin, y)
+	pdf.SetLineWidth(0.2)
+	pdf.Line(pdfMargin, y+1, pageW-pdfMargin, y+1)
+	pdf.SetXY(pdfMargin, y+6)
+}
+
+// renderRegistrationCard writes the proof of registration with its tracking code as QR code.
+func renderRegistrationCard(w io.Writer, lh Letterhead, reg models.Registration, period models.AdmissionPeriod) error {
+	pdf, tr := newDocumentPDF()
+	drawLetterhead(pdf, tr, lh)
+	pageW, _ := pdf.GetPageSize()
+
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.CellFormat(0, 8, "KARTU PENDAFTARAN SANTRI BARU", "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(0, 6, tr(period.Name+" - Tahun Ajaran "+period.AcademicYear), "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	qr, err := qrcode.Encode(reg.TrackingCode, qrcode.Medium, 256)
+	if err != nil {
+		return err
+	}
+	const qrSize = 40.0
+	y := pdf.GetY()
+	pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", pageW-pdfMargin-qrSize, y, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.CellFormat(0, 5, "Kode Pendaftaran", "", 1, "L", false, 0, "")
+	pdf.SetFont("Courier", "B", 20)
+	pdf.CellFormat(0, 10, reg.TrackingCode, "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	rows := [][2]string{
+		{"No. Pendaftaran", fmt.Sprintf("%06d", reg.ID)},
+		{"Nama Lengkap", reg.FullName},
+		{"NISN", reg.NISN},
+		{"Jenis Kelamin", labelOr(genderLabels, reg.Gender)},
+		{"Tempat, Tanggal Lahir", reg.PlaceOfBirth + ", " + formatIndonesianDate(reg.DateOfBirth)},
+		{"Asal Sekolah", reg.OriginSchool},
+		{"Jenis Pendaftar", labelOr(studentTypeLabels, reg.StudentType)},
+		{"Program", labelOr(programLabels, reg.Program)},
+		{"Tanggal Daftar", formatIndonesianDate(reg.CreatedAt)},
+	}
+	pdf.SetFont(pdfFont, "", 10)
+	for _, r := range rows {
+		pdf.CellFormat(45, 7, tr(r[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, tr(": "+r[1]), "", 1, "L", false, 0, "")
+	}
+	if pdf.GetY() < y+qrSize {
+		pdf.SetY(y + qrSize)
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont(pdfFont, "I", 9)
+	pdf.MultiCell(0, 5, tr("Simpan kartu ini sebagai bukti pendaftaran. Status pendaftaran dapat dicek "+
+		"dengan kode pendaftaran dan NISN."), "", "L", false)
+
+	return pdf.Output(w)
+}
+
+// renderLetter writes an acceptance/rejection letter whose body comes from a LetterTemplate.
+func renderLetter(w io.Writer, lh Letterhead, tpl models.LetterTemplate, reg models.Registration, period models.AdmissionPeriod, now time.Time) error {
+	data := newLetterData(lh, reg, period, now)
+	subject, body, err := executeLetterTemplate(tpl, data)
+	if err != nil {
+		return err
+	}
+
+	pdf, tr := newDocumentPDF()
+	drawLetterhead(pdf, tr, lh)
+	pageW, _ := pdf.GetPageSize()
+
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(0, 6, tr(lh.City+", "+data.Date), "", 1, "R", false, 0, "")
+	header := [][2]string{
+		{"Nomor", fmt.Sprintf("%06d/PSB/%d", reg.ID, now.In(exportLocation).Year())},
+		{"Lampiran", "-"},
+		{"Perihal", subject},
+	}
+	for _, h := range header {
+		pdf.CellFormat(25, 6, h[0], "", 0, "L", false, 0, "")
+		pdf.MultiCell(0, 6, tr(": "+h[1]), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	pdf.MultiCell(0, 6, tr("Kepada Yth.\nOrang Tua/Wali dari "+reg.FullName+"\ndi tempat"), "", "L", false)
+	pdf.Ln(4)
+	pdf.MultiCell(0, 6, tr(body), "", "J", false)
+	pdf.Ln(10)
+
+	signX := pageW/2 + 10
+	pdf.SetX(signX)
+	pdf.CellFormat(0, 6, tr(lh.SignatoryTitle+","), "", 1, "L", false, 0, "")
+	pdf.Ln(20)
+	pdf.SetX(signX)
+	pdf.SetFont(pdfFont, "BU", 10)
+	pdf.CellFormat(0, 6, tr(lh.SignatoryName), "", 1, "L", false, 0, "")
+
+	return pdf.Output(w)
+}
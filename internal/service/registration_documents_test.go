@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"darulabror/internal/models"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocumentToken(t *testing.T) {
+	key := []byte("secret")
+	now := time.Unix(1_700_000_000, 0)
+	token := signDocumentToken(key, documentToken{RegistrationID: 42, Kind: DocumentLetter, ExpiresAt: now.Add(time.Hour)})
+
+	got, err := verifyDocumentToken(key, token, now)
+	if err != nil {
+		t.Fatalf("verifyDocumentToken() error = %v", err)
+	}
+	if got.RegistrationID != 42 || got.Kind != DocumentLetter {
+		t.Errorf("verifyDocumentToken() = %+v", got)
+	}
+
+	tests := map[string]struct {
+		key   []byte
+		token string
+		now   time.Time
+	}{
+		"expired":   {key, token, now.Add(2 * time.Hour)},
+		"wrong key": {[]byte("other"), token, now},
+		"tampered":  {key, "x" + token, now},
+		"garbage":   {key, "not-a-token", now},
+	}
+	for name, tt := range tests {
+		if _, err := verifyDocumentToken(tt.key, tt.token, tt.now); !errors.Is(err, ErrInvalidDocumentToken) {
+			t.Errorf("%s: error = %v, want %v", name, err, ErrInvalidDocumentToken)
+		}
+	}
+}
+
+func TestNewTrackingCode(t *testing.T) {
+	re := regexp.MustCompile(`^DA-[` + trackingAlphabet + `]{4}-[` + trackingAlphabet + `]{4}$`)
+	for i := 0; i < 100; i++ {
+		code, err := newTrackingCode()
+		if err != nil {
+			t.Fatalf("newTrackingCode() error = %v", err)
+		}
+		if !re.MatchString(code) {
+			t.Fatalf("newTrackingCode() = %q", code)
+		}
+	}
+}
+
+func TestDefaultLetterTemplates(t *testing.T) {
+	for kind, tpl := range defaultLetterTemplates {
+		_, body, err := executeLetterTemplate(tpl, sampleLetterData)
+		if err != nil {
+			t.Fatalf("%s: executeLetterTemplate() error = %v", kind, err)
+		}
+		if !strings.Contains(body, sampleLetterData.FullName) || strings.Contains(body, "{{") {
+			t.Errorf("%s: body not rendered: %q", kind, body)
+		}
+	}
+
+	bad := models.LetterTemplate{Subject: "x", Body: "{{.Unknown}}"}
+	if _, _, err := executeLetterTemplate(bad, sampleLetterData); err == nil {
+		t.Error("executeLetterTemplate() with unknown field: want error")
+	}
+}
+
+func TestRenderDocuments(t *testing.T) {
+	lh := Letterhead{SchoolName: "Pondok Pesantren Darul Abror", Address: "Jl. Contoh 1", City: "Kediri",
+		SignatoryName: "Ust. Ahmad", SignatoryTitle: "Ketua Panitia"}
+	reg := models.Registration{
+		ID:           7,
+		TrackingCode: "DA-7K3M-Q9TX",
+		FullName:     "Siti Aminah",
+		NISN:         "0012345678",
+		Gender:       models.Female,
+		Program:      models.ProgramBoarding,
+		StudentType:  models.StudentNew,
+		Status:       models.RegistrationStatusDone,
+		DateOfBirth:  time.Date(2011, 3, 7, 0, 0, 0, 0, time.UTC),
+		CreatedAt:    time.Now(),
+	}
+	period := models.AdmissionPeriod{Name: "Gelombang 1", AcademicYear: "2025/2026"}
+
+	var card bytes.Buffer
+	if err := renderRegistrationCard(&card, lh, reg, period); err != nil {
+		t.Fatalf("renderRegistrationCard() error = %v", err)
+	}
+	if !bytes.HasPrefix(card.Bytes(), []byte("%PDF")) {
+		t.Error("registration card is not a PDF")
+	}
+
+	var letter bytes.Buffer
+	if err := renderLetter(&letter, lh, defaultLetterTemplates[models.LetterAcceptance], reg, period, time.Now()); err != nil {
+		t.Fatalf("renderLetter() error = %v", err)
+	}
+	if !bytes.HasPrefix(letter.Bytes(), []byte("%PDF")) {
+		t.Error("letter is not a PDF")
+	}
+}
+
+func TestFormatIndonesianDate(t *testing.T) {
+	got := formatIndonesianDate(time.Date(2025, 8, 17, 3, 0, 0, 0, time.UTC))
+	if got != "17 Agustus 2025" {
+		t.Errorf("formatIndonesianDate() = %q", got)
+	}
+}
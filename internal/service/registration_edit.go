@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultEditLinkTTL = time.Hour
+
+	// editTokenKind marks edit links; they are signed like download links but never render a document.
+	editTokenKind = "edit"
+)
+
+// RegistrationEditConfig is loaded by config.LoadRegistrationEditConfig.
+type RegistrationEditConfig struct {
+	SigningKey []byte        // HMAC key of edit links
+	LinkTTL    time.Duration // lifetime of edit links (default 1 hour)
+	URL        string        // page the emailed link opens; the token is appended as ?token=
+}
+
+// registrationField is one applicant-editable field, keyed by its JSON name.
+type registrationField struct {
+	Key   string
+	Value func(models.Registration) string
+}
+
+var registrationFields = []registrationField{
+	{"student_type", func(r models.Registration) string { return string(r.StudentType) }},
+	{"full_name", func(r models.Registration) string { return r.FullName }},
+	{"email", func(r models.Registration) string { return r.Email }},
+	{"phone", func(r models.Registration) string { return r.Phone }},
+	{"gender", func(r models.Registration) string { return string(r.Gender) }},
+	{"program", func(r models.Registration) string { return string(r.Program) }},
+	{"place_of_birth", func(r models.Registration) string { return r.PlaceOfBirth }},
+	{"date_of_birth", func(r models.Registration) string { return r.DateOfBirth.Format(dateLayoutISO) }},
+	{"address", func(r models.Registration) string { return r.Address }},
+	{"origin_school", func(r models.Registration) string { return r.OriginSchool }},
+	{"nisn", func(r models.Registration) string { return r.NISN }},
+	{"father_name", func(r models.Registration) string { return r.FatherName }},
+	{"father_occupation", func(r models.Registration) string { return r.FatherOccupation }},
+	{"phone_father", func(r models.Registration) string { return r.PhoneFather }},
+	{"date_of_birth_father", func(r models.Registration) string { return r.DateOfBirthFather.Format(dateLayoutISO) }},
+	{"mother_name", func(r models.Registration) string { return r.MotherName }},
+	{"mother_occupation", func(r models.Registration) string { return r.MotherOccupation }},
+	{"phone_mother", func(r models.Registration) string { return r.PhoneMother }},
+	{"date_of_birth_mother", func(r models.Registration) string { return r.DateOfBirthMother.Format(dateLayoutISO) }},
+}
+
+// diffRegistration lists the fields that differ between old and updated.
+func diffRegistration(old, updated models.Registration) []models.RegistrationChange {
+	var changes []models.RegistrationChange
+	for _, f := range registrationFields {
+		from, to := f.Value(old), f.Value(updated)
+		if from != to {
+			changes = append(changes, models.RegistrationChange{
+				RegistrationID: old.ID,
+				Field:          f.Key,
+				OldValue:       from,
+				NewValue:       to,
+			})
+		}
+	}
+	return changes
+}
+
+// maskEmail keeps the first letter of the local part: "siti@example.com" -> "s***@example.com".
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+func (s *registrationService) RequestEditLink(ctx context.Context, code, nisn string) (dto.EditLinkSentDTO, error) {
+	reg, err := lookupRegistration(s.repo, code, nisn)
+	if err != nil {
+		return dto.EditLinkSentDTO{}, err
+	}
+	if reg.Status != models.RegistrationStatusNew {
+		return dto.EditLinkSentDTO{}, ErrRegistrationNotEditable
+	}
+
+	expires := time.Now().Add(s.edit.LinkTTL)
+	token := signDocumentToken(s.edit.SigningKey, documentToken{RegistrationID: reg.ID, Kind: editTokenKind, ExpiresAt: expires})
+	link := s.edit.URL + "?token=" + url.QueryEscape(token)
+
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+	err = s.mailer.Send(ctx, repository.MailMessage{
+		To:      reg.Email,
+		Subject: "Tautan perubahan data pendaftaran",
+		Body: "Assalamu'alaikum,\n\n" +
+			"Kami menerima permintaan untuk mengubah data pendaftaran ananda " + reg.FullName +
+			" (kode pendaftaran " + reg.TrackingCode + ").\n\n" +
+			"Buka tautan berikut untuk mengubah data (berlaku sampai " + expires.In(exportLocation).Format(exportDateTimeLayout) + " WIB):\n" +
+			link + "\n\n" +
+			"Abaikan email ini jika Anda tidak memintanya.\n\n" +
+			"Wassalamu'alaikum.",
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("id", reg.ID).Error("failed send registration edit link")
+		return dto.EditLinkSentDTO{}, err
+	}
+
+	logrus.WithField("id", reg.ID).Info("registration edit link sent")
+	return dto.EditLinkSentDTO{Email: maskEmail(reg.Email), ExpiresAt: expires.Unix()}, nil
+}
+
+// editableRegistration resolves an edit link to its registration.
+func (s *registrationService) editableRegistration(token string) (models.Registration, error) {
+	t, err := verifyDocumentToken(s.edit.SigningKey, token, time.Now())
+	if err != nil || t.Kind != editTokenKind {
+		return models.Registration{}, ErrInvalidEditToken
+	}
+
+	reg, err := s.repo.GetByID(t.RegistrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Registration{}, ErrInvalidEditToken
+		}
+		logrus.WithError(err).WithField("id", t.RegistrationID).Error("failed get registration by id")
+		return models.Registration{}, err
+	}
+	if reg.Status != models.RegistrationStatusNew {
+		return models.Registration{}, ErrRegistrationNotEditable
+	}
+	return reg, nil
+}
+
+func (s *registrationService) GetForEdit(token string) (dto.RegistrationDTO, error) {
+	reg, err := s.editableRegistration(token)
+	if err != nil {
+		return dto.RegistrationDTO{}, err
+	}
+	return dto.RegistrationModelToDTO(reg), nil
+}
+
+func (s *registrationService) UpdateByApplicant(token string, in dto.RegistrationDTO) (dto.RegistrationDTO, error) {
+	current, err := s.editableRegistration(token)
+	if err != nil {
+		return dto.RegistrationDTO{}, err
+	}
+	return s.updateRegistration(current, in, models.ChangeSourceApplicant, nil, models.RegistrationStatusNew)
+}
+
+func (s *registrationService) UpdateRegistration(id uint, in dto.RegistrationDTO, adminID uint) (dto.RegistrationDTO, error) {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.RegistrationDTO{}, ErrNotFoundRegistration
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed get registration by id")
+		return dto.RegistrationDTO{}, err
+	}
+	return s.updateRegistration(current, in, models.ChangeSourceAdmin, &adminID, "")
+}
+
+// updateRegistration applies the editable fields of in to current, re-checking email/NISN uniqueness
+// within the period, and records one change per edited field. in.Version is the version the editor
+// started from (current's when zero).
+func (s *registrationService) updateRegistration(current models.Registration, in dto.RegistrationDTO, source string, changedBy *uint, requireStatus models.RegistrationStatus) (dto.RegistrationDTO, error) {
+	updated, err := dto.RegistrationDTOToModel(in)
+	if err != nil {
+		logrus.WithError(err).Error("failed convert RegistrationDTO to model")
+		return dto.RegistrationDTO{}, err
+	}
+	// never taken from the request
+	updated.ID = current.ID
+	updated.AdmissionPeriodID = current.AdmissionPeriodID
+	updated.TrackingCode = current.TrackingCode
+	updated.Status = current.Status
+	updated.CreatedAt = current.CreatedAt
+	if updated.Version == 0 {
+		updated.Version = current.Version
+	}
+	if updated.Version != current.Version {
+		return dto.RegistrationDTO{}, ErrRegistrationVersionConflict
+	}
+
+	changes := diffRegistration(current, updated)
+	if len(changes) == 0 {
+		return dto.RegistrationModelToDTO(current), nil
+	}
+
+	if current.AdmissionPeriodID != nil {
+		periodID := *current.AdmissionPeriodID
+		if updated.Email != current.Email {
+			exists, err := s.repo.ExistsByEmail(periodID, updated.Email)
+			if err != nil {
+				logrus.WithError(err).WithField("email", updated.Email).Error("failed check registration email")
+				return dto.RegistrationDTO{}, err
+			}
+			if exists {
+				return dto.RegistrationDTO{}, ErrRegistrationEmailExists
+			}
+		}
+		if updated.NISN != current.NISN {
+			exists, err := s.repo.ExistsByNISN(periodID, updated.NISN)
+			if err != nil {
+				logrus.WithError(err).WithField("nisn", updated.NISN).Error("failed check registration nisn")
+				return dto.RegistrationDTO{}, err
+			}
+			if exists {
+				return dto.RegistrationDTO{}, ErrRegistrationNISNExists
+			}
+		}
+	}
+
+	for i := range changes {
+		changes[i].Source = source
+		changes[i].ChangedBy = changedBy
+	}
+	// seats are only affected when the gender/program group changes
+	var promoted []models.Registration
+	if requireStatus != "" || updated.Gender != current.Gender || updated.Program != current.Program {
+		promoted, err = s.quotaRepo.UpdateRegistration(updated, changes, requireStatus)
+	} else {
+		err = s.repo.Update(updated, changes)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return dto.RegistrationDTO{}, ErrNotFoundRegistration
+		case errors.Is(err, repository.ErrVersionConflict):
+			return dto.RegistrationDTO{}, ErrRegistrationVersionConflict
+		case errors.Is(err, repository.ErrStatusChanged):
+			return dto.RegistrationDTO{}, ErrRegistrationNotEditable
+		case errors.Is(err, repository.ErrNoSeatAvailable):
+			return dto.RegistrationDTO{}, ErrQuotaFull
+		case errors.Is(err, repository.ErrDuplicateEmail):
+			return dto.RegistrationDTO{}, ErrRegistrationEmailExists
+		case errors.Is(err, repository.ErrDuplicateNISN):
+			return dto.RegistrationDTO{}, ErrRegistrationNISNExists
+		}
+		logrus.WithError(err).WithField("id", current.ID).Error("failed update registration")
+		return dto.RegistrationDTO{}, err
+	}
+
+	fields := make([]string, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	logrus.WithFields(logrus.Fields{
+		"id":     current.ID,
+		"source": source,
+		"fields": strings.Join(fields, ","),
+	}).Info("registration updated")
+	logPromoted(promoted)
+	updated.Version++
+	return dto.RegistrationModelToDTO(updated), nil
+}
+
+func (s *registrationService) GetChanges(id uint) ([]dto.RegistrationChangeDTO, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFoundRegistration
+		}
+		return nil, err
+	}
+
+	changes, err := s.repo.GetChanges(id)
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get registration changes")
+		return nil, err
+	}
+
+	out := make([]dto.RegistrationChangeDTO, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, dto.RegistrationChangeModelToDTO(c))
+	}
+	return out, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDiffRegistration(t *testing.T) {
+	old := models.Registration{
+		ID:          5,
+		FullName:    "Siti Aminah",
+		Phone:       "081234567890",
+		Gender:      models.Female,
+		DateOfBirth: time.Date(2010, 3, 7, 0, 0, 0, 0, time.UTC),
+	}
+	updated := old
+	updated.Phone = "081234567809"
+	updated.DateOfBirth = time.Date(2010, 7, 3, 0, 0, 0, 0, time.UTC)
+	updated.Status = models.RegistrationStatusDone // not an editable field
+
+	changes := diffRegistration(old, updated)
+	if len(changes) != 2 {
+		t.Fatalf("diffRegistration() returned %d changes, want 2: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.Field != "phone" || c.OldValue != "081234567890" || c.NewValue != "081234567809" || c.RegistrationID != 5 {
+		t.Errorf("changes[0] = %+v", c)
+	}
+	if c := changes[1]; c.Field != "date_of_birth" || c.OldValue != "2010-03-07" || c.NewValue != "2010-07-03" {
+		t.Errorf("changes[1] = %+v", c)
+	}
+
+	if got := diffRegistration(old, old); len(got) != 0 {
+		t.Errorf("diffRegistration(same) = %+v, want none", got)
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	for in, want := range map[string]string{
+		"siti@example.com": "s***@example.com",
+		"a@b.id":           "a***@b.id",
+		"invalid":          "***",
+	} {
+		if got := maskEmail(in); got != want {
+			t.Errorf("maskEmail(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// A download link must not open the edit form.
+func TestEditableRegistrationRejectsDocumentToken(t *testing.T) {
+	key := []byte("secret")
+	s := &registrationService{edit: RegistrationEditConfig{SigningKey: key}}
+	token := signDocumentToken(key, documentToken{RegistrationID: 1, Kind: DocumentCard, ExpiresAt: time.Now().Add(time.Hour)})
+
+	if _, err := s.editableRegistration(token); !errors.Is(err, ErrInvalidEditToken) {
+		t.Errorf("editableRegistration() error = %v, want %v", err, ErrInvalidEditToken)
+	}
+}
+
+// A stale version is refused before anything is written.
+func TestUpdateRegistrationStaleVersion(t *testing.T) {
+	current := models.Registration{ID: 5, FullName: "Siti Aminah", Version: 3}
+	in := dto.RegistrationModelToDTO(current)
+	in.FullName = "Siti Aminah Zahra"
+	in.Version = 2
+
+	s := &registrationService{}
+	if _, err := s.updateRegistration(current, in, models.ChangeSourceAdmin, nil, ""); !errors.Is(err, ErrRegistrationVersionConflict) {
+		t.Errorf("updateRegistration() error = %v, want %v", err, ErrRegistrationVersionConflict)
+	}
+}
+
+// The unique index catches an email or NISN taken after the existence check.
+func TestUpdateRegistrationUniqueViolation(t *testing.T) {
+	periodID := uint(1)
+	current := models.Registration{ID: 5, AdmissionPeriodID: &periodID, Email: "a@example.com", NISN: "0011111111", Program: models.ProgramBoarding, Version: 1}
+	in := dto.RegistrationModelToDTO(current)
+	in.Email = "b@example.com"
+
+	for repoErr, want := range map[error]error{
+		repository.ErrDuplicateEmail: ErrRegistrationEmailExists,
+		repository.ErrDuplicateNISN:  ErrRegistrationNISNExists,
+	} {
+		s := &registrationService{repo: &fakeRegistrationRepo{updateErr: repoErr}}
+		if _, err := s.updateRegistration(current, in, models.ChangeSourceAdmin, nil, ""); !errors.Is(err, want) {
+			t.Errorf("updateRegistration() with %v error = %v, want %v", repoErr, err, want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"darulabror/internal/models"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+
+	exportDateLayout     = "02/01/2006"
+	exportDateTimeLayout = "02/01/2006 15:04"
+	exportSheetName      = "Pendaftar"
+)
+
+// exportLocation is WIB; registrations are stored as timestamptz.
+var exportLocation = func() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}()
+
+// exportColumn maps a registration field to an Indonesian spreadsheet header.
+// Value returns a string, or a time.Time for date cells.
+type exportColumn struct {
+	Key    string
+	Header string
+	Value  func(r models.Registration) interface{}
+}
+
+var (
+	studentTypeLabels = map[models.StudentType]string{
+		models.StudentNew:      "Baru",
+		models.StudentTransfer: "Pindahan",
+	}
+	genderLabels = map[models.Gender]string{
+		models.Male:   "Laki-laki",
+		models.Female: "Perempuan",
+	}
+	programLabels = map[models.Program]string{
+		models.ProgramBoarding:    "Asrama",
+		models.ProgramNonBoarding: "Non-Asrama",
+	}
+	registrationStatusLabels = map[models.RegistrationStatus]string{
+		models.RegistrationStatusNew:        "Baru",
+		models.RegistrationStatusValidate:   "Verifikasi",
+		models.RegistrationStatusProcess:    "Proses",
+		models.RegistrationStatusDone:       "Selesai",
+		models.RegistrationStatusWaitlisted: "Daftar Tunggu",
+		models.RegistrationStatusWithdrawn:  "Mengundurkan Diri",
+		models.RegistrationStatusRejected:   "Tidak Diterima",
+	}
+)
+
+func labelOr[K ~string](labels map[K]string, v K) string {
+	if l, ok := labels[v]; ok {
+		return l
+	}
+	return string(v)
+}
+
+var registrationExportColumns = []exportColumn{
+	{"id", "No. Pendaftaran", func(r models.Registration) interface{} { return strconv.FormatUint(uint64(r.ID), 10) }},
+	{"created_at", "Tanggal Daftar", func(r models.Registration) interface{} { return r.CreatedAt.In(exportLocation) }},
+	{"status", "Status", func(r models.Registration) interface{} { return labelOr(registrationStatusLabels, r.Status) }},
+	{"student_type", "Jenis Pendaftar", func(r models.Registration) interface{} { return labelOr(studentTypeLabels, r.StudentType) }},
+	{"full_name", "Nama Lengkap", func(r models.Registration) interface{} { return r.FullName }},
+	{"gender", "Jenis Kelamin", func(r models.Registration) interface{} { return labelOr(genderLabels, r.Gender) }},
+	{"program", "Program", func(r models.Registration) interface{} { return labelOr(programLabels, r.Program) }},
+	{"nisn", "NISN", func(r models.Registration) interface{} { return r.NISN }},
+	{"email", "Email", func(r models.Registration) interface{} { return r.Email }},
+	{"phone", "No. HP", func(r models.Registration) interface{} { return r.Phone }},
+	{"place_of_birth", "Tempat Lahir", func(r models.Registration) interface{} { return r.PlaceOfBirth }},
+	{"date_of_birth", "Tanggal Lahir", func(r models.Registration) interface{} { return r.DateOfBirth }},
+	{"address", "Alamat", func(r models.Registration) interface{} { return r.Address }},
+	{"origin_school", "Asal Sekolah", func(r models.Registration) interface{} { return r.OriginSchool }},
+	{"father_name", "Nama Ayah", func(r models.Registration) interface{} { return r.FatherName }},
+	{"father_occupation", "Pekerjaan Ayah", func(r models.Registration) interface{} { return r.FatherOccupation }},
+	{"phone_father", "No. HP Ayah", func(r models.Registration) interface{} { return r.PhoneFather }},
+	{"date_of_birth_father", "Tanggal Lahir Ayah", func(r models.Registration) interface{} { return r.DateOfBirthFather }},
+	{"mother_name", "Nama Ibu", func(r models.Registration) interface{} { return r.MotherName }},
+	{"mother_occupation", "Pekerjaan Ibu", func(r models.Registration) interface{} { return r.MotherOccupation }},
+	{"phone_mother", "No. HP Ibu", func(r models.Registration) interface{} { return r.PhoneMother }},
+	{"date_of_birth_mother", "Tanggal Lahir Ibu", func(r models.Registration) interface{} { return r.DateOfBirthMother }},
+}
+
+// RegistrationExportColumnKeys lists the selectable columns (default: all, in this order).
+func RegistrationExportColumnKeys() []string {
+	keys := make([]string, 0, len(registrationExportColumns))
+	for _, c := range registrationExportColumns {
+		keys = append(keys, c.Key)
+	}
+	return keys
+}
+
+// selectExportColumns keeps the requested order; empty selection means all columns.
+func selectExportColumns(keys []string) ([]exportColumn, error) {
+	if len(keys) == 0 {
+		return registrationExportColumns, nil
+	}
+
+	byKey := make(map[string]exportColumn, len(registrationExportColumns))
+	for _, c := range registrationExportColumns {
+		byKey[c.Key] = c
+	}
+
+	cols := make([]exportColumn, 0, len(keys))
+	for _, k := range keys {
+		c, ok := byKey[k]
+		if !ok {
+			return nil, ErrInvalidExportColumn
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// rowWriter abstracts CSV/XLSX so rows can be streamed batch by batch.
+type rowWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+func newRowWriter(format string, w io.Writer, cols []exportColumn) (rowWriter, error) {
+	headers := make([]interface{}, 0, len(cols))
+	for _, c := range cols {
+		headers = append(headers, c.Header)
+	}
+
+	var (
+		rw  rowWriter
+		err error
+	)
+	switch format {
+	case ExportFormatCSV:
+		rw, err = newCSVRowWriter(w)
+	case ExportFormatXLSX:
+		rw, err = newXLSXRowWriter(w, len(cols))
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := rw.WriteRow(headers); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func newCSVRowWriter(w io.Writer) (*csvRowWriter, error) {
+	// UTF-8 BOM so Excel opens names with non-ASCII characters correctly
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvRowWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch t := v.(type) {
+		case time.Time:
+			if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+				record[i] = t.Format(exportDateLayout)
+			} else {
+				record[i] = t.Format(exportDateTimeLayout)
+			}
+		case string:
+			record[i] = escapeSpreadsheetFormula(t)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeSpreadsheetFormula prevents user input such as "=HYPERLINK(...)" from being
+// evaluated by spreadsheet apps. Phone numbers like "+62812..." are left alone.
+func escapeSpreadsheetFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + s
+	case '+', '-':
+		if strings.Trim(s[1:], "0123456789 ") != "" {
+			return "'" + s
+		}
+	}
+	return s
+}
+
+type xlsxRowWriter struct {
+	w         io.Writer
+	f         *excelize.File
+	sw        *excelize.StreamWriter
+	row       int
+	dateStyle int
+	timeStyle int
+	headStyle int
+}
+
+func newXLSXRowWriter(w io.Writer, numCols int) (*xlsxRowWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", exportSheetName); err != nil {
+		return nil, err
+	}
+
+	dateFmt, timeFmt := "dd/mm/yyyy", "dd/mm/yyyy hh:mm"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt})
+	if err != nil {
+		return nil, err
+	}
+	timeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &timeFmt})
+	if err != nil {
+		return nil, err
+	}
+	headStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	sw, err := f.NewStreamWriter(exportSheetName)
+	if err != nil {
+		return nil, err
+	}
+	if err := sw.SetColWidth(1, numCols, 20); err != nil {
+		return nil, err
+	}
+
+	return &xlsxRowWriter{
+		w:         w,
+		f:         f,
+		sw:        sw,
+		dateStyle: dateStyle,
+		timeStyle: timeStyle,
+		headStyle: headStyle,
+	}, nil
+}
+
+func (x *xlsxRowWriter) WriteRow(values []interface{}) error {
+	x.row++
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		switch t := v.(type) {
+		case time.Time:
+			// Excel has no time zones: keep the wall clock of t
+			wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+			style := x.dateStyle
+			if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 {
+				style = x.timeStyle
+			}
+			cells[i] = excelize.Cell{StyleID: style, Value: wall}
+		default:
+			if x.row == 1 {
+				cells[i] = excelize.Cell{StyleID: x.headStyle, Value: v}
+			} else {
+				cells[i] = v
+			}
+		}
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, cells)
+}
+
+func (x *xlsxRowWriter) Close() error {
+	defer x.f.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.f.Write(x.w)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeExportJobRepo struct {
+	repository.ExportJobRepo
+	jobs map[uint]models.ExportJob
+}
+
+func (f *fakeExportJobRepo) GetByID(id uint) (models.ExportJob, error) {
+	return f.jobs[id], nil
+}
+
+func (f *fakeExportJobRepo) Update(job models.ExportJob) error {
+	f.jobs[job.ID] = job
+	return nil
+}
+
+func TestGetExportJobStale(t *testing.T) {
+	old := time.Now().Add(-exportJobTimeout - time.Minute).Unix()
+	repo := &fakeExportJobRepo{jobs: map[uint]models.ExportJob{
+		1: {ID: 1, Status: models.ExportJobRunning, CreatedAt: old},
+		2: {ID: 2, Status: models.ExportJobRunning, CreatedAt: time.Now().Unix()},
+	}}
+	svc := NewExportService(nil, repo, nil)
+
+	got, err := svc.GetExportJob(context.Background(), 1)
+	if err != nil || got.Status != models.ExportJobFailed || repo.jobs[1].Status != models.ExportJobFailed {
+		t.Errorf("stale job = %+v, %v; want failed", got, err)
+	}
+	if got, _ := svc.GetExportJob(context.Background(), 2); got.Status != models.ExportJobRunning {
+		t.Errorf("fresh job status = %s, want running", got.Status)
+	}
+}
+
+func TestJobRunnerClose(t *testing.T) {
+	r := newJobRunner()
+	finished := make(chan struct{})
+	r.Go(func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		close(finished)
+	})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Close() returned before the job finished")
+	}
+
+	r = newJobRunner()
+	cancelled := make(chan struct{})
+	r.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Close(ctx); err == nil {
+		t.Error("Close() with an expired context should return its error")
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Error("Close() did not cancel the running job")
+	}
+}
+
+func TestEscapeSpreadsheetFormula(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"Ahmad", "Ahmad"},
+		{"+6281234567890", "+6281234567890"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"-1+2", "'-1+2"},
+	}
+	for _, tt := range tests {
+		if got := escapeSpreadsheetFormula(tt.in); got != tt.want {
+			t.Errorf("escapeSpreadsheetFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSelectExportColumns(t *testing.T) {
+	cols, err := selectExportColumns([]string{"nisn", "full_name"})
+	if err != nil {
+		t.Fatalf("selectExportColumns() error = %v", err)
+	}
+	if len(cols) != 2 || cols[0].Key != "nisn" || cols[1].Key != "full_name" {
+		t.Errorf("selectExportColumns() kept wrong order: %+v", cols)
+	}
+
+	if _, err := selectExportColumns([]string{"password"}); err != ErrInvalidExportColumn {
+		t.Errorf("selectExportColumns(unknown) error = %v, want %v", err, ErrInvalidExportColumn)
+	}
+}
+
+func TestCSVRowWriter(t *testing.T) {
+	cols, _ := selectExportColumns([]string{"full_name", "gender", "date_of_birth"})
+
+	var buf bytes.Buffer
+	rw, err := newRowWriter(ExportFormatCSV, &buf, cols)
+	if err != nil {
+		t.Fatalf("newRowWriter() error = %v", err)
+	}
+	reg := models.Registration{
+		FullName:    "Siti",
+		Gender:      models.Female,
+		DateOfBirth: time.Date(2010, 3, 7, 0, 0, 0, 0, time.UTC),
+	}
+	values := make([]interface{}, len(cols))
+	for i, c := range cols {
+		values[i] = c.Value(reg)
+	}
+	if err := rw.WriteRow(values); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\ufeff") {
+		t.Error("csv output should start with a UTF-8 BOM")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	want := [][]string{
+		{"Nama Lengkap", "Jenis Kelamin", "Tanggal Lahir"},
+		{"Siti", "Perempuan", "07/03/2010"},
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// importField copies one spreadsheet cell into the DTO. Headers are matched against
+// both the export headers ("Nama Lengkap") and the column keys ("full_name"), so an
+// exported file can be edited and imported again. Optional columns may be missing.
+type importField struct {
+	Key      string
+	Set      func(d *dto.RegistrationDTO, v string)
+	Optional bool
+}
+
+var registrationImportFields = []importField{
+	{"student_type", func(d *dto.RegistrationDTO, v string) {
+		d.StudentType = models.StudentType(unlabel(studentTypeLabels, v))
+	}, false},
+	{"full_name", func(d *dto.RegistrationDTO, v string) { d.FullName = v }, false},
+	{"gender", func(d *dto.RegistrationDTO, v string) { d.Gender = models.Gender(unlabel(genderLabels, v)) }, false},
+	{"program", func(d *dto.RegistrationDTO, v string) { d.Program = models.Program(unlabel(programLabels, v)) }, true},
+	{"nisn", func(d *dto.RegistrationDTO, v string) { d.NISN = v }, false},
+	{"email", func(d *dto.RegistrationDTO, v string) { d.Email = v }, false},
+	{"phone", func(d *dto.RegistrationDTO, v string) { d.Phone = v }, false},
+	{"place_of_birth", func(d *dto.RegistrationDTO, v string) { d.PlaceOfBirth = v }, false},
+	{"date_of_birth", func(d *dto.RegistrationDTO, v string) { d.DateOfBirth = normalizeImportDate(v) }, false},
+	{"address", func(d *dto.RegistrationDTO, v string) { d.Address = v }, false},
+	{"origin_school", func(d *dto.RegistrationDTO, v string) { d.OriginSchool = v }, false},
+	{"father_name", func(d *dto.RegistrationDTO, v string) { d.FatherName = v }, false},
+	{"father_occupation", func(d *dto.RegistrationDTO, v string) { d.FatherOccupation = v }, false},
+	{"phone_father", func(d *dto.RegistrationDTO, v string) { d.PhoneFather = v }, false},
+	{"date_of_birth_father", func(d *dto.RegistrationDTO, v string) { d.DateOfBirthFather = normalizeImportDate(v) }, false},
+	{"mother_name", func(d *dto.RegistrationDTO, v string) { d.MotherName = v }, false},
+	{"mother_occupation", func(d *dto.RegistrationDTO, v string) { d.MotherOccupation = v }, false},
+	{"phone_mother", func(d *dto.RegistrationDTO, v string) { d.PhoneMother = v }, false},
+	{"date_of_birth_mother", func(d *dto.RegistrationDTO, v string) { d.DateOfBirthMother = normalizeImportDate(v) }, false},
+}
+
+// unlabel maps an Indonesian label ("Perempuan") back to its value ("female").
+// Unknown input is returned as is and rejected by the validator.
+func unlabel[K ~string](labels map[K]string, v string) string {
+	for k, l := range labels {
+		if strings.EqualFold(v, l) || strings.EqualFold(v, string(k)) {
+			return string(k)
+		}
+	}
+	return v
+}
+
+var importDateLayouts = []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006"}
+
+// normalizeImportDate converts dd/mm/yyyy and Excel serial dates to the DTO layout (yyyy-mm-dd).
+func normalizeImportDate(v string) string {
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.Format(dateLayoutISO)
+		}
+	}
+	if serial, err := strconv.ParseFloat(v, 64); err == nil && serial > 0 {
+		if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			return t.Format(dateLayoutISO)
+		}
+	}
+	return v
+}
+
+const dateLayoutISO = "2006-01-02"
+
+func normalizeImportHeader(h string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+}
+
+// importColumnIndex maps field key -> column index from the header row.
+func importColumnIndex(header []string) (map[string]int, error) {
+	byHeader := make(map[string]string, len(registrationExportColumns)*2)
+	for _, c := range registrationExportColumns {
+		byHeader[normalizeImportHeader(c.Header)] = c.Key
+		byHeader[c.Key] = c.Key
+	}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		if key, ok := byHeader[normalizeImportHeader(h)]; ok {
+			if _, dup := index[key]; !dup {
+				index[key] = i
+			}
+		}
+	}
+
+	for _, f := range registrationImportFields {
+		if _, ok := index[f.Key]; !ok && !f.Optional {
+			return nil, fmt.Errorf("%w: %s", ErrImportMissingColumn, f.Key)
+		}
+	}
+	return index, nil
+}
+
+// importRow is one parsed data row; Line is the spreadsheet row number.
+type importRow struct {
+	Line int
+	Reg  dto.RegistrationDTO
+}
+
+// readImportRows parses a CSV/XLSX file. Blank rows are skipped; more than maxRows data rows is an error.
+func readImportRows(format string, r io.Reader, maxRows int) ([]importRow, error) {
+	var (
+		records [][]string
+		err     error
+	)
+	switch format {
+	case ExportFormatCSV:
+		records, err = readCSVRecords(r, maxRows+1)
+	case ExportFormatXLSX:
+		records, err = readXLSXRecords(r, maxRows+1)
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrImportEmptyFile
+	}
+
+	index, err := importColumnIndex(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		if isBlankRecord(rec) {
+			continue
+		}
+		var reg dto.RegistrationDTO
+		for _, f := range registrationImportFields {
+			if i, ok := index[f.Key]; ok {
+				f.Set(&reg, cleanImportCell(rec, i))
+			}
+		}
+		rows = append(rows, importRow{Line: i + 2, Reg: reg})
+	}
+	if len(rows) == 0 {
+		return nil, ErrImportEmptyFile
+	}
+	return rows, nil
+}
+
+// cleanImportCell trims the value and drops the quote added by escapeSpreadsheetFormula.
+func cleanImportCell(rec []string, i int) string {
+	if i >= len(rec) {
+		return ""
+	}
+	v := strings.TrimSpace(rec[i])
+	if len(v) > 1 && v[0] == '\'' {
+		v = v[1:]
+	}
+	return v
+}
+
+func isBlankRecord(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSVRecords accepts comma or semicolon separated files (Excel with Indonesian locale uses ';').
+func readCSVRecords(r io.Reader, maxRecords int) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+
+	cr := csv.NewReader(strings.NewReader(text))
+	cr.FieldsPerRecord = -1
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		cr.Comma = ';'
+	}
+
+	var records [][]string
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+		if len(records) > maxRecords {
+			return nil, ErrImportTooManyRows
+		}
+	}
+	return records, nil
+}
+
+// readXLSXRecords reads the first sheet.
+func readXLSXRecords(r io.Reader, maxRecords int) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrImportEmptyFile
+	}
+	rows, err := f.Rows(sheets[0])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records [][]string
+	for rows.Next() {
+		rec, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+		if len(records) > maxRecords {
+			return nil, ErrImportTooManyRows
+		}
+	}
+	return records, rows.Error()
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"darulabror/internal/models"
+	"darulabror/internal/utils"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestReadImportRowsCSV(t *testing.T) {
+	csvData := "\ufeffJenis Pendaftar;Nama Lengkap;Jenis Kelamin;NISN;Email;No. HP;Tempat Lahir;Tanggal Lahir;Alamat;Asal Sekolah;" +
+		"Nama Ayah;Pekerjaan Ayah;No. HP Ayah;Tanggal Lahir Ayah;Nama Ibu;Pekerjaan Ibu;No. HP Ibu;Tanggal Lahir Ibu\n" +
+		"Pindahan;Siti Aminah;Perempuan;'0012345678;siti@example.com;081234567890;Kediri;07/03/2010;Jl. Mawar 1;SDN 1;" +
+		"Ahmad;Petani;081234567891;1980-01-02;Aisyah;Guru;081234567892;2/1/1982\n" +
+		";;;;;;;;;;;;;;;;;\n"
+
+	rows, err := readImportRows(ExportFormatCSV, strings.NewReader(csvData), 10)
+	if err != nil {
+		t.Fatalf("readImportRows() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("readImportRows() returned %d rows, want 1", len(rows))
+	}
+
+	got := rows[0]
+	if got.Line != 2 {
+		t.Errorf("Line = %d, want 2", got.Line)
+	}
+	if got.Reg.StudentType != models.StudentTransfer || got.Reg.Gender != models.Female {
+		t.Errorf("labels not mapped: student_type=%q gender=%q", got.Reg.StudentType, got.Reg.Gender)
+	}
+	if got.Reg.NISN != "0012345678" {
+		t.Errorf("NISN = %q, want formula quote removed", got.Reg.NISN)
+	}
+	if got.Reg.DateOfBirth != "2010-03-07" || got.Reg.DateOfBirthMother != "1982-01-02" {
+		t.Errorf("dates not normalized: %q, %q", got.Reg.DateOfBirth, got.Reg.DateOfBirthMother)
+	}
+	if got.Reg.Program != "" {
+		t.Errorf("Program = %q, want empty when the optional column is missing", got.Reg.Program)
+	}
+}
+
+func TestReadImportRowsMissingColumn(t *testing.T) {
+	_, err := readImportRows(ExportFormatCSV, strings.NewReader("full_name,email\nSiti,siti@example.com\n"), 10)
+	if !errors.Is(err, ErrImportMissingColumn) {
+		t.Errorf("readImportRows() error = %v, want %v", err, ErrImportMissingColumn)
+	}
+}
+
+func TestReadImportRowsTooMany(t *testing.T) {
+	header := strings.Join(RegistrationExportColumnKeys(), ",")
+	data := header + "\n" + strings.Repeat("x\n", 3)
+	if _, err := readImportRows(ExportFormatCSV, strings.NewReader(data), 2); !errors.Is(err, ErrImportTooManyRows) {
+		t.Errorf("readImportRows() error = %v, want %v", err, ErrImportTooManyRows)
+	}
+}
+
+// An exported XLSX can be imported again unchanged.
+func TestImportExportedXLSX(t *testing.T) {
+	reg := models.Registration{
+		ID:                7,
+		StudentType:       models.StudentNew,
+		Gender:            models.Male,
+		Program:           models.ProgramNonBoarding,
+		Status:            models.RegistrationStatusNew,
+		FullName:          "Ahmad Fauzi",
+		Email:             "ahmad@example.com",
+		NISN:              "0012345678",
+		Phone:             "081234567890",
+		DateOfBirth:       time.Date(2011, 12, 31, 0, 0, 0, 0, time.UTC),
+		DateOfBirthFather: time.Date(1980, 1, 2, 0, 0, 0, 0, time.UTC),
+		DateOfBirthMother: time.Date(1982, 2, 3, 0, 0, 0, 0, time.UTC),
+		CreatedAt:         time.Now(),
+	}
+
+	var buf bytes.Buffer
+	rw, err := newRowWriter(ExportFormatXLSX, &buf, registrationExportColumns)
+	if err != nil {
+		t.Fatalf("newRowWriter() error = %v", err)
+	}
+	values := make([]interface{}, len(registrationExportColumns))
+	for i, c := range registrationExportColumns {
+		values[i] = c.Value(reg)
+	}
+	if err := rw.WriteRow(values); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	rows, err := readImportRows(ExportFormatXLSX, &buf, 10)
+	if err != nil {
+		t.Fatalf("readImportRows() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("readImportRows() returned %d rows, want 1", len(rows))
+	}
+	got := rows[0].Reg
+	if got.FullName != reg.FullName || got.NISN != reg.NISN || got.Gender != reg.Gender || got.StudentType != reg.StudentType || got.Program != reg.Program {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+	if got.DateOfBirth != "2011-12-31" || got.DateOfBirthFather != "1980-01-02" {
+		t.Errorf("dates = %q, %q", got.DateOfBirth, got.DateOfBirthFather)
+	}
+}
+
+func TestCheckImportRowTranslated(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	})
+	if err := utils.RegisterValidationTranslations(v); err != nil {
+		t.Fatal(err)
+	}
+	s := &importService{regRepo: &fakeRegistrationRepo{}, validate: v}
+
+	csvData := "Jenis Pendaftar;Nama Lengkap;Jenis Kelamin;NISN;Email;No. HP;Tempat Lahir;Tanggal Lahir;Alamat;Asal Sekolah;" +
+		"Nama Ayah;Pekerjaan Ayah;No. HP Ayah;Tanggal Lahir Ayah;Nama Ibu;Pekerjaan Ibu;No. HP Ibu;Tanggal Lahir Ibu\n" +
+		"Baru;Siti Aminah;Perempuan;123;siti@example.com;081234567890;Kediri;07/03/2010;Jl. Mawar 1;SDN 1;" +
+		"Ahmad;Petani;081234567891;1980-01-02;Aisyah;Guru;081234567892;2/1/1982\n"
+	rows, err := readImportRows(ExportFormatCSV, strings.NewReader(csvData), 10)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("readImportRows() = %d rows, %v", len(rows), err)
+	}
+
+	for lang, want := range map[string]string{
+		"en": "nisn: nisn must be 10 characters in length",
+		"id": "nisn: panjang nisn harus 10 karakter",
+	} {
+		got, err := s.checkImportRow(1, rows[0], lang, map[string]int{}, map[string]int{})
+		if err != nil {
+			t.Fatalf("checkImportRow() error = %v", err)
+		}
+		if len(got) != 1 || got[0] != want {
+			t.Errorf("checkImportRow(%s) = %q, want [%q]", lang, got, want)
+		}
+	}
+}
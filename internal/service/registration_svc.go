@@ -1,10 +1,13 @@
 package service
 
 import (
+	"context"
 	"darulabror/internal/dto"
 	"darulabror/internal/models"
 	"darulabror/internal/repository"
+	"darulabror/internal/utils"
 	"errors"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -12,83 +15,148 @@ import (
 
 type RegistrationService interface {
 	// Public
-	CreateRegistration(regDTO dto.RegistrationDTO) error
+	CreateRegistration(regDTO dto.RegistrationDTO) (dto.RegistrationReceiptDTO, error)
+	// RequestEditLink emails an edit link to the registration's address (status must be new).
+	RequestEditLink(ctx context.Context, code, nisn string) (dto.EditLinkSentDTO, error)
+	GetForEdit(token string) (dto.RegistrationDTO, error)
+	// UpdateByApplicant re-checks email/NISN uniqueness and records each changed field.
+	UpdateByApplicant(token string, in dto.RegistrationDTO) (dto.RegistrationDTO, error)
 
 	// Admin
-	GetAllRegistrations(page, limit int, status string) ([]dto.RegistrationDTO, int64, error)
+	GetAllRegistrations(q utils.ListQuery) ([]dto.RegistrationDTO, utils.PageInfo, error)
 	GetRegistrationByID(id uint) (dto.RegistrationDTO, error)
-	UpdateRegistrationStatus(id uint, status models.RegistrationStatus) error
+	// UpdateRegistration replaces the editable fields; in.Version must match (ErrRegistrationVersionConflict).
+	UpdateRegistration(id uint, in dto.RegistrationDTO, adminID uint) (dto.RegistrationDTO, error)
+	// UpdateRegistrationStatus records the change; adminID is the acting admin.
+	UpdateRegistrationStatus(id uint, status models.RegistrationStatus, adminID uint) error
+	GetStatusHistory(id uint) ([]dto.RegistrationStatusHistoryDTO, error)
+	GetChanges(id uint) ([]dto.RegistrationChangeDTO, error)
 	DeleteRegistration(id uint) error
 }
 
 type registrationService struct {
-	repo repository.RegistrationRepo
+	repo       repository.RegistrationRepo
+	periodRepo repository.AdmissionPeriodRepo
+	quotaRepo  repository.AdmissionQuotaRepo
+	docs       DocumentService
+	edit       RegistrationEditConfig
+	mailer     repository.Mailer
 }
 
-func NewRegistrationService(repo repository.RegistrationRepo) RegistrationService {
-	return &registrationService{repo: repo}
+func NewRegistrationService(
+	repo repository.RegistrationRepo,
+	periodRepo repository.AdmissionPeriodRepo,
+	quotaRepo repository.AdmissionQuotaRepo,
+	docs DocumentService,
+	edit RegistrationEditConfig,
+	mailer repository.Mailer,
+) RegistrationService {
+	if edit.LinkTTL <= 0 {
+		edit.LinkTTL = defaultEditLinkTTL
+	}
+	return &registrationService{
+		repo:       repo,
+		periodRepo: periodRepo,
+		quotaRepo:  quotaRepo,
+		docs:       docs,
+		edit:       edit,
+		mailer:     mailer,
+	}
 }
 
-func (s *registrationService) CreateRegistration(regDTO dto.RegistrationDTO) error {
-	// uniqueness checks
-	existsEmail, err := s.repo.ExistsByEmail(regDTO.Email)
+func (s *registrationService) CreateRegistration(regDTO dto.RegistrationDTO) (dto.RegistrationReceiptDTO, error) {
+	// only accepted while an admission period is open
+	period, err := s.periodRepo.GetOpen(time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.RegistrationReceiptDTO{}, ErrRegistrationClosed
+		}
+		logrus.WithError(err).Error("failed get open admission period")
+		return dto.RegistrationReceiptDTO{}, err
+	}
+
+	// uniqueness checks (per period)
+	existsEmail, err := s.repo.ExistsByEmail(period.ID, regDTO.Email)
 	if err != nil {
 		logrus.WithError(err).WithField("email", regDTO.Email).Error("failed check registration email")
-		return err
+		return dto.RegistrationReceiptDTO{}, err
 	}
 	if existsEmail {
-		return ErrRegistrationEmailExists
+		return dto.RegistrationReceiptDTO{}, ErrRegistrationEmailExists
 	}
 
-	existsNISN, err := s.repo.ExistsByNISN(regDTO.NISN)
+	existsNISN, err := s.repo.ExistsByNISN(period.ID, regDTO.NISN)
 	if err != nil {
 		logrus.WithError(err).WithField("nisn", regDTO.NISN).Error("failed check registration nisn")
-		return err
+		return dto.RegistrationReceiptDTO{}, err
 	}
 	if existsNISN {
-		return ErrRegistrationNISNExists
+		return dto.RegistrationReceiptDTO{}, ErrRegistrationNISNExists
 	}
 
 	reg, err := dto.RegistrationDTOToModel(regDTO)
 	if err != nil {
 		logrus.WithError(err).Error("failed convert RegistrationDTO to model")
-		return err
+		return dto.RegistrationReceiptDTO{}, err
+	}
+	reg.AdmissionPeriodID = &period.ID
+	if reg.TrackingCode, err = newTrackingCode(); err != nil {
+		logrus.WithError(err).Error("failed generate tracking code")
+		return dto.RegistrationReceiptDTO{}, ErrCreateRegistration
 	}
 
-	if err := s.repo.Create(reg); err != nil {
+	// the quota repo decides between "new" and "waitlisted" under the period lock
+	regs := []models.Registration{reg}
+	if err := s.quotaRepo.CreateRegistrations(regs); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateEmail):
+			return dto.RegistrationReceiptDTO{}, ErrRegistrationEmailExists
+		case errors.Is(err, repository.ErrDuplicateNISN):
+			return dto.RegistrationReceiptDTO{}, ErrRegistrationNISNExists
+		}
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"email": reg.Email,
 			"nisn":  reg.NISN,
 		}).Error("failed create registration")
-		return ErrCreateRegistration
+		return dto.RegistrationReceiptDTO{}, ErrCreateRegistration
 	}
+	reg = regs[0]
 
 	logrus.WithFields(logrus.Fields{
-		"email": reg.Email,
-		"nisn":  reg.NISN,
+		"id":        reg.ID,
+		"email":     reg.Email,
+		"nisn":      reg.NISN,
+		"status":    reg.Status,
+		"period_id": period.ID,
 	}).Info("registration created")
-	return nil
+	return dto.RegistrationReceiptDTO{
+		ID:                reg.ID,
+		Status:            reg.Status,
+		AdmissionPeriodID: reg.AdmissionPeriodID,
+		TrackingCode:      reg.TrackingCode,
+		CardURL:           s.docs.Links(reg).CardURL,
+	}, nil
 }
 
-func (s *registrationService) GetAllRegistrations(page, limit int, status string) ([]dto.RegistrationDTO, int64, error) {
-	regs, total, err := s.repo.GetAll(page, limit, status)
+func (s *registrationService) GetAllRegistrations(q utils.ListQuery) ([]dto.RegistrationDTO, utils.PageInfo, error) {
+	regs, page, err := s.repo.GetAll(q)
 	if err != nil {
 		logrus.WithError(err).Error("failed get all registrations")
-		return nil, 0, err
+		return nil, utils.PageInfo{}, err
 	}
 
 	out := make([]dto.RegistrationDTO, 0, len(regs))
 	for _, r := range regs {
 		out = append(out, dto.RegistrationModelToDTO(r))
 	}
-	return out, total, nil
+	return out, page, nil
 }
 
 func (s *registrationService) GetRegistrationByID(id uint) (dto.RegistrationDTO, error) {
 	reg, err := s.repo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.RegistrationDTO{}, errors.New("registration not found")
+			return dto.RegistrationDTO{}, ErrNotFoundRegistration
 		}
 		logrus.WithError(err).WithField("id", id).Error("failed get registration by id")
 		return dto.RegistrationDTO{}, err
@@ -96,27 +164,61 @@ func (s *registrationService) GetRegistrationByID(id uint) (dto.RegistrationDTO,
 	return dto.RegistrationModelToDTO(reg), nil
 }
 
+func (s *registrationService) GetStatusHistory(id uint) ([]dto.RegistrationStatusHistoryDTO, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFoundRegistration
+		}
+		return nil, err
+	}
+
+	history, err := s.repo.GetStatusHistory(id)
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get registration status history")
+		return nil, err
+	}
+
+	out := make([]dto.RegistrationStatusHistoryDTO, 0, len(history))
+	for _, h := range history {
+		out = append(out, dto.RegistrationStatusHistoryModelToDTO(h))
+	}
+	return out, nil
+}
+
 func (s *registrationService) DeleteRegistration(id uint) error {
-	if err := s.repo.Delete(id); err != nil {
+	promoted, err := s.quotaRepo.DeleteRegistration(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundRegistration
+		}
 		logrus.WithError(err).WithField("id", id).Error("failed delete registration")
 		return err
 	}
 	logrus.WithField("id", id).Info("registration deleted")
+	logPromoted(promoted)
 	return nil
 }
 
-func (s *registrationService) UpdateRegistrationStatus(id uint, status models.RegistrationStatus) error {
-	// Validate status value
-	if status != models.RegistrationStatusNew && 
-	   status != models.RegistrationStatusValidate && 
-	   status != models.RegistrationStatusProcess && 
-	   status != models.RegistrationStatusDone {
-		return errors.New("invalid status value")
+func (s *registrationService) UpdateRegistrationStatus(id uint, status models.RegistrationStatus, adminID uint) error {
+	switch status {
+	case models.RegistrationStatusNew,
+		models.RegistrationStatusValidate,
+		models.RegistrationStatusProcess,
+		models.RegistrationStatusDone,
+		models.RegistrationStatusWaitlisted,
+		models.RegistrationStatusWithdrawn,
+		models.RegistrationStatusRejected:
+	default:
+		return ErrInvalidRegistrationStatus
 	}
-	
-	if err := s.repo.UpdateStatus(id, status); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("registration not found")
+
+	_, promoted, err := s.quotaRepo.ChangeStatus(id, status, &adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return ErrNotFoundRegistration
+		case errors.Is(err, repository.ErrNoSeatAvailable):
+			return ErrQuotaFull
 		}
 		logrus.WithError(err).WithField("id", id).Error("failed update registration status")
 		return err
@@ -125,5 +227,17 @@ func (s *registrationService) UpdateRegistrationStatus(id uint, status models.Re
 		"id":     id,
 		"status": status,
 	}).Info("registration status updated")
+	logPromoted(promoted)
 	return nil
 }
+
+func logPromoted(promoted []models.Registration) {
+	for _, p := range promoted {
+		logrus.WithFields(logrus.Fields{
+			"id":        p.ID,
+			"period_id": p.AdmissionPeriodID,
+			"gender":    p.Gender,
+			"program":   p.Program,
+		}).Info("waitlisted registration promoted")
+	}
+}
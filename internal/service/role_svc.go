@@ -0,0 +1,212 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RoleService manages the admin roles. superadmin always has every permission and cannot be
+// changed; admin and superadmin cannot be deleted.
+type RoleService interface {
+	ListRoles() ([]dto.RoleDTO, error)
+	GetRole(name models.Role) (dto.RoleDTO, error)
+	// CreateRole and UpdateRole only grant permissions the requester holds itself.
+	CreateRole(held []models.Permission, in dto.RoleInputDTO) (dto.RoleDTO, error)
+	UpdateRole(held []models.Permission, name models.Role, in dto.RoleInputDTO) (dto.RoleDTO, error)
+	DeleteRole(held []models.Permission, name models.Role) error
+}
+
+type roleService struct {
+	repo repository.RoleRepo
+}
+
+func NewRoleService(repo repository.RoleRepo) RoleService {
+	return &roleService{repo: repo}
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+// roleToDTO reports superadmin with the whole catalogue, whatever rows it has.
+func roleToDTO(role models.AdminRole) dto.RoleDTO {
+	out := dto.RoleModelToDTO(role)
+	if role.Name == models.Superadmin {
+		out.Permissions = models.Permissions
+	}
+	return out
+}
+
+// rolePermissions resolves the permissions of a role, as embedded in the admin's JWT.
+func rolePermissions(repo repository.RoleRepo, name models.Role) ([]models.Permission, error) {
+	if name == models.Superadmin {
+		return models.Permissions, nil
+	}
+	role, err := repo.Get(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFoundRole
+		}
+		return nil, err
+	}
+	return roleToDTO(role).Permissions, nil
+}
+
+// holdsAll reports whether held includes every permission of want.
+func holdsAll(held, want []models.Permission) bool {
+	set := make(map[models.Permission]bool, len(held))
+	for _, p := range held {
+		set[p] = true
+	}
+	for _, p := range want {
+		if !set[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizePermissions checks the permissions against the catalogue and drops duplicates.
+func normalizePermissions(perms []models.Permission) ([]models.Permission, error) {
+	seen := map[models.Permission]bool{}
+	out := make([]models.Permission, 0, len(perms))
+	for _, p := range perms {
+		p = models.Permission(strings.TrimSpace(string(p)))
+		if !p.Valid() {
+			return nil, ErrInvalidPermission
+		}
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out, nil
+}
+
+func buildRole(name models.Role, description string, perms []models.Permission) models.AdminRole {
+	role := models.AdminRole{Name: name, Description: strings.TrimSpace(description)}
+	for _, p := range perms {
+		role.Permissions = append(role.Permissions, models.RolePermission{Role: name, Permission: p})
+	}
+	return role
+}
+
+func (s *roleService) ListRoles() ([]dto.RoleDTO, error) {
+	roles, err := s.repo.List()
+	if err != nil {
+		logrus.WithError(err).Error("failed list roles")
+		return nil, err
+	}
+	out := make([]dto.RoleDTO, len(roles))
+	for i, r := range roles {
+		out[i] = roleToDTO(r)
+	}
+	return out, nil
+}
+
+func (s *roleService) GetRole(name models.Role) (dto.RoleDTO, error) {
+	role, err := s.repo.Get(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.RoleDTO{}, ErrNotFoundRole
+		}
+		logrus.WithError(err).WithField("role", name).Error("failed get role")
+		return dto.RoleDTO{}, err
+	}
+	return roleToDTO(role), nil
+}
+
+func (s *roleService) CreateRole(held []models.Permission, in dto.RoleInputDTO) (dto.RoleDTO, error) {
+	name := models.Role(strings.ToLower(strings.TrimSpace(string(in.Name))))
+	if !roleNamePattern.MatchString(string(name)) {
+		return dto.RoleDTO{}, ErrInvalidRoleName
+	}
+	perms, err := normalizePermissions(in.Permissions)
+	if err != nil {
+		return dto.RoleDTO{}, err
+	}
+	if !holdsAll(held, perms) {
+		return dto.RoleDTO{}, ErrPermissionNotHeld
+	}
+
+	if _, err := s.repo.Get(name); err == nil {
+		return dto.RoleDTO{}, ErrRoleExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logrus.WithError(err).WithField("role", name).Error("failed check role")
+		return dto.RoleDTO{}, err
+	}
+
+	if err := s.repo.Create(buildRole(name, in.Description, perms)); err != nil {
+		logrus.WithError(err).WithField("role", name).Error("failed create role")
+		return dto.RoleDTO{}, err
+	}
+	logrus.WithFields(logrus.Fields{"role": name, "permissions": perms}).Info("role created")
+	return s.GetRole(name)
+}
+
+func (s *roleService) UpdateRole(held []models.Permission, name models.Role, in dto.RoleInputDTO) (dto.RoleDTO, error) {
+	if name == models.Superadmin {
+		return dto.RoleDTO{}, ErrSystemRole
+	}
+	current, err := s.GetRole(name)
+	if err != nil {
+		return dto.RoleDTO{}, err
+	}
+	perms, err := normalizePermissions(in.Permissions)
+	if err != nil {
+		return dto.RoleDTO{}, err
+	}
+	// removing a permission is as powerful as granting it
+	if !holdsAll(held, perms) || !holdsAll(held, current.Permissions) {
+		return dto.RoleDTO{}, ErrPermissionNotHeld
+	}
+
+	if err := s.repo.Update(buildRole(name, in.Description, perms)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.RoleDTO{}, ErrNotFoundRole
+		}
+		logrus.WithError(err).WithField("role", name).Error("failed update role")
+		return dto.RoleDTO{}, err
+	}
+	logrus.WithFields(logrus.Fields{"role": name, "permissions": perms}).Info("role updated")
+	return s.GetRole(name)
+}
+
+func (s *roleService) DeleteRole(held []models.Permission, name models.Role) error {
+	current, err := s.GetRole(name)
+	if err != nil {
+		return err
+	}
+	if current.System {
+		return ErrSystemRole
+	}
+	if !holdsAll(held, current.Permissions) {
+		return ErrPermissionNotHeld
+	}
+
+	n, err := s.repo.CountAdmins(name)
+	if err != nil {
+		logrus.WithError(err).WithField("role", name).Error("failed count role admins")
+		return err
+	}
+	if n > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.repo.Delete(name); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundRole
+		}
+		logrus.WithError(err).WithField("role", name).Error("failed delete role")
+		return err
+	}
+	logrus.WithField("role", name).Info("role deleted")
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"math"
+	"sort"
+)
+
+// selectionWeightTotal is the required sum of component weights of a period.
+const selectionWeightTotal = 100.0
+
+// weightsValid allows for float rounding in weights such as 33.33 + 33.33 + 33.34.
+func weightsValid(components []models.SelectionComponent) bool {
+	var total float64
+	for _, c := range components {
+		total += c.Weight
+	}
+	return math.Abs(total-selectionWeightTotal) < 0.01
+}
+
+// finalScore weighs every entered score as score/max_score*weight, rounded to 2 decimals.
+// complete is false while a component has no score.
+func finalScore(components []models.SelectionComponent, scores map[uint]float64) (final float64, complete bool) {
+	complete = len(components) > 0
+	for _, c := range components {
+		s, ok := scores[c.ID]
+		if !ok {
+			complete = false
+			continue
+		}
+		final += s / c.MaxScore * c.Weight
+	}
+	return math.Round(final*100) / 100, complete
+}
+
+type rankedCandidate struct {
+	repository.SelectionCandidate
+	Rank     int
+	Final    float64
+	Complete bool
+}
+
+// rankCandidates orders complete results by final score (earlier registration wins ties)
+// and numbers them from 1; incomplete results follow with rank 0.
+func rankCandidates(components []models.SelectionComponent, candidates []repository.SelectionCandidate, scores []models.SelectionScore) []rankedCandidate {
+	byReg := make(map[uint]map[uint]float64)
+	for _, s := range scores {
+		if byReg[s.RegistrationID] == nil {
+			byReg[s.RegistrationID] = make(map[uint]float64)
+		}
+		byReg[s.RegistrationID][s.ComponentID] = s.Score
+	}
+
+	ranked := make([]rankedCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		final, complete := finalScore(components, byReg[c.ID])
+		ranked = append(ranked, rankedCandidate{SelectionCandidate: c, Final: final, Complete: complete})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Complete != b.Complete {
+			return a.Complete
+		}
+		if a.Final != b.Final {
+			return a.Final > b.Final
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	for i := range ranked {
+		if ranked[i].Complete {
+			ranked[i].Rank = i + 1
+		}
+	}
+	return ranked
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"testing"
+	"time"
+)
+
+var testComponents = []models.SelectionComponent{
+	{ID: 1, Key: "quran", Weight: 40, MaxScore: 100},
+	{ID: 2, Key: "written", Weight: 35, MaxScore: 50},
+	{ID: 3, Key: "interview", Weight: 25, MaxScore: 10},
+}
+
+func TestFinalScore(t *testing.T) {
+	final, complete := finalScore(testComponents, map[uint]float64{1: 80, 2: 40, 3: 7})
+	// 80/100*40 + 40/50*35 + 7/10*25 = 32 + 28 + 17.5
+	if final != 77.5 || !complete {
+		t.Errorf("finalScore() = %v, %v; want 77.5, true", final, complete)
+	}
+
+	final, complete = finalScore(testComponents, map[uint]float64{1: 100})
+	if final != 40 || complete {
+		t.Errorf("finalScore() partial = %v, %v; want 40, false", final, complete)
+	}
+
+	if _, complete := finalScore(nil, nil); complete {
+		t.Error("finalScore() without components should not be complete")
+	}
+}
+
+func TestWeightsValid(t *testing.T) {
+	if !weightsValid(testComponents) {
+		t.Error("weightsValid() = false for 40+35+25")
+	}
+	thirds := []models.SelectionComponent{{Weight: 33.33}, {Weight: 33.33}, {Weight: 33.34}}
+	if !weightsValid(thirds) {
+		t.Error("weightsValid() = false for 33.33+33.33+33.34")
+	}
+	if weightsValid(testComponents[:2]) {
+		t.Error("weightsValid() = true for 40+35")
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	candidates := []repository.SelectionCandidate{
+		{ID: 1, CreatedAt: base.Add(3 * time.Hour)},
+		{ID: 2, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 3, CreatedAt: base.Add(1 * time.Hour)},
+		{ID: 4, CreatedAt: base},
+	}
+	scores := []models.SelectionScore{
+		{RegistrationID: 1, ComponentID: 1, Score: 90}, {RegistrationID: 1, ComponentID: 2, Score: 40}, {RegistrationID: 1, ComponentID: 3, Score: 8},
+		{RegistrationID: 2, ComponentID: 1, Score: 90}, {RegistrationID: 2, ComponentID: 2, Score: 40}, {RegistrationID: 2, ComponentID: 3, Score: 8},
+		{RegistrationID: 3, ComponentID: 1, Score: 50}, {RegistrationID: 3, ComponentID: 2, Score: 30}, {RegistrationID: 3, ComponentID: 3, Score: 5},
+		{RegistrationID: 4, ComponentID: 1, Score: 100}, // incomplete
+	}
+
+	ranked := rankCandidates(testComponents, candidates, scores)
+
+	wantIDs := []uint{2, 1, 3, 4} // 1 and 2 tie; 2 registered earlier
+	wantRanks := []int{1, 2, 3, 0}
+	for i, r := range ranked {
+		if r.ID != wantIDs[i] || r.Rank != wantRanks[i] {
+			t.Errorf("ranked[%d] = id %d rank %d, want id %d rank %d", i, r.ID, r.Rank, wantIDs[i], wantRanks[i])
+		}
+	}
+	if ranked[3].Complete {
+		t.Error("candidate without all scores should be incomplete")
+	}
+}
@@ -0,0 +1,505 @@
+package service
+
+import (
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type SelectionService interface {
+	// Components (superadmin)
+	GetComponents(periodID uint) ([]dto.SelectionComponentDTO, error)
+	SetComponents(periodID uint, components []dto.SelectionComponentDTO) ([]dto.SelectionComponentDTO, error)
+
+	// Sessions
+	CreateSession(sessionDTO dto.SelectionSessionDTO) (dto.SelectionSessionDTO, error)
+	GetSessions(periodID uint) ([]dto.SelectionSessionDTO, error)
+	GetSessionByID(id uint) (dto.SelectionSessionDTO, error)
+	UpdateSession(id uint, sessionDTO dto.SelectionSessionDTO) (dto.SelectionSessionDTO, error)
+	DeleteSession(id uint) error
+	GetSessionCandidates(id uint) ([]dto.SelectionCandidateDTO, error)
+	Assign(sessionID uint, registrationIDs []uint) error
+	Unassign(sessionID, registrationID uint) error
+
+	// Scores and ranking
+	GetResult(registrationID uint) (dto.SelectionResultDTO, error)
+	SaveScores(registrationID uint, scores []dto.SelectionScoreInputDTO, adminID uint) (dto.SelectionResultDTO, error)
+	GetRanking(periodID uint, gender models.Gender, program models.Program) ([]dto.RankingEntryDTO, error)
+	ApplyRanking(periodID uint, req dto.RankingApplyDTO, adminID uint) (dto.RankingApplyResultDTO, error)
+}
+
+type selectionService struct {
+	repo       repository.SelectionRepo
+	regRepo    repository.RegistrationRepo
+	periodRepo repository.AdmissionPeriodRepo
+	quotaRepo  repository.AdmissionQuotaRepo
+}
+
+func NewSelectionService(repo repository.SelectionRepo, regRepo repository.RegistrationRepo, periodRepo repository.AdmissionPeriodRepo, quotaRepo repository.AdmissionQuotaRepo) SelectionService {
+	return &selectionService{repo: repo, regRepo: regRepo, periodRepo: periodRepo, quotaRepo: quotaRepo}
+}
+
+func (s *selectionService) checkPeriod(periodID uint) error {
+	if _, err := s.periodRepo.GetByID(periodID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundAdmissionPeriod
+		}
+		return err
+	}
+	return nil
+}
+
+// ======================
+//  COMPONENTS
+// ======================
+
+func (s *selectionService) GetComponents(periodID uint) ([]dto.SelectionComponentDTO, error) {
+	if err := s.checkPeriod(periodID); err != nil {
+		return nil, err
+	}
+
+	components, err := s.repo.GetComponents(periodID)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", periodID).Error("failed get selection components")
+		return nil, err
+	}
+
+	out := make([]dto.SelectionComponentDTO, 0, len(components))
+	for _, c := range components {
+		out = append(out, dto.SelectionComponentModelToDTO(c))
+	}
+	return out, nil
+}
+
+func (s *selectionService) SetComponents(periodID uint, components []dto.SelectionComponentDTO) ([]dto.SelectionComponentDTO, error) {
+	if err := s.checkPeriod(periodID); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	rows := make([]models.SelectionComponent, 0, len(components))
+	for _, c := range components {
+		if seen[c.Key] {
+			return nil, ErrDuplicateSelectionComponent
+		}
+		seen[c.Key] = true
+		rows = append(rows, dto.SelectionComponentDTOToModel(periodID, c))
+	}
+	if !weightsValid(rows) {
+		return nil, ErrSelectionWeights
+	}
+
+	if err := s.repo.ReplaceComponents(periodID, rows); err != nil {
+		if errors.Is(err, repository.ErrComponentInUse) {
+			return nil, ErrSelectionComponentInUse
+		}
+		logrus.WithError(err).WithField("period_id", periodID).Error("failed set selection components")
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"period_id":  periodID,
+		"components": len(rows),
+	}).Info("selection components updated")
+	return s.GetComponents(periodID)
+}
+
+// ======================
+//  SESSIONS
+// ======================
+
+func (s *selectionService) CreateSession(sessionDTO dto.SelectionSessionDTO) (dto.SelectionSessionDTO, error) {
+	if err := s.checkPeriod(sessionDTO.AdmissionPeriodID); err != nil {
+		return dto.SelectionSessionDTO{}, err
+	}
+
+	session := dto.SelectionSessionDTOToModel(sessionDTO)
+	session.ID = 0
+	if err := s.repo.CreateSession(&session); err != nil {
+		logrus.WithError(err).WithField("period_id", session.AdmissionPeriodID).Error("failed create selection session")
+		return dto.SelectionSessionDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":        session.ID,
+		"period_id": session.AdmissionPeriodID,
+	}).Info("selection session created")
+	return dto.SelectionSessionModelToDTO(session), nil
+}
+
+func (s *selectionService) GetSessions(periodID uint) ([]dto.SelectionSessionDTO, error) {
+	sessions, err := s.repo.GetSessions(periodID)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", periodID).Error("failed get selection sessions")
+		return nil, err
+	}
+
+	out := make([]dto.SelectionSessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		d, err := s.withAssigned(session)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (s *selectionService) getSession(id uint) (models.SelectionSession, error) {
+	session, err := s.repo.GetSessionByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, ErrNotFoundSelectionSession
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed get selection session")
+	}
+	return session, err
+}
+
+func (s *selectionService) GetSessionByID(id uint) (dto.SelectionSessionDTO, error) {
+	session, err := s.getSession(id)
+	if err != nil {
+		return dto.SelectionSessionDTO{}, err
+	}
+	return s.withAssigned(session)
+}
+
+func (s *selectionService) UpdateSession(id uint, sessionDTO dto.SelectionSessionDTO) (dto.SelectionSessionDTO, error) {
+	existing, err := s.getSession(id)
+	if err != nil {
+		return dto.SelectionSessionDTO{}, err
+	}
+
+	assigned, err := s.repo.CountAssignments(id)
+	if err != nil {
+		return dto.SelectionSessionDTO{}, err
+	}
+	if int64(sessionDTO.Capacity) < assigned {
+		return dto.SelectionSessionDTO{}, ErrSelectionSessionCapacity
+	}
+
+	// a session stays in its admission period
+	session := dto.SelectionSessionDTOToModel(sessionDTO)
+	session.ID = existing.ID
+	session.AdmissionPeriodID = existing.AdmissionPeriodID
+	session.CreatedAt = existing.CreatedAt
+
+	if err := s.repo.UpdateSession(session); err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed update selection session")
+		return dto.SelectionSessionDTO{}, err
+	}
+
+	logrus.WithField("id", id).Info("selection session updated")
+	return s.withAssigned(session)
+}
+
+func (s *selectionService) DeleteSession(id uint) error {
+	if err := s.repo.DeleteSession(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundSelectionSession
+		}
+		logrus.WithError(err).WithField("id", id).Error("failed delete selection session")
+		return err
+	}
+	logrus.WithField("id", id).Info("selection session deleted")
+	return nil
+}
+
+func (s *selectionService) GetSessionCandidates(id uint) ([]dto.SelectionCandidateDTO, error) {
+	if _, err := s.getSession(id); err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.GetSessionCandidates(id)
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("failed get selection session candidates")
+		return nil, err
+	}
+
+	out := make([]dto.SelectionCandidateDTO, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, selectionCandidateToDTO(c))
+	}
+	return out, nil
+}
+
+// Assign only accepts registrations of the session's period that passed validation.
+func (s *selectionService) Assign(sessionID uint, registrationIDs []uint) error {
+	session, err := s.getSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	seen := map[uint]bool{}
+	ids := make([]uint, 0, len(registrationIDs))
+	for _, id := range registrationIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		reg, err := s.getCandidate(id)
+		if err != nil {
+			return err
+		}
+		if reg.AdmissionPeriodID == nil || *reg.AdmissionPeriodID != session.AdmissionPeriodID {
+			return fmt.Errorf("%w: registration %d", ErrSelectionWrongPeriod, id)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := s.repo.Assign(sessionID, ids); err != nil {
+		if errors.Is(err, repository.ErrSessionFull) {
+			return ErrSelectionSessionFull
+		}
+		logrus.WithError(err).WithField("session_id", sessionID).Error("failed assign selection session")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"session_id":    sessionID,
+		"registrations": len(ids),
+	}).Info("registrations assigned to selection session")
+	return nil
+}
+
+func (s *selectionService) Unassign(sessionID, registrationID uint) error {
+	if err := s.repo.Unassign(sessionID, registrationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFoundSelectionAssignment
+		}
+		logrus.WithError(err).WithField("session_id", sessionID).Error("failed unassign selection session")
+		return err
+	}
+	return nil
+}
+
+func (s *selectionService) withAssigned(session models.SelectionSession) (dto.SelectionSessionDTO, error) {
+	d := dto.SelectionSessionModelToDTO(session)
+	count, err := s.repo.CountAssignments(session.ID)
+	if err != nil {
+		logrus.WithError(err).WithField("id", session.ID).Error("failed count selection assignments")
+		return dto.SelectionSessionDTO{}, err
+	}
+	d.Assigned = count
+	return d, nil
+}
+
+// ======================
+//  SCORES AND RANKING
+// ======================
+
+// getCandidate loads a registration that takes part in the selection.
+func (s *selectionService) getCandidate(id uint) (models.Registration, error) {
+	reg, err := s.regRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return reg, fmt.Errorf("%w: registration %d", ErrNotFoundRegistration, id)
+		}
+		return reg, err
+	}
+
+	for _, st := range repository.SelectionCandidateStatuses {
+		if reg.Status == st && reg.AdmissionPeriodID != nil {
+			return reg, nil
+		}
+	}
+	return reg, fmt.Errorf("%w: registration %d", ErrSelectionNotEligible, id)
+}
+
+func (s *selectionService) GetResult(registrationID uint) (dto.SelectionResultDTO, error) {
+	reg, err := s.regRepo.GetByID(registrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.SelectionResultDTO{}, ErrNotFoundRegistration
+		}
+		return dto.SelectionResultDTO{}, err
+	}
+	if reg.AdmissionPeriodID == nil {
+		return dto.SelectionResultDTO{}, ErrSelectionNotEligible
+	}
+
+	components, err := s.repo.GetComponents(*reg.AdmissionPeriodID)
+	if err != nil {
+		return dto.SelectionResultDTO{}, err
+	}
+	scores, err := s.repo.GetScores(registrationID)
+	if err != nil {
+		logrus.WithError(err).WithField("id", registrationID).Error("failed get selection scores")
+		return dto.SelectionResultDTO{}, err
+	}
+
+	byComponent := make(map[uint]float64, len(scores))
+	for _, sc := range scores {
+		byComponent[sc.ComponentID] = sc.Score
+	}
+
+	result := dto.SelectionResultDTO{
+		RegistrationID: registrationID,
+		Scores:         make([]dto.SelectionScoreDTO, 0, len(components)),
+	}
+	for _, c := range components {
+		item := dto.SelectionScoreDTO{
+			ComponentID: c.ID,
+			Key:         c.Key,
+			Name:        c.Name,
+			Weight:      c.Weight,
+			MaxScore:    c.MaxScore,
+		}
+		if v, ok := byComponent[c.ID]; ok {
+			item.Score = &v
+		}
+		result.Scores = append(result.Scores, item)
+	}
+	result.FinalScore, result.Complete = finalScore(components, byComponent)
+	return result, nil
+}
+
+func (s *selectionService) SaveScores(registrationID uint, scores []dto.SelectionScoreInputDTO, adminID uint) (dto.SelectionResultDTO, error) {
+	reg, err := s.getCandidate(registrationID)
+	if err != nil {
+		return dto.SelectionResultDTO{}, err
+	}
+
+	components, err := s.repo.GetComponents(*reg.AdmissionPeriodID)
+	if err != nil {
+		return dto.SelectionResultDTO{}, err
+	}
+	byID := make(map[uint]models.SelectionComponent, len(components))
+	for _, c := range components {
+		byID[c.ID] = c
+	}
+
+	rows := make([]models.SelectionScore, 0, len(scores))
+	for _, sc := range scores {
+		c, ok := byID[sc.ComponentID]
+		if !ok {
+			return dto.SelectionResultDTO{}, fmt.Errorf("%w: component %d", ErrInvalidSelectionComponent, sc.ComponentID)
+		}
+		if sc.Score < 0 || sc.Score > c.MaxScore {
+			return dto.SelectionResultDTO{}, fmt.Errorf("%w: %s (max %g)", ErrInvalidSelectionScore, c.Key, c.MaxScore)
+		}
+		rows = append(rows, models.SelectionScore{
+			RegistrationID: registrationID,
+			ComponentID:    sc.ComponentID,
+			Score:          sc.Score,
+			ScoredBy:       adminID,
+		})
+	}
+
+	if err := s.repo.SaveScores(rows); err != nil {
+		logrus.WithError(err).WithField("id", registrationID).Error("failed save selection scores")
+		return dto.SelectionResultDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       registrationID,
+		"scores":   len(rows),
+		"admin_id": adminID,
+	}).Info("selection scores saved")
+	return s.GetResult(registrationID)
+}
+
+func (s *selectionService) rank(periodID uint, gender models.Gender, program models.Program) ([]rankedCandidate, error) {
+	if err := s.checkPeriod(periodID); err != nil {
+		return nil, err
+	}
+
+	components, err := s.repo.GetComponents(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, ErrNoSelectionComponents
+	}
+	candidates, err := s.repo.GetCandidates(periodID, gender, program)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", periodID).Error("failed get selection candidates")
+		return nil, err
+	}
+	scores, err := s.repo.GetPeriodScores(periodID)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", periodID).Error("failed get selection scores")
+		return nil, err
+	}
+	return rankCandidates(components, candidates, scores), nil
+}
+
+func (s *selectionService) GetRanking(periodID uint, gender models.Gender, program models.Program) ([]dto.RankingEntryDTO, error) {
+	ranked, err := s.rank(periodID, gender, program)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.RankingEntryDTO, 0, len(ranked))
+	for _, r := range ranked {
+		out = append(out, dto.RankingEntryDTO{
+			Rank:                  r.Rank,
+			SelectionCandidateDTO: selectionCandidateToDTO(r.SelectionCandidate),
+			FinalScore:            r.Final,
+			Complete:              r.Complete,
+		})
+	}
+	return out, nil
+}
+
+// ApplyRanking moves the top req.Top complete results with final score >= req.MinScore to req.Status.
+// Registrations already in that status are left as is; "done" is never lowered to "process".
+func (s *selectionService) ApplyRanking(periodID uint, req dto.RankingApplyDTO, adminID uint) (dto.RankingApplyResultDTO, error) {
+	ranked, err := s.rank(periodID, req.Gender, req.Program)
+	if err != nil {
+		return dto.RankingApplyResultDTO{}, err
+	}
+
+	result := dto.RankingApplyResultDTO{Status: req.Status, RegistrationIDs: []uint{}}
+	for _, r := range ranked {
+		if !r.Complete || r.Rank > req.Top || r.Final < req.MinScore {
+			continue
+		}
+		if r.Status == req.Status || r.Status == models.RegistrationStatusDone {
+			continue
+		}
+		result.RegistrationIDs = append(result.RegistrationIDs, r.ID)
+	}
+
+	// all or nothing, so a failure never leaves the ranking half applied
+	promoted, err := s.quotaRepo.ChangeStatuses(result.RegistrationIDs, req.Status, &adminID)
+	if err != nil {
+		logrus.WithError(err).WithField("period_id", periodID).Error("failed apply selection ranking")
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return dto.RankingApplyResultDTO{}, ErrNotFoundRegistration
+		case errors.Is(err, repository.ErrNoSeatAvailable):
+			return dto.RankingApplyResultDTO{}, ErrQuotaFull
+		}
+		return dto.RankingApplyResultDTO{}, err
+	}
+	result.Updated = len(result.RegistrationIDs)
+	logPromoted(promoted)
+
+	logrus.WithFields(logrus.Fields{
+		"period_id": periodID,
+		"status":    req.Status,
+		"top":       req.Top,
+		"updated":   result.Updated,
+		"admin_id":  adminID,
+	}).Info("selection ranking applied")
+	return result, nil
+}
+
+func selectionCandidateToDTO(c repository.SelectionCandidate) dto.SelectionCandidateDTO {
+	return dto.SelectionCandidateDTO{
+		RegistrationID: c.ID,
+		FullName:       c.FullName,
+		NISN:           c.NISN,
+		Gender:         c.Gender,
+		Program:        c.Program,
+		Status:         c.Status,
+		SessionID:      c.SessionID,
+	}
+}
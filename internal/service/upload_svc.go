@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/dto"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"mime"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	UploadPurposeArticleHeader  = "article_header"
+	UploadPurposeArticleContent = "article_content"
+
+	uploadURLTTL = 30 * time.Minute
+)
+
+type uploadRule struct {
+	maxSize  int64
+	types    map[string]bool
+	objectFn func(stamp, safeName string) string
+}
+
+var (
+	imageTypes = map[string]bool{
+		"image/jpeg": true,
+		"image/png":  true,
+		"image/webp": true,
+		"image/gif":  true,
+	}
+	mediaTypes = map[string]bool{
+		"image/jpeg":      true,
+		"image/png":       true,
+		"image/webp":      true,
+		"image/gif":       true,
+		"video/mp4":       true,
+		"video/webm":      true,
+		"video/quicktime": true,
+	}
+
+	// object naming stays server-side and matches the multipart upload flow in ArticleHandler
+	uploadRules = map[string]uploadRule{
+		UploadPurposeArticleHeader: {
+			maxSize:  10 << 20,
+			types:    imageTypes,
+			objectFn: func(stamp, name string) string { return "articles/header_" + stamp + "_" + name },
+		},
+		UploadPurposeArticleContent: {
+			maxSize:  2 << 30,
+			types:    mediaTypes,
+			objectFn: func(stamp, name string) string { return "articles/content/" + stamp + "_" + name },
+		},
+	}
+
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+type UploadService interface {
+	CreateUpload(ctx context.Context, adminID uint, req dto.UploadRequestDTO) (dto.UploadTicketDTO, error)
+	CompleteUpload(ctx context.Context, id uint) (dto.MediaUploadDTO, error)
+}
+
+type uploadService struct {
+	repo  repository.MediaUploadRepo
+	store repository.ObjectStore
+}
+
+func NewUploadService(repo repository.MediaUploadRepo, store repository.ObjectStore) UploadService {
+	return &uploadService{repo: repo, store: store}
+}
+
+func baseMediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mt
+}
+
+func sanitizeFileName(name string) string {
+	name = unsafeFileChars.ReplaceAllString(filepath.Base(name), "_")
+	if name == "" || name == "." || name == "_" {
+		return "file"
+	}
+	return name
+}
+
+func (s *uploadService) CreateUpload(ctx context.Context, adminID uint, req dto.UploadRequestDTO) (dto.UploadTicketDTO, error) {
+	rule, ok := uploadRules[req.Purpose]
+	if !ok {
+		return dto.UploadTicketDTO{}, ErrUploadTypeNotAllowed
+	}
+	contentType := baseMediaType(req.ContentType)
+	if !rule.types[contentType] {
+		return dto.UploadTicketDTO{}, ErrUploadTypeNotAllowed
+	}
+	if req.Size > rule.maxSize {
+		return dto.UploadTicketDTO{}, ErrUploadTooLarge
+	}
+
+	objectName := rule.objectFn(strconv.FormatInt(time.Now().UnixNano(), 10), sanitizeFileName(req.FileName))
+
+	target, err := s.store.GenerateUploadURL(ctx, objectName, contentType, req.Resumable, uploadURLTTL)
+	if err != nil {
+		logrus.WithError(err).WithField("object", objectName).Error("failed generate upload url")
+		return dto.UploadTicketDTO{}, err
+	}
+
+	upload := models.MediaUpload{
+		ObjectName:  objectName,
+		FileName:    req.FileName,
+		ContentType: contentType,
+		Size:        req.Size,
+		Purpose:     req.Purpose,
+		UploadedBy:  adminID,
+	}
+	if err := s.repo.Create(&upload); err != nil {
+		logrus.WithError(err).WithField("object", objectName).Error("failed create media upload")
+		return dto.UploadTicketDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       upload.ID,
+		"object":   objectName,
+		"size":     req.Size,
+		"admin_id": adminID,
+	}).Info("direct upload issued")
+
+	return dto.UploadTicketDTO{
+		ID:         upload.ID,
+		ObjectName: objectName,
+		UploadURL:  target.URL,
+		Method:     target.Method,
+		Headers:    target.Headers,
+		ExpiresAt:  time.Now().Add(uploadURLTTL).Unix(),
+	}, nil
+}
+
+func (s *uploadService) CompleteUpload(ctx context.Context, id uint) (dto.MediaUploadDTO, error) {
+	upload, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.MediaUploadDTO{}, ErrNotFoundUpload
+		}
+		return dto.MediaUploadDTO{}, err
+	}
+	if upload.Status == models.MediaUploadCompleted {
+		return dto.MediaUploadModelToDTO(upload), nil
+	}
+
+	info, err := s.store.StatObject(ctx, upload.ObjectName)
+	if err != nil {
+		if errors.Is(err, repository.ErrObjectNotFound) {
+			return dto.MediaUploadDTO{}, ErrUploadIncomplete
+		}
+		logrus.WithError(err).WithField("object", upload.ObjectName).Error("failed stat uploaded object")
+		return dto.MediaUploadDTO{}, err
+	}
+
+	rule := uploadRules[upload.Purpose]
+	contentType := baseMediaType(info.ContentType)
+	if info.Size != upload.Size || info.Size > rule.maxSize || !rule.types[contentType] {
+		logrus.WithFields(logrus.Fields{
+			"object":        upload.ObjectName,
+			"size":          info.Size,
+			"declared_size": upload.Size,
+			"content_type":  info.ContentType,
+		}).Warn("uploaded object rejected")
+
+		if err := s.store.DeleteObject(ctx, upload.ObjectName); err != nil {
+			logrus.WithError(err).WithField("object", upload.ObjectName).Error("failed delete rejected object")
+		}
+		_ = s.repo.Delete(upload.ID)
+		return dto.MediaUploadDTO{}, ErrUploadMismatch
+	}
+
+	url, err := s.store.GenerateSignedURL(ctx, upload.ObjectName, 10*time.Minute)
+	if err != nil {
+		return dto.MediaUploadDTO{}, err
+	}
+
+	if err := s.repo.MarkCompleted(upload.ID, url, info.Size, contentType); err != nil {
+		logrus.WithError(err).WithField("id", upload.ID).Error("failed mark upload completed")
+		return dto.MediaUploadDTO{}, err
+	}
+
+	upload, err = s.repo.GetByID(upload.ID)
+	if err != nil {
+		return dto.MediaUploadDTO{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":     upload.ID,
+		"object": upload.ObjectName,
+	}).Info("direct upload completed")
+	return dto.MediaUploadModelToDTO(upload), nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"darulabror/internal/models"
+	"darulabror/internal/repository"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type fakeMediaUploadRepo struct {
+	repository.MediaUploadRepo
+	uploads map[uint]models.MediaUpload
+}
+
+func (f *fakeMediaUploadRepo) GetByID(id uint) (models.MediaUpload, error) {
+	u, ok := f.uploads[id]
+	if !ok {
+		return models.MediaUpload{}, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+func (f *fakeMediaUploadRepo) MarkCompleted(id uint, url string, size int64, contentType string) error {
+	u := f.uploads[id]
+	u.Status, u.URL, u.Size, u.ContentType = models.MediaUploadCompleted, url, size, contentType
+	f.uploads[id] = u
+	return nil
+}
+
+func (f *fakeMediaUploadRepo) Delete(id uint) error {
+	delete(f.uploads, id)
+	return nil
+}
+
+func TestCompleteUpload(t *testing.T) {
+	// ftyp box of a QuickTime movie, which http.DetectContentType does not know
+	mov := "\x00\x00\x00\x14ftypqt  \x00\x00\x02\x00qt  "
+
+	tests := []struct {
+		name     string
+		body     string
+		declared int64
+		wantErr  error
+	}{
+		{"quicktime", mov, int64(len(mov)), nil},
+		{"size mismatch", mov, int64(len(mov)) + 1, ErrUploadMismatch},
+		{"type mismatch", "#!/bin/sh\necho hi\n", 18, ErrUploadMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := repository.NewLocalStorageRepo(dir, "http://localhost/media", []byte("secret"), true)
+			const object = "articles/content/1_clip.mov"
+			if _, err := store.UploadFile(context.Background(), strings.NewReader(tt.body), object); err != nil {
+				t.Fatal(err)
+			}
+			repo := &fakeMediaUploadRepo{uploads: map[uint]models.MediaUpload{
+				1: {ID: 1, ObjectName: object, ContentType: "video/quicktime", Size: tt.declared, Purpose: UploadPurposeArticleContent, Status: models.MediaUploadPending},
+			}}
+
+			got, err := NewUploadService(repo, store).CompleteUpload(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompleteUpload() error = %v, want %v", err, tt.wantErr)
+			}
+			_, statErr := os.Stat(filepath.Join(dir, filepath.FromSlash(object)))
+			if tt.wantErr != nil {
+				if _, ok := repo.uploads[1]; ok || !errors.Is(statErr, os.ErrNotExist) {
+					t.Errorf("rejected upload not cleaned up: record kept = %v, stat = %v", ok, statErr)
+				}
+				return
+			}
+			if statErr != nil || got.Status != models.MediaUploadCompleted || got.ContentType != "video/quicktime" {
+				t.Errorf("CompleteUpload() = %+v, stat = %v", got, statErr)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 const (
 	CtxAdminIDKey = "admin_id"
 	CtxRoleKey    = "role"
+	// CtxPermissionsKey holds the []models.Permission of the token.
+	CtxPermissionsKey = "permissions"
 )
 
 func GetAdminID(c echo.Context) (uint, bool) {
@@ -28,3 +30,17 @@ func GetRole(c echo.Context) (models.Role, bool) {
 	role, ok := v.(models.Role)
 	return role, ok
 }
+
+func GetPermissions(c echo.Context) []models.Permission {
+	perms, _ := c.Get(CtxPermissionsKey).([]models.Permission)
+	return perms
+}
+
+func HasPermission(c echo.Context, p models.Permission) bool {
+	for _, held := range GetPermissions(c) {
+		if held == p {
+			return true
+		}
+	}
+	return false
+}
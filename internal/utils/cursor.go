@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the keyset position (created_at, id) of the last item of a page.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// PageInfo is returned by list queries next to the items.
+type PageInfo struct {
+	Total      *int64 // nil when not counted (cursor mode without with_total=true)
+	NextCursor string // empty on the last page (cursor mode only)
+}
+
+// EncodeCursor returns an opaque, URL-safe token.
+func EncodeCursor(c Cursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "." + strconv.FormatUint(uint64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(b), ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	id64, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: time.Unix(0, nanos), ID: uint(id64)}, nil
+}
+
+// ListMeta builds the "meta" object of list responses:
+// offset mode {page, limit, total}, cursor mode {limit, next_cursor[, total]}.
+func ListMeta(q ListQuery, p PageInfo) map[string]interface{} {
+	meta := map[string]interface{}{
+		"limit": q.Limit,
+	}
+	if q.CursorMode {
+		meta["next_cursor"] = p.NextCursor
+	} else {
+		meta["page"] = q.Page
+	}
+	if p.Total != nil {
+		meta["total"] = *p.Total
+	}
+	return meta
+}
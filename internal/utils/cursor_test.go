@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := Cursor{CreatedAt: time.Date(2025, 6, 1, 8, 30, 0, 123456000, time.UTC), ID: 42}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"", "!!", "bm90LWEtY3Vyc29y"} {
+		if _, err := DecodeCursor(bad); err == nil {
+			t.Errorf("DecodeCursor(%q) expected error", bad)
+		}
+	}
+}
+
+func TestListMeta(t *testing.T) {
+	total := int64(7)
+
+	offset := ListMeta(ListQuery{Page: 2, Limit: 10}, PageInfo{Total: &total})
+	if offset["page"] != 2 || offset["total"] != total {
+		t.Errorf("offset meta = %v", offset)
+	}
+	if _, ok := offset["next_cursor"]; ok {
+		t.Error("offset meta should not contain next_cursor")
+	}
+
+	cursor := ListMeta(ListQuery{Limit: 10, CursorMode: true}, PageInfo{NextCursor: "abc"})
+	if cursor["next_cursor"] != "abc" {
+		t.Errorf("cursor meta = %v", cursor)
+	}
+	if _, ok := cursor["total"]; ok {
+		t.Error("cursor meta should omit total unless counted")
+	}
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is the stable, machine-readable kind of a failed request. Clients switch on it
+// instead of on messages.
+type ErrorCode string
+
+const (
+	CodeBadRequest   ErrorCode = "bad_request"
+	CodeValidation   ErrorCode = "validation"
+	CodeUnauthorized ErrorCode = "unauthorized"
+	CodeForbidden    ErrorCode = "forbidden"
+	CodeNotFound     ErrorCode = "not_found"
+	CodeConflict     ErrorCode = "conflict"
+	CodeTooLarge     ErrorCode = "too_large"
+	CodeTooMany      ErrorCode = "too_many_requests"
+	CodeInternal     ErrorCode = "internal"
+	CodeUnavailable  ErrorCode = "unavailable"
+)
+
+var codeStatus = map[ErrorCode]int{
+	CodeBadRequest:   http.StatusBadRequest,
+	CodeValidation:   http.StatusUnprocessableEntity,
+	CodeUnauthorized: http.StatusUnauthorized,
+	CodeForbidden:    http.StatusForbidden,
+	CodeNotFound:     http.StatusNotFound,
+	CodeConflict:     http.StatusConflict,
+	CodeTooLarge:     http.StatusRequestEntityTooLarge,
+	CodeTooMany:      http.StatusTooManyRequests,
+	CodeInternal:     http.StatusInternalServerError,
+	CodeUnavailable:  http.StatusServiceUnavailable,
+}
+
+// Status is the HTTP status a code is reported with.
+func (c ErrorCode) Status() int {
+	if s, ok := codeStatus[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// codeForStatus is the code of a response written with a bare HTTP status.
+func codeForStatus(status int) ErrorCode {
+	for code, s := range codeStatus {
+		if s == status {
+			return code
+		}
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// DomainError is an error the client may see: its message is safe to return and its code
+// decides the HTTP status. Err keeps the underlying cause for logs.
+type DomainError struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+}
+
+// NewDomainError returns a sentinel domain error.
+func NewDomainError(code ErrorCode, message string) *DomainError {
+	return &DomainError{Code: code, Message: message}
+}
+
+func (e *DomainError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() error { return e.Err }
+
+// Is matches another domain error with the same code and message, so a sentinel still
+// matches after Wrap.
+func (e *DomainError) Is(target error) bool {
+	t, ok := target.(*DomainError)
+	return ok && t.Code == e.Code && t.Message == e.Message
+}
+
+// Wrap returns a copy of e carrying cause.
+func (e *DomainError) Wrap(cause error) *DomainError {
+	return &DomainError{Code: e.Code, Message: e.Message, Err: cause}
+}
+
+// AsDomainError finds the first domain error in err's chain.
+func AsDomainError(err error) (*DomainError, bool) {
+	var de *DomainError
+	if errors.As(err, &de) {
+		return de, true
+	}
+	return nil, false
+}
+
+// ErrorCodeOf is the code of err, CodeInternal when it is not a domain error.
+func ErrorCodeOf(err error) ErrorCode {
+	if de, ok := AsDomainError(err); ok {
+		return de.Code
+	}
+	return CodeInternal
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ETag returns a strong entity tag of the JSON encoding of v.
+func ETag(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// NotModified sets the ETag, Last-Modified and Cache-Control headers of a cacheable GET response
+// and reports whether the client's copy is still current, in which case the handler answers 304.
+// If-None-Match takes precedence over If-Modified-Since (RFC 9110 13.2.2).
+func NotModified(c echo.Context, etag string, lastModified time.Time, cacheControl string) bool {
+	h := c.Response().Header()
+	h.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		h.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", cacheControl)
+
+	req := c.Request()
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	if ims := req.Header.Get(echo.HeaderIfModifiedSince); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// etagMatches compares an If-None-Match list with etag using the weak comparison.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestNotModified(t *testing.T) {
+	etag, err := ETag(map[string]int{"id": 1})
+	if err != nil {
+		t.Fatalf("ETag() error = %v", err)
+	}
+	lastModified := time.Date(2025, 1, 30, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{"no validators", http.MethodGet, nil, false},
+		{"matching etag", http.MethodGet, map[string]string{"If-None-Match": etag}, true},
+		{"etag in list", http.MethodGet, map[string]string{"If-None-Match": `"abc", W/` + etag}, true},
+		{"wildcard", http.MethodGet, map[string]string{"If-None-Match": "*"}, true},
+		{"other etag wins over date", http.MethodGet, map[string]string{"If-None-Match": `"abc"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)}, false},
+		{"same date", http.MethodGet, map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, true},
+		{"older date", http.MethodGet, map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, false},
+		{"bad date", http.MethodGet, map[string]string{"If-Modified-Since": "yesterday"}, false},
+		{"not a GET", http.MethodPost, map[string]string{"If-None-Match": etag}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/articles/1", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			if got := NotModified(c, etag, lastModified, "public, max-age=60"); got != tc.want {
+				t.Errorf("NotModified() = %v, want %v", got, tc.want)
+			}
+			if rec.Header().Get("ETag") != etag || rec.Header().Get("Cache-Control") != "public, max-age=60" ||
+				rec.Header().Get("Last-Modified") != "Thu, 30 Jan 2025 10:00:00 GMT" {
+				t.Errorf("headers = %v", rec.Header())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// WriteError writes err in the error envelope. Domain errors keep their code and message,
+// validation and Echo errors keep their status, and anything else is logged and reported as
+// a 500 with fallback so internal details never reach the client.
+func WriteError(c echo.Context, err error, fallback string) error {
+	if de, ok := AsDomainError(err); ok {
+		if de.Err != nil && de.Code.Status() >= http.StatusInternalServerError {
+			logger.WithError(de.Err).WithField("path", c.Request().URL.Path).Error(de.Message)
+		}
+		return sendErrorResponse(c, de.Code.Status(), de.Message, nil)
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return ValidationErrorResponse(c, err)
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		msg, ok := he.Message.(string)
+		if !ok {
+			msg = http.StatusText(he.Code)
+		}
+		return sendErrorResponse(c, he.Code, msg, nil)
+	}
+
+	logger.WithError(err).WithField("path", c.Request().URL.Path).Error(fallback)
+	return InternalServerErrorResponse(c, fallback)
+}
+
+// HTTPErrorHandler is the Echo error handler: errors returned by handlers and middleware, and
+// Echo's own 404/405s, are written through WriteError.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	if werr := WriteError(c, err, "internal server error"); werr != nil {
+		logger.WithError(werr).Error("failed to write error response")
+	}
+}
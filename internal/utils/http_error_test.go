@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+var errTestNotFound = NewDomainError(CodeNotFound, "thing not found")
+
+func TestDomainErrorIs(t *testing.T) {
+	wrapped := fmt.Errorf("load: %w", errTestNotFound.Wrap(errors.New("record not found")))
+
+	if !errors.Is(wrapped, errTestNotFound) {
+		t.Fatal("wrapped domain error should match its sentinel")
+	}
+	if errors.Is(wrapped, NewDomainError(CodeConflict, "thing not found")) {
+		t.Fatal("a different code must not match")
+	}
+	if got := ErrorCodeOf(wrapped); got != CodeNotFound {
+		t.Fatalf("ErrorCodeOf = %q", got)
+	}
+	if got := ErrorCodeOf(errors.New("boom")); got != CodeInternal {
+		t.Fatalf("ErrorCodeOf(plain) = %q", got)
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		status  int
+		code    ErrorCode
+		message string
+	}{
+		{"domain", fmt.Errorf("svc: %w", errTestNotFound), http.StatusNotFound, CodeNotFound, "thing not found"},
+		{"domain with cause", errTestNotFound.Wrap(errors.New("sql: no rows")), http.StatusNotFound, CodeNotFound, "thing not found"},
+		{"echo", echo.ErrMethodNotAllowed, http.StatusMethodNotAllowed, CodeBadRequest, "Method Not Allowed"},
+		{"echo route", echo.ErrNotFound, http.StatusNotFound, CodeNotFound, "Not Found"},
+		{"internal", errors.New("pq: connection refused"), http.StatusInternalServerError, CodeInternal, "internal server error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/things/1", nil), rec)
+
+			HTTPErrorHandler(tc.err, c)
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.status)
+			}
+			var body ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Status != "error" || body.Code != tc.code || body.Message != tc.message {
+				t.Fatalf("body = %+v", body)
+			}
+		})
+	}
+}
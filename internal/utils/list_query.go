@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ListQuery is the common filter/sort input of admin list endpoints:
+// ?page=&limit=&q=&status=a,b&from=YYYY-MM-DD&to=YYYY-MM-DD&sort=&order=asc|desc
+//
+// Passing cursor (empty for the first page) switches from OFFSET to keyset
+// pagination on (created_at, id); total is then only counted with with_total=true.
+type ListQuery struct {
+	Page       int
+	Limit      int
+	CursorMode bool
+	Cursor     *Cursor // nil = first page
+	WithTotal  bool
+
+	Search   string
+	Statuses []string
+	From     *time.Time // created_at >= From
+	To       *time.Time // created_at < To (the day after the requested "to" date)
+	Sort     string     // empty = endpoint default
+	Desc     bool
+	Assignee *uint // assigned admin; 0 = unassigned, nil = no filter
+}
+
+// ParseListQuery validates sort and status against the endpoint's whitelists.
+// An empty statuses list means the endpoint has no status filter.
+func ParseListQuery(c echo.Context, sortFields, statuses []string) (ListQuery, error) {
+	q, err := ParsePageQuery(c)
+	if err != nil {
+		return q, err
+	}
+	q.Search = strings.TrimSpace(c.QueryParam("q"))
+
+	for _, raw := range c.QueryParams()["status"] {
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			if !contains(statuses, s) {
+				return q, errors.New("invalid status: " + s)
+			}
+			q.Statuses = append(q.Statuses, s)
+		}
+	}
+
+	if v := c.QueryParam("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return q, errors.New("invalid from (expected YYYY-MM-DD)")
+		}
+		q.From = &t
+	}
+	if v := c.QueryParam("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return q, errors.New("invalid to (expected YYYY-MM-DD)")
+		}
+		t = t.AddDate(0, 0, 1) // inclusive end date
+		q.To = &t
+	}
+
+	if v := c.QueryParam("sort"); v != "" {
+		if !contains(sortFields, v) {
+			return q, errors.New("invalid sort (allowed: " + strings.Join(sortFields, ", ") + ")")
+		}
+		q.Sort = v
+	}
+	if q.CursorMode && q.Sort != "" && q.Sort != "created_at" {
+		return q, errors.New("cursor pagination only supports sort=created_at")
+	}
+	switch strings.ToLower(c.QueryParam("order")) {
+	case "", "desc":
+	case "asc":
+		q.Desc = false
+	default:
+		return q, errors.New("invalid order (expected asc or desc)")
+	}
+
+	return q, nil
+}
+
+// ParseAssignee reads ?assignee=me|none|<admin id> of lists whose items can be assigned;
+// "me" is the logged-in admin ("my queue").
+func ParseAssignee(c echo.Context, q *ListQuery) error {
+	v := strings.TrimSpace(c.QueryParam("assignee"))
+	switch v {
+	case "":
+		return nil
+	case "me":
+		id, ok := GetAdminID(c)
+		if !ok {
+			return errors.New("assignee=me requires a logged-in admin")
+		}
+		q.Assignee = &id
+	case "none":
+		none := uint(0)
+		q.Assignee = &none
+	default:
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || id == 0 {
+			return errors.New("invalid assignee (expected me, none or an admin id)")
+		}
+		admin := uint(id)
+		q.Assignee = &admin
+	}
+	return nil
+}
+
+// ParsePageQuery reads only the pagination part (page/limit or cursor); used by public lists.
+func ParsePageQuery(c echo.Context) (ListQuery, error) {
+	q := ListQuery{Desc: true}
+	q.Page, q.Limit = ParsePagination(c)
+
+	if c.QueryParams().Has("cursor") {
+		q.CursorMode = true
+		if v := c.QueryParam("cursor"); v != "" {
+			cur, err := DecodeCursor(v)
+			if err != nil {
+				return q, err
+			}
+			q.Cursor = &cur
+		}
+		q.WithTotal, _ = strconv.ParseBool(c.QueryParam("with_total"))
+	}
+	return q, nil
+}
+
+// CacheKey identifies the query for caching its result; equal queries give equal keys.
+func (q ListQuery) CacheKey() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "p=%d|l=%d|cm=%t|wt=%t", q.Page, q.Limit, q.CursorMode, q.WithTotal)
+	if q.Cursor != nil {
+		b.WriteString("|c=" + EncodeCursor(*q.Cursor))
+	}
+	if q.Search != "" {
+		b.WriteString("|q=" + strconv.Quote(q.Search))
+	}
+	if len(q.Statuses) > 0 {
+		b.WriteString("|s=" + strings.Join(q.Statuses, ","))
+	}
+	if q.From != nil {
+		fmt.Fprintf(&b, "|f=%d", q.From.Unix())
+	}
+	if q.To != nil {
+		fmt.Fprintf(&b, "|t=%d", q.To.Unix())
+	}
+	fmt.Fprintf(&b, "|o=%s:%t", q.Sort, q.Desc)
+	if q.Assignee != nil {
+		fmt.Fprintf(&b, "|a=%d", *q.Assignee)
+	}
+	return b.String()
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newListContext(query string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/admin/registrations?"+query, nil)
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func TestParseListQuery(t *testing.T) {
+	sorts := []string{"id", "created_at", "full_name"}
+	statuses := []string{"new", "validate", "done"}
+
+	q, err := ParseListQuery(newListContext("q=+siti+&status=new,done&status=validate&from=2025-01-01&to=2025-01-31&sort=full_name&order=asc&limit=500"), sorts, statuses)
+	if err != nil {
+		t.Fatalf("ParseListQuery() error = %v", err)
+	}
+	if q.Search != "siti" || q.Sort != "full_name" || q.Desc || q.Limit != MaxLimit {
+		t.Errorf("ParseListQuery() = %+v", q)
+	}
+	if len(q.Statuses) != 3 {
+		t.Errorf("Statuses = %v, want 3 values", q.Statuses)
+	}
+	if q.From == nil || q.To == nil || q.To.Format("2006-01-02") != "2025-02-01" {
+		t.Errorf("date range = %v - %v, want inclusive end", q.From, q.To)
+	}
+
+	invalid := []string{
+		"sort=password",
+		"status=deleted",
+		"order=up",
+		"from=01-01-2025",
+	}
+	for _, query := range invalid {
+		if _, err := ParseListQuery(newListContext(query), sorts, statuses); err == nil {
+			t.Errorf("ParseListQuery(%q) expected error", query)
+		}
+	}
+
+	if _, err := ParseListQuery(newListContext("status=new"), sorts, nil); err == nil {
+		t.Error("status filter should be rejected when the endpoint has none")
+	}
+}
+
+func TestParseAssignee(t *testing.T) {
+	c := newListContext("assignee=me")
+	c.Set(CtxAdminIDKey, uint(7))
+	var q ListQuery
+	if err := ParseAssignee(c, &q); err != nil || q.Assignee == nil || *q.Assignee != 7 {
+		t.Errorf("assignee=me: Assignee = %v, err = %v, want 7", q.Assignee, err)
+	}
+
+	q = ListQuery{}
+	if err := ParseAssignee(newListContext("assignee=none"), &q); err != nil || q.Assignee == nil || *q.Assignee != 0 {
+		t.Errorf("assignee=none: Assignee = %v, err = %v, want 0", q.Assignee, err)
+	}
+
+	q = ListQuery{}
+	if err := ParseAssignee(newListContext(""), &q); err != nil || q.Assignee != nil {
+		t.Errorf("no assignee: Assignee = %v, err = %v, want nil", q.Assignee, err)
+	}
+
+	for _, query := range []string{"assignee=0", "assignee=bob", "assignee=me"} {
+		if err := ParseAssignee(newListContext(query), &ListQuery{}); err == nil {
+			t.Errorf("ParseAssignee(%q) expected error", query)
+		}
+	}
+}
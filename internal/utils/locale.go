@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"darulabror/internal/models"
+	"errors"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+var localeMatcher = language.NewMatcher(localeTags())
+
+func localeTags() []language.Tag {
+	tags := make([]language.Tag, len(models.Locales))
+	for i, l := range models.Locales {
+		tags[i] = language.Make(string(l))
+	}
+	return tags
+}
+
+// ParseLang reads ?lang= (id, en, ar); empty when not given.
+func ParseLang(c echo.Context) (models.Locale, error) {
+	v := strings.ToLower(strings.TrimSpace(c.QueryParam("lang")))
+	if v == "" {
+		return "", nil
+	}
+	l := models.Locale(v)
+	if !l.Valid() {
+		return "", errors.New("invalid lang (expected id, en or ar)")
+	}
+	return l, nil
+}
+
+// ResolveLocale picks the content locale of a public request: ?lang= first, then the best
+// Accept-Language match, then the default locale.
+func ResolveLocale(c echo.Context) (models.Locale, error) {
+	l, err := ParseLang(c)
+	if err != nil || l != "" {
+		return l, err
+	}
+	return MatchAcceptLanguage(c.Request().Header.Get("Accept-Language")), nil
+}
+
+// MatchAcceptLanguage returns the supported locale preferred by an Accept-Language header.
+func MatchAcceptLanguage(header string) models.Locale {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return models.DefaultLocale
+	}
+	_, i, conf := localeMatcher.Match(tags...)
+	if conf == language.No {
+		return models.DefaultLocale
+	}
+	return models.Locales[i]
+}
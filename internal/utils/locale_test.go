@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"darulabror/internal/models"
+	"testing"
+)
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	for header, want := range map[string]models.Locale{
+		"":                           models.LocaleIndonesian,
+		"ar-SA,ar;q=0.9,en;q=0.8":    models.LocaleArabic,
+		"en-GB,en;q=0.9":             models.LocaleEnglish,
+		"fr-FR,fr;q=0.9,en;q=0.5":    models.LocaleEnglish,
+		"id-ID,id;q=0.9,en-US;q=0.8": models.LocaleIndonesian,
+		"de-DE":                      models.LocaleIndonesian,
+		"en;q=0.2,ar;q=0.8":          models.LocaleArabic,
+		"not a header;;;":            models.LocaleIndonesian,
+	} {
+		if got := MatchAcceptLanguage(header); got != want {
+			t.Errorf("MatchAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
@@ -28,12 +28,14 @@ type SuccessResponseData struct {
 }
 
 type ErrorResponse struct {
-	Status  string `json:"status" example:"error"`
-	Message string `json:"message"`
+	Status  string       `json:"status" example:"error"`
+	Code    ErrorCode    `json:"code" example:"not_found"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
 }
 
-// sendResponse is a helper function to send JSON responses with logging
-func sendResponse(c echo.Context, code int, status string, message string, data interface{}) error {
+// logResponse logs a response with the request and admin context.
+func logResponse(c echo.Context, code int, message string) {
 	fields := logrus.Fields{
 		"method": c.Request().Method,
 		"path":   c.Request().URL.Path,
@@ -55,41 +57,24 @@ func sendResponse(c echo.Context, code int, status string, message string, data
 	} else {
 		logger.WithFields(fields).Info(message)
 	}
+}
 
-	resp := map[string]interface{}{
-		"status":  status,
-		"message": message,
-	}
-	if data != nil {
-		resp["data"] = data
-	}
-	return c.JSON(code, resp)
+// sendResponse is a helper function to send JSON responses with logging
+func sendResponse(c echo.Context, code int, status string, message string, data interface{}) error {
+	logResponse(c, code, message)
+	return c.JSON(code, Response{Status: status, Message: message, Data: data})
+}
+
+// sendErrorResponse writes the error shape shared by every failed request; the error code
+// follows the HTTP status and fieldErrs is only set for validation failures.
+func sendErrorResponse(c echo.Context, code int, message string, fieldErrs []FieldError) error {
+	logResponse(c, code, message)
+	return c.JSON(code, ErrorResponse{Status: "error", Code: codeForStatus(code), Message: message, Errors: fieldErrs})
 }
 
 // sendNoContent logs like sendResponse, but returns a proper 204 with no body.
 func sendNoContent(c echo.Context, code int, message string) error {
-	fields := logrus.Fields{
-		"method": c.Request().Method,
-		"path":   c.Request().URL.Path,
-		"status": code,
-	}
-
-	// Admin-only context (set by JWT middleware)
-	if adminID, ok := GetAdminID(c); ok {
-		fields["admin_id"] = adminID
-	}
-	if role, ok := GetRole(c); ok {
-		fields["role"] = role
-	}
-
-	if code >= 500 {
-		logger.WithFields(fields).Error(message)
-	} else if code >= 400 {
-		logger.WithFields(fields).Warn(message)
-	} else {
-		logger.WithFields(fields).Info(message)
-	}
-
+	logResponse(c, code, message)
 	return c.NoContent(code)
 }
 
@@ -101,35 +86,47 @@ func CreatedResponse(c echo.Context, message string, data interface{}) error {
 	return sendResponse(c, http.StatusCreated, "success", message, data)
 }
 
+func AcceptedResponse(c echo.Context, message string, data interface{}) error {
+	return sendResponse(c, http.StatusAccepted, "success", message, data)
+}
+
 func NoContentResponse(c echo.Context) error {
 	// 204 must not return a body
 	return sendNoContent(c, http.StatusNoContent, "No Content")
 }
 
 func BadRequestResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusBadRequest, "error", message, nil)
+	return sendErrorResponse(c, http.StatusBadRequest, message, nil)
 }
 
 func UnauthorizedResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusUnauthorized, "error", message, nil)
+	return sendErrorResponse(c, http.StatusUnauthorized, message, nil)
 }
 
 func ForbiddenResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusForbidden, "error", message, nil)
+	return sendErrorResponse(c, http.StatusForbidden, message, nil)
 }
 
 func NotFoundResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusNotFound, "error", message, nil)
+	return sendErrorResponse(c, http.StatusNotFound, message, nil)
 }
 
 func ConflictResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusConflict, "error", message, nil)
+	return sendErrorResponse(c, http.StatusConflict, message, nil)
+}
+
+func RequestEntityTooLargeResponse(c echo.Context, message string) error {
+	return sendErrorResponse(c, http.StatusRequestEntityTooLarge, message, nil)
 }
 
 func UnprocessableEntityResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusUnprocessableEntity, "error", message, nil)
+	return sendErrorResponse(c, http.StatusUnprocessableEntity, message, nil)
 }
 
 func InternalServerErrorResponse(c echo.Context, message string) error {
-	return sendResponse(c, http.StatusInternalServerError, "error", message, nil)
+	return sendErrorResponse(c, http.StatusInternalServerError, message, nil)
+}
+
+func ServiceUnavailableResponse(c echo.Context, message string) error {
+	return sendErrorResponse(c, http.StatusServiceUnavailable, message, nil)
 }
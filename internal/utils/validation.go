@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+// validationLanguages are the languages validation messages are translated to; the first one
+// is used when Accept-Language matches none of them.
+var validationLanguages = []string{"id", "en"}
+
+var (
+	validationMatcher = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+	validationUni     *ut.UniversalTranslator
+)
+
+// FieldError is one failed validation rule of a request body.
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"required"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message" example:"email wajib diisi"`
+}
+
+// RegisterValidationTranslations installs the Indonesian and English messages on v. It must be
+// called once at startup, before requests are served.
+func RegisterValidationTranslations(v *validator.Validate) error {
+	uni := ut.New(id.New(), id.New(), en.New())
+
+	idTrans, _ := uni.GetTranslator("id")
+	if err := id_translations.RegisterDefaultTranslations(v, idTrans); err != nil {
+		return err
+	}
+	enTrans, _ := uni.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return err
+	}
+
+	validationUni = uni
+	return nil
+}
+
+// ValidationLanguage returns the validation message language preferred by an Accept-Language
+// header.
+func ValidationLanguage(header string) string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return validationLanguages[0]
+	}
+	_, i, conf := validationMatcher.Match(tags...)
+	if conf == language.No {
+		return validationLanguages[0]
+	}
+	return validationLanguages[i]
+}
+
+// ValidationErrors converts a validator error into field errors with messages in lang.
+// Errors that did not come from the validator yield nil.
+func ValidationErrors(err error, lang string) []FieldError {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	var trans ut.Translator
+	if validationUni != nil {
+		trans, _ = validationUni.GetTranslator(lang)
+	}
+
+	out := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		out = append(out, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe, trans),
+		})
+	}
+	return out
+}
+
+// fieldPath drops the struct name from the namespace, e.g. "RegistrationDTO.parent.phone"
+// becomes "parent.phone".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// fieldMessage translates fe, falling back to a plain message for rules without a
+// translation (fe.Translate then returns the raw validator error).
+func fieldMessage(fe validator.FieldError, trans ut.Translator) string {
+	if trans != nil {
+		if msg := fe.Translate(trans); msg != fe.Error() {
+			return msg
+		}
+	}
+	msg := fe.Field() + " failed " + fe.Tag()
+	if fe.Param() != "" {
+		msg += "=" + fe.Param()
+	}
+	return msg
+}
+
+// ValidationErrorResponse writes a 422 listing every failed field. The messages follow the
+// request's Accept-Language (Indonesian by default).
+func ValidationErrorResponse(c echo.Context, err error) error {
+	fieldErrs := ValidationErrors(err, ValidationLanguage(c.Request().Header.Get("Accept-Language")))
+	if fieldErrs == nil {
+		return UnprocessableEntityResponse(c, err.Error())
+	}
+	return sendErrorResponse(c, http.StatusUnprocessableEntity, "validation failed", fieldErrs)
+}
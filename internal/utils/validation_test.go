@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type validationAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validationInput struct {
+	Email   string            `json:"email" validate:"required,email"`
+	NISN    string            `json:"nisn" validate:"len=10"`
+	Address validationAddress `json:"address"`
+}
+
+func newTestValidator(t *testing.T) *validator.Validate {
+	t.Helper()
+	v := validator.New()
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	})
+	if err := RegisterValidationTranslations(v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValidationLanguage(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                        "id",
+		"en-US,en;q=0.9":          "en",
+		"ar-SA,ar;q=0.9,en;q=0.8": "en",
+		"id-ID,en;q=0.5":          "id",
+		"de-DE":                   "id",
+	} {
+		if got := ValidationLanguage(header); got != want {
+			t.Errorf("ValidationLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	v := newTestValidator(t)
+	err := v.Struct(validationInput{Email: "nope", NISN: "123"})
+
+	got := ValidationErrors(err, "en")
+	want := []FieldError{
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "nisn", Rule: "len", Param: "10", Message: "nisn must be 10 characters in length"},
+		{Field: "address.city", Rule: "required", Message: "city is a required field"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("en errors = %+v, want %+v", got, want)
+	}
+
+	idErrs := ValidationErrors(err, "id")
+	if len(idErrs) != 3 || idErrs[2].Message != "city wajib diisi" {
+		t.Fatalf("id errors = %+v", idErrs)
+	}
+
+	if ValidationErrors(errors.New("boom"), "en") != nil {
+		t.Fatal("non-validator error should yield nil")
+	}
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	v := newTestValidator(t)
+	req := httptest.NewRequest(http.MethodPost, "/contacts", nil)
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	if err := ValidationErrorResponse(c, v.Struct(validationInput{Email: "a@b.co", NISN: "1234567890"})); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != "error" || len(body.Errors) != 1 || body.Errors[0].Field != "address.city" {
+		t.Fatalf("body = %+v", body)
+	}
+}
@@ -64,3 +64,338 @@ CREATE TABLE IF NOT EXISTS contacts (
     status TEXT NOT NULL DEFAULT 'new' CHECK (status IN ('new','in_progress','done')),
     created_at BIGINT NOT NULL
 );
+
+-- Table: media_uploads (direct-to-bucket uploads)
+CREATE TABLE IF NOT EXISTS media_uploads (
+    id BIGSERIAL PRIMARY KEY,
+    object_name TEXT NOT NULL UNIQUE,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    purpose TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','completed')),
+    url TEXT,
+    uploaded_by BIGINT NOT NULL,
+    created_at BIGINT NOT NULL,
+    completed_at BIGINT NOT NULL DEFAULT 0
+);
+
+-- Table: export_jobs (background exports, files stored in the private bucket)
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    format TEXT NOT NULL,
+    params JSONB,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','running','done','failed')),
+    object_name TEXT,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by BIGINT NOT NULL,
+    created_at BIGINT NOT NULL,
+    completed_at BIGINT NOT NULL DEFAULT 0
+);
+
+-- Keyset pagination on (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_admins_created_at_id ON admins (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_articles_created_at_id ON articles (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_registrations_created_at_id ON registrations (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_contacts_created_at_id ON contacts (created_at, id);
+
+-- Table: admission_periods (academic year waves)
+CREATE TABLE IF NOT EXISTS admission_periods (
+    id BIGSERIAL PRIMARY KEY,
+    academic_year TEXT NOT NULL,
+    wave INT NOT NULL CHECK (wave >= 1),
+    name TEXT NOT NULL,
+    opens_at TIMESTAMPTZ NOT NULL,
+    closes_at TIMESTAMPTZ NOT NULL,
+    quota INT NOT NULL DEFAULT 0 CHECK (quota >= 0),
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    CHECK (closes_at > opens_at),
+    UNIQUE (academic_year, wave)
+);
+
+-- Registrations belong to a period; email/NISN are unique per period (re-apply in a later year).
+-- Registrations made before periods existed keep admission_period_id NULL.
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS admission_period_id BIGINT REFERENCES admission_periods(id);
+ALTER TABLE registrations DROP CONSTRAINT IF EXISTS registrations_email_key;
+ALTER TABLE registrations DROP CONSTRAINT IF EXISTS registrations_nisn_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registrations_period_email ON registrations (admission_period_id, email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registrations_period_nisn ON registrations (admission_period_id, nisn);
+
+-- Quotas per gender/program; waitlisted/withdrawn registrations do not hold a seat.
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS program TEXT NOT NULL DEFAULT 'boarding' CHECK (program IN ('boarding','non_boarding'));
+ALTER TABLE registrations DROP CONSTRAINT IF EXISTS registrations_status_check;
+ALTER TABLE registrations ADD CONSTRAINT registrations_status_check
+    CHECK (status IN ('new','validate','process','done','waitlisted','withdrawn'));
+CREATE INDEX IF NOT EXISTS idx_registrations_period_group ON registrations (admission_period_id, gender, program, status);
+
+-- Table: admission_quotas
+CREATE TABLE IF NOT EXISTS admission_quotas (
+    id BIGSERIAL PRIMARY KEY,
+    admission_period_id BIGINT NOT NULL REFERENCES admission_periods(id) ON DELETE CASCADE,
+    gender TEXT NOT NULL CHECK (gender IN ('male','female')),
+    program TEXT NOT NULL CHECK (program IN ('boarding','non_boarding')),
+    seats INT NOT NULL CHECK (seats >= 0),
+    updated_at BIGINT NOT NULL,
+    UNIQUE (admission_period_id, gender, program)
+);
+
+-- Table: registration_status_histories
+CREATE TABLE IF NOT EXISTS registration_status_histories (
+    id BIGSERIAL PRIMARY KEY,
+    registration_id BIGINT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    changed_by BIGINT,
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_registration_status_histories_registration_id ON registration_status_histories (registration_id);
+
+-- Selection tests: components (weighted), sessions, assignments and scores
+CREATE TABLE IF NOT EXISTS selection_components (
+    id BIGSERIAL PRIMARY KEY,
+    admission_period_id BIGINT NOT NULL REFERENCES admission_periods(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    weight DOUBLE PRECISION NOT NULL CHECK (weight > 0 AND weight <= 100),
+    max_score DOUBLE PRECISION NOT NULL CHECK (max_score > 0),
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    UNIQUE (admission_period_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS selection_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    admission_period_id BIGINT NOT NULL REFERENCES admission_periods(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    location TEXT NOT NULL,
+    capacity INT NOT NULL CHECK (capacity >= 1),
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_selection_sessions_admission_period_id ON selection_sessions (admission_period_id);
+
+CREATE TABLE IF NOT EXISTS selection_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES selection_sessions(id) ON DELETE CASCADE,
+    registration_id BIGINT NOT NULL UNIQUE REFERENCES registrations(id) ON DELETE CASCADE,
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_selection_assignments_session_id ON selection_assignments (session_id);
+
+CREATE TABLE IF NOT EXISTS selection_scores (
+    id BIGSERIAL PRIMARY KEY,
+    registration_id BIGINT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    component_id BIGINT NOT NULL REFERENCES selection_components(id),
+    score DOUBLE PRECISION NOT NULL CHECK (score >= 0),
+    scored_by BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    UNIQUE (registration_id, component_id)
+);
+
+-- Tracking codes (public status check, registration card) and the rejected status.
+-- Existing registrations get a random code; new ones are generated by the API (DA-XXXX-XXXX).
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS tracking_code TEXT;
+UPDATE registrations SET tracking_code = 'DA-' || upper(substr(md5(random()::text || id::text), 1, 8))
+    WHERE tracking_code IS NULL;
+ALTER TABLE registrations ALTER COLUMN tracking_code SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registrations_tracking_code ON registrations (tracking_code);
+ALTER TABLE registrations DROP CONSTRAINT IF EXISTS registrations_status_check;
+ALTER TABLE registrations ADD CONSTRAINT registrations_status_check
+    CHECK (status IN ('new','validate','process','done','waitlisted','withdrawn','rejected'));
+
+-- Table: letter_templates (decision letters; built-in defaults are used when a kind has no row)
+CREATE TABLE IF NOT EXISTS letter_templates (
+    kind TEXT PRIMARY KEY CHECK (kind IN ('acceptance','rejection')),
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    updated_by BIGINT NOT NULL DEFAULT 0,
+    updated_at BIGINT NOT NULL
+);
+
+-- Registration fee payments (online via PAYMENT_PROVIDER)
+ALTER TABLE admission_periods ADD COLUMN IF NOT EXISTS registration_fee BIGINT NOT NULL DEFAULT 0 CHECK (registration_fee >= 0);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id BIGSERIAL PRIMARY KEY,
+    registration_id BIGINT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    number TEXT NOT NULL UNIQUE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','paid','expired','failed')),
+    provider TEXT NOT NULL,
+    external_id TEXT,
+    payment_url TEXT,
+    expires_at BIGINT NOT NULL,
+    paid_at BIGINT,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_invoices_registration_id ON invoices (registration_id);
+
+-- Webhook events, unique per provider so a redelivered notification is applied once
+CREATE TABLE IF NOT EXISTS payment_events (
+    id BIGSERIAL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    invoice_id BIGINT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    payload JSONB,
+    created_at BIGINT NOT NULL,
+    UNIQUE (provider, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_payment_events_invoice_id ON payment_events (invoice_id);
+
+-- Bank transfer receipts, verified by an admin
+CREATE TABLE IF NOT EXISTS payment_proofs (
+    id BIGSERIAL PRIMARY KEY,
+    registration_id BIGINT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    object_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','approved','rejected')),
+    reject_reason TEXT,
+    invoice_id BIGINT REFERENCES invoices(id) ON DELETE SET NULL,
+    reviewed_by BIGINT,
+    reviewed_at BIGINT,
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_payment_proofs_registration_id ON payment_proofs (registration_id);
+CREATE INDEX IF NOT EXISTS idx_payment_proofs_status_created_at ON payment_proofs (status, created_at);
+
+-- Field edits of registrations (applicant edit links, admin edits)
+CREATE TABLE IF NOT EXISTS registration_changes (
+    id BIGSERIAL PRIMARY KEY,
+    registration_id BIGINT NOT NULL REFERENCES registrations(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    source TEXT NOT NULL CHECK (source IN ('applicant','admin')),
+    changed_by BIGINT,
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_registration_changes_registration_id ON registration_changes (registration_id);
+
+-- Optimistic locking of registration edits
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- Internal notes and assignments (registrations, contacts)
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS assigned_to BIGINT REFERENCES admins(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_registrations_assigned_to ON registrations (assigned_to);
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS assigned_to BIGINT REFERENCES admins(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_contacts_assigned_to ON contacts (assigned_to);
+
+CREATE TABLE IF NOT EXISTS admin_notes (
+    id BIGSERIAL PRIMARY KEY,
+    subject_type TEXT NOT NULL CHECK (subject_type IN ('registration','contact')),
+    subject_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_admin_notes_subject ON admin_notes (subject_type, subject_id);
+
+-- Article views per day (WIB), written in batches by the API
+CREATE TABLE IF NOT EXISTS article_views_daily (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, day)
+);
+CREATE INDEX IF NOT EXISTS idx_article_views_daily_day ON article_views_daily (day);
+
+-- Article slugs and translations (en, ar); the article row is the Indonesian (default) version
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug TEXT;
+UPDATE articles SET slug = 'article-' || id WHERE slug IS NULL OR slug = '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles (slug);
+
+CREATE TABLE IF NOT EXISTS article_translations (
+    id BIGSERIAL PRIMARY KEY,
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL CHECK (locale IN ('en','ar')),
+    title TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    content JSONB NOT NULL,
+    source_hash TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_translations_article_locale ON article_translations (article_id, locale);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_translations_slug ON article_translations (slug);
+
+-- Roles: named sets of permissions (admins.role refers to roles.name)
+CREATE TABLE IF NOT EXISTS roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description, system, created_at, updated_at) VALUES
+    ('superadmin', 'Every permission', TRUE, EXTRACT(EPOCH FROM now())::BIGINT, EXTRACT(EPOCH FROM now())::BIGINT),
+    ('admin', 'Day-to-day admissions, articles and contacts', TRUE, EXTRACT(EPOCH FROM now())::BIGINT, EXTRACT(EPOCH FROM now())::BIGINT)
+ON CONFLICT (name) DO NOTHING;
+
+-- superadmin always has every permission; its rows are informational
+INSERT INTO role_permissions (role, permission)
+SELECT r.role, p.permission
+FROM (VALUES ('superadmin'), ('admin')) AS r(role)
+CROSS JOIN (VALUES
+    ('dashboard.view'),
+    ('articles.view'), ('articles.write'), ('articles.publish'), ('articles.delete'),
+    ('registrations.view'), ('registrations.edit'), ('registrations.delete'), ('registrations.export'), ('registrations.import'),
+    ('payments.verify'), ('selection.manage'),
+    ('contacts.view'), ('contacts.reply'), ('contacts.delete')
+) AS p(permission)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('superadmin', 'notes.delete'),
+    ('superadmin', 'admission_periods.manage'),
+    ('superadmin', 'letter_templates.manage'),
+    ('superadmin', 'admins.manage'),
+    ('superadmin', 'roles.manage')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE admins DROP CONSTRAINT IF EXISTS admins_role_check;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'admins_role_fkey') THEN
+        ALTER TABLE admins ADD CONSTRAINT admins_role_fkey FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+    END IF;
+END $$;
+
+-- Admin two-factor authentication (TOTP); the secret is AES-GCM encrypted, recovery codes are SHA-256 hashes
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS mfa_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS admin_mfa (
+    admin_id BIGINT PRIMARY KEY REFERENCES admins(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at BIGINT NOT NULL DEFAULT 0,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS admin_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id BIGINT NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_admin_recovery_codes_admin_id ON admin_recovery_codes (admin_id);